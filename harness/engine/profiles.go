@@ -41,10 +41,13 @@ func Profile1(index int, node *DuskNode, walletPath string) {
 	viper.Set("mempool.poolType", "hashmap")
 	viper.Set("mempool.preallocTxs", "100")
 	viper.Set("mempool.maxInvItems", "10000")
+	viper.Set("mempool.persistFile", node.Dir+"/mempool.dat")
+	viper.Set("mempool.persistInterval", "60")
 
 	viper.Set("consensus.defaultlocktime", 1000)
 	viper.Set("consensus.defaultoffset", 10)
 	viper.Set("consensus.defaultamount", 50)
+	viper.Set("consensus.journalFile", node.Dir+"/consensus.wal")
 }
 
 // Profile2 builds dusk.toml with lite driver enabled (suitable for bench testing)
@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
@@ -25,6 +26,9 @@ const (
 func main() {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt)
+	// SIGHUP reloads the configs marked with #live#
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
 
 	fmt.Fprintln(os.Stdout, "initializing node...")
 	// Loading all node configurations. Fail-fast if critical error occurs
@@ -86,7 +90,16 @@ func main() {
 	// Wait until the interrupt signal is received from an OS signal or
 	// shutdown is requested through one of the subsystems such as the RPC
 	// server.
-	<-interrupt
+	for running := true; running; {
+		select {
+		case <-hangup:
+			if err := srv.ReloadConfig(); err != nil {
+				log.WithField("prefix", "main").WithError(err).Errorln("could not reload config")
+			}
+		case <-interrupt:
+			running = false
+		}
+	}
 
 	// Graceful shutdown of listening components
 	msg := message.New(topics.Quit, bytes.Buffer{})
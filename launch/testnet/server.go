@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"net"
+	"sync/atomic"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/gql"
 	"github.com/dusk-network/dusk-blockchain/pkg/rpc"
@@ -12,13 +13,17 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/candidate"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/equivocation"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/initiator"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/transactor"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/dupemap"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/processing/chainsync"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	log "github.com/sirupsen/logrus"
 
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
@@ -29,10 +34,16 @@ type Server struct {
 	eventBus   *eventbus.EventBus
 	rpcBus     *rpcbus.RPCBus
 	chain      *chain.Chain
+	mempool    *mempool.Mempool
 	dupeMap    *dupemap.DupeMap
 	counter    *chainsync.Counter
 	gossip     *processing.Gossip
 	rpcWrapper *rpc.RPCSrvWrapper
+	// gqlServer is nil if the GraphQL service is disabled
+	gqlServer *gql.Server
+
+	// peers is the amount of connected peers. It is accessed atomically
+	peers int32
 }
 
 // Setup creates a new EventBus, generates the BLS and the ED25519 Keys, launches a new `CommitteeStore`, launches the Blockchain process and inits the Stake and Blind Bid channels
@@ -59,6 +70,10 @@ func Setup() *Server {
 	candidateBroker := candidate.NewBroker(eventBus, rpcBus)
 	go candidateBroker.Listen()
 
+	// Setting up the equivocation detector
+	detector := equivocation.New(eventBus, nil)
+	go detector.Listen()
+
 	// Setting up a dupemap
 	dupeBlacklist := launchDupeMap(eventBus)
 
@@ -69,14 +84,15 @@ func Setup() *Server {
 	}
 
 	// Instantiate GraphQL server
+	var gqlServer *gql.Server
 	if cfg.Get().Gql.Enabled {
-		gqlServer, err := gql.NewHTTPServer(eventBus, rpcBus)
+		gqlServer, err = gql.NewHTTPServer(eventBus, rpcBus)
 		if err != nil {
 			log.Errorf("GraphQL http server error: %s", err.Error())
-		}
-
-		if err := gqlServer.Start(); err != nil {
+		} else if err := gqlServer.Start(); err != nil {
 			log.Errorf("GraphQL failed to start: %s", err.Error())
+			_ = gqlServer.Stop()
+			gqlServer = nil
 		}
 	}
 
@@ -85,19 +101,40 @@ func Setup() *Server {
 		eventBus:   eventBus,
 		rpcBus:     rpcBus,
 		chain:      chain,
+		mempool:    m,
 		dupeMap:    dupeBlacklist,
 		counter:    counter,
 		gossip:     processing.NewGossip(protocol.TestNet),
 		rpcWrapper: rpcWrapper,
+		gqlServer:  gqlServer,
 	}
 
-	// Setting up the transactor component
-	transactor, err := transactor.New(eventBus, rpcBus, nil, srv.counter, nil, nil, cfg.Get().General.WalletOnly)
+	peerCountChan := make(chan rpcbus.Request, 1)
+	if err := rpcBus.Register(topics.GetPeerCount, peerCountChan); err != nil {
+		log.WithError(err).Errorln("could not register the peer count")
+	}
+	go srv.servePeerCount(peerCountChan)
+
+	reloadChan := make(chan rpcbus.Request, 1)
+	if err := rpcBus.Register(topics.ReloadConfig, reloadChan); err != nil {
+		log.WithError(err).Errorln("could not register the config reload")
+	}
+	go srv.serveReloadConfig(reloadChan)
+
+	// Setting up the transactor component. Observers never start the
+	// consensus components, even with a wallet loaded
+	observer := cfg.Get().Consensus.Observer
+	transactor, err := transactor.New(eventBus, rpcBus, nil, srv.counter, nil, nil, cfg.Get().General.WalletOnly || observer)
 	if err != nil {
 		log.Panic(err)
 	}
 	go transactor.Listen()
 
+	// Following the consensus without taking part in it
+	if observer {
+		initiator.LaunchObserver(eventBus, rpcBus)
+	}
+
 	// Connecting to the log based monitoring system
 	if err := ConnectToLogMonitor(eventBus); err != nil {
 		log.Panic(err)
@@ -143,7 +180,7 @@ func (s *Server) OnAccept(conn net.Conn) {
 	go peerReader.ReadLoop()
 
 	peerWriter := peer.NewWriter(conn, s.gossip, s.eventBus)
-	go peerWriter.Serve(writeQueueChan, exitChan)
+	go s.serve(peerWriter, writeQueueChan, exitChan)
 }
 
 // OnConnection is the callback for writing to the peers
@@ -170,12 +207,57 @@ func (s *Server) OnConnection(conn net.Conn, addr string) {
 	}
 
 	go peerReader.ReadLoop()
-	go peerWriter.Serve(writeQueueChan, exitChan)
+	go s.serve(peerWriter, writeQueueChan, exitChan)
+}
+
+// serve runs the writer of a peer, keeping track of the connected peers
+func (s *Server) serve(w *peer.Writer, writeQueueChan <-chan *bytes.Buffer, exitChan chan struct{}) {
+	atomic.AddInt32(&s.peers, 1)
+	defer atomic.AddInt32(&s.peers, -1)
+	w.Serve(writeQueueChan, exitChan)
+}
+
+// servePeerCount answers the requests for the amount of connected peers
+func (s *Server) servePeerCount(reqChan <-chan rpcbus.Request) {
+	for r := range reqChan {
+		r.RespChan <- rpcbus.Response{Resp: int(atomic.LoadInt32(&s.peers)), Err: nil}
+	}
+}
+
+// ReloadConfig reads the config file again, and lets the components apply
+// the configs marked with #live#
+func (s *Server) ReloadConfig() error {
+	if err := cfg.Reload(); err != nil {
+		return err
+	}
+
+	log.WithField("file", cfg.Get().UsedConfigFile).Infoln("config reloaded")
+	msg := message.New(topics.ConfigReloaded, bytes.Buffer{})
+	s.eventBus.Publish(topics.ConfigReloaded, msg)
+	return nil
+}
+
+// serveReloadConfig answers the requests of the in-process components to
+// reload the config. The operators reload it with SIGHUP, as the gRPC service
+// has no endpoint for it
+func (s *Server) serveReloadConfig(reqChan <-chan rpcbus.Request) {
+	for r := range reqChan {
+		r.RespChan <- rpcbus.Response{Resp: nil, Err: s.ReloadConfig()}
+	}
 }
 
 // Close the chain and the connections created through the RPC bus
 func (s *Server) Close() {
 	// TODO: disconnect peers
+	// The GraphQL server drains its in-flight queries and websocket clients
+	// first, as they rely on the other components
+	if s.gqlServer != nil {
+		if err := s.gqlServer.Stop(); err != nil {
+			log.WithError(err).Warnln("GraphQL server did not shut down gracefully")
+		}
+	}
+
+	s.mempool.Quit()
 	s.chain.Close()
 	s.rpcBus.Close()
 	s.rpcWrapper.Shutdown()
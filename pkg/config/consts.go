@@ -20,6 +20,9 @@ const (
 	// ConsensusTimeOut is the time out for consensus step timers.
 	ConsensusTimeOut = 5 * time.Second
 
+	// ConsensusMaxTimeOut caps the growth of the consensus step timers
+	ConsensusMaxTimeOut = 60 * time.Second
+
 	MinFee = int64(100)
 
 	// GenesisBlockBlob represents the genesis block bytes in hexadecimal format
@@ -80,12 +80,76 @@ type gqlConfiguration struct {
 
 	MaxRequestLimit uint
 
+	// MaxQueryDepth is the maximum nesting of the fields of a query. 0
+	// disables the limit
+	MaxQueryDepth uint
+	// MaxQueryComplexity is the maximum cost of a query, each field costing
+	// 1 for every item it is resolved for. 0 disables the limit
+	MaxQueryComplexity uint
+	// QueryTimeout is the maximum time (in seconds) a query can take. 0
+	// disables the timeout
+	QueryTimeout uint
+
+	// PersistedQueries is a JSON file mapping the hex-encoded SHA-256 hashes
+	// of queries to the queries. Empty value disables persisted queries
+	PersistedQueries string
+	// OnlyPersistedQueries rejects the queries which are not persisted
+	OnlyPersistedQueries bool
+
+	// AllowedOrigins are the origins allowed to send cross-origin requests
+	// and to open websockets. "*" allows all origins
+	AllowedOrigins []string
+
+	Auth         gqlAuthConfiguration
 	Notification notificationConfiguration
 }
 
+// setLive copies the #live# configs, which the running server applies on
+// reload
+func (c *gqlConfiguration) setLive(from gqlConfiguration) {
+	c.CertFile, c.KeyFile = from.CertFile, from.KeyFile
+	c.MaxRequestLimit = from.MaxRequestLimit
+	c.MaxQueryDepth = from.MaxQueryDepth
+	c.MaxQueryComplexity = from.MaxQueryComplexity
+	c.QueryTimeout = from.QueryTimeout
+	c.PersistedQueries = from.PersistedQueries
+	c.OnlyPersistedQueries = from.OnlyPersistedQueries
+	c.AllowedOrigins = from.AllowedOrigins
+	c.Auth = from.Auth
+	c.Notification = from.Notification
+}
+
+type gqlAuthConfiguration struct {
+	// RequireKey rejects the requests which do not come with an API key
+	RequireKey bool
+	// AnonymousScopes are the scopes of the requests without an API key.
+	// Empty value grants the "chain" and "mempool" scopes
+	AnonymousScopes []string
+
+	Keys []apiKeyConfiguration
+}
+
+type apiKeyConfiguration struct {
+	Name string
+	Key  string
+	// Scopes are any of "chain", "mempool" and "wallet"
+	Scopes []string
+	// MaxRequestLimit is the maximum requests per second of the key. 0
+	// falls back to gql.maxRequestLimit
+	MaxRequestLimit uint
+}
+
 type notificationConfiguration struct {
 	BrokersNum       uint
 	ClientsPerBroker uint
+	// QueueSize is the number of notifications buffered for each client
+	QueueSize uint
+	// SlowConsumerPolicy is applied to the clients whose queue is full. One
+	// of disconnect, dropoldest and dropnewest
+	SlowConsumerPolicy string
+	// MaxReplayBlocks is the maximum number of blocks replayed to a client
+	// resuming its notifications
+	MaxReplayBlocks uint
 }
 
 // Performance parameters
@@ -98,9 +162,42 @@ type mempoolConfiguration struct {
 	PoolType    string
 	PreallocTxs uint32
 	MaxInvItems uint32
+
+	// PersistFile is the file where verified txs are dumped to survive a
+	// node restart. Empty value disables persistence
+	PersistFile string
+	// PersistInterval is the period (in seconds) of dumping verified txs
+	PersistInterval uint
+
+	// RebroadcastBlocks is the number of blocks a locally-submitted tx can
+	// stay unconfirmed before being re-advertised. 0 disables rebroadcasting
+	RebroadcastBlocks uint64
+	// MaxRebroadcastBlocks caps the exponential back-off between two
+	// re-advertisements
+	MaxRebroadcastBlocks uint64
+
+	// ReplaceByFee allows a tx to replace the verified txs spending the same
+	// key images if it pays a higher fee
+	ReplaceByFee bool
+	// MinFeeBump is the minimum increase (in percents) of both the absolute
+	// fee and the fee rate required for a replacement
+	MinFeeBump uint
 }
 
 type consensusConfiguration struct {
 	DefaultLockTime uint64
 	DefaultAmount   uint64
+	// JournalFile is the write-ahead log of the signed votes. If empty, votes
+	// are only journaled in memory
+	JournalFile string
+	// Faults lists the misbehaviours injected in the consensus messages
+	// gossiped by the node. It is meant for testing only, and is rejected by
+	// the builds without the faults tag
+	Faults []string
+	// Components lists the names of the consensus components to start. If
+	// empty, the default components are started
+	Components []string
+	// Observer makes the node follow the consensus without taking part in
+	// it, regardless of the wallet being loaded
+	Observer bool
 }
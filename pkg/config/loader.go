@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -20,6 +22,8 @@ const (
 
 var (
 	r *Registry
+	// lock guards r, as it is replaced on Reload
+	lock sync.RWMutex
 )
 
 type Base struct {
@@ -71,6 +75,9 @@ type Registry struct {
 // properties config files
 func Load(configFileName string, secondary interface{}, customflags func() (string, error)) error {
 
+	lock.Lock()
+	defer lock.Unlock()
+
 	r = new(Registry)
 	r.ConfigFileName = configFileName
 
@@ -105,9 +112,35 @@ func LoadFromFile(configPath string) (Registry, error) {
 // Get returns registry by value in order to avoid further modifications after
 // initial configuration loading
 func Get() Registry {
+	lock.RLock()
+	defer lock.RUnlock()
 	return *r
 }
 
+// Reload reads the loaded config file again, so that the configs marked with
+// #live# can be applied without node restart. Only the #live# configs are
+// updated, as the other ones are read once by the components at startup. The
+// flags and the ENV variables keep overwriting the config file. The Registry
+// is left untouched on error
+func Reload() error {
+	current := Get()
+	if len(current.UsedConfigFile) == 0 {
+		return errors.New("no config file loaded")
+	}
+
+	registry := new(Registry)
+	registry.ConfigFileName = current.ConfigFileName
+	registry.FixedConfigFile = current.UsedConfigFile
+	if err := registry.init(nil); err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	r.Gql.setLive(registry.Gql)
+	return nil
+}
+
 func (r *Registry) init(secondary interface{}) error {
 
 	// Make an attempt to find dusk.toml/dusk.json/dusk.yaml in any of the
@@ -225,6 +258,8 @@ func defineENV() {
 // Mock should be used only in test packages. It could be useful when a unit
 // test needs to be rerun with configs different from the default ones.
 func Mock(m *Registry) {
+	lock.Lock()
+	defer lock.Unlock()
 	r = m
 }
 
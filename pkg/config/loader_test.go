@@ -1,7 +1,9 @@
 package config
 
 import (
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/spf13/pflag"
@@ -128,6 +130,80 @@ func TestReadOnly(t *testing.T) {
 	}
 }
 
+func TestReload(t *testing.T) {
+
+	Reset()
+
+	// A copy of default.dusk.toml to be modified
+	content, err := ioutil.ReadFile("./samples/default.dusk.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ioutil.TempFile("", "dusk*.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := ioutil.WriteFile(file.Name(), content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Mock command line arguments
+	os.Args = append(os.Args, "--config="+file.Name(), "--logger.level=custom")
+
+	if err := Load("dusk", nil, nil); err != nil {
+		t.Fatalf("Failed parse: %v", err)
+	}
+
+	if Get().Gql.MaxRequestLimit != 20 {
+		t.Fatalf("Invalid gql maxRequestLimit %d", Get().Gql.MaxRequestLimit)
+	}
+
+	modified := strings.Replace(string(content), "maxRequestLimit = 20", "maxRequestLimit = 50", 1)
+	modified = strings.Replace(modified, "maxSizeMB = 100", "maxSizeMB = 200", 1)
+	modified = strings.Replace(modified, `address="127.0.0.1:9001"`, `address="127.0.0.1:9002"`, 1)
+	if err := ioutil.WriteFile(file.Name(), []byte(modified), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Reload(); err != nil {
+		t.Fatalf("Failed reload: %v", err)
+	}
+
+	if Get().Gql.MaxRequestLimit != 50 {
+		t.Errorf("Invalid gql maxRequestLimit %d", Get().Gql.MaxRequestLimit)
+	}
+
+	// The configs which are not #live# keep their value
+	if Get().Mempool.MaxSizeMB != 100 {
+		t.Errorf("Invalid mempool maxSizeMB %d", Get().Mempool.MaxSizeMB)
+	}
+
+	if Get().Gql.Address != "127.0.0.1:9001" {
+		t.Errorf("Invalid gql address %s", Get().Gql.Address)
+	}
+
+	// Flags still overwrite the config file
+	if Get().Logger.Level != "custom" {
+		t.Errorf("Invalid logger level %s", Get().Logger.Level)
+	}
+
+	// A broken config file leaves the registry untouched
+	if err := ioutil.WriteFile(file.Name(), []byte("[gql"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Reload(); err == nil {
+		t.Error("Reload of an invalid config file succeeded")
+	}
+
+	if Get().Gql.MaxRequestLimit != 50 {
+		t.Errorf("Invalid gql maxRequestLimit %d", Get().Gql.MaxRequestLimit)
+	}
+}
+
 func TestSecondaryRegistry(t *testing.T) {
 
 	Reset()
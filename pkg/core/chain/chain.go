@@ -9,6 +9,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/bwesterb/go-ristretto"
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
@@ -65,7 +66,7 @@ type Chain struct {
 
 	// The highest block we've seen from the network. This is updated
 	// by the synchronizer, and used to calculate our synchronization
-	// progress.
+	// progress. It is accessed atomically.
 	highestSeen uint64
 
 	// collector channels
@@ -157,7 +158,7 @@ func (c *Chain) Listen() {
 		case certMsg := <-c.certificateChan:
 			c.handleCertificateMessage(certMsg)
 		case height := <-c.highestSeenChan:
-			c.highestSeen = height
+			c.updateHighestSeen(height)
 		case r := <-c.getLastBlockChan:
 			c.provideLastBlock(r)
 		case r := <-c.verifyCandidateBlockChan:
@@ -221,6 +222,15 @@ func (c *Chain) onAcceptBlock(m message.Message) error {
 	// Accept the block
 	blk := m.Payload().(block.Block)
 
+	// If the network accepted a different block at the height of our
+	// intermediate block, the latter is reverted. Its txs are handed back to
+	// the mempool.
+	if c.intermediateBlock != nil && c.intermediateBlock.Header.Height == blk.Header.Height &&
+		!bytes.Equal(c.intermediateBlock.Header.Hash, blk.Header.Hash) {
+		msg := message.New(topics.DisconnectedBlock, *c.intermediateBlock)
+		c.eventBus.Publish(topics.DisconnectedBlock, msg)
+	}
+
 	// This will decrement the sync counter
 	if err := c.AcceptBlock(blk); err != nil {
 		return err
@@ -321,6 +331,7 @@ func (c *Chain) AcceptBlock(blk block.Block) error {
 
 	msg := message.New(topics.AcceptedBlock, blk)
 	c.eventBus.Publish(topics.AcceptedBlock, msg)
+	c.publishSyncProgress(blk.Header.Height)
 
 	l.Trace("procedure ended")
 	return nil
@@ -700,16 +711,22 @@ func (c *Chain) provideRoundResults(r rpcbus.Request) {
 }
 
 func (c *Chain) provideSyncProgress(r rpcbus.Request) {
-	if c.highestSeen == 0 {
-		r.RespChan <- rpcbus.Response{&node.SyncProgressResponse{Progress: 0}, nil}
-		return
-	}
-
 	c.mu.RLock()
 	prevBlockHeight := c.prevBlock.Header.Height
 	c.mu.RUnlock()
 
-	progressPercentage := (float64(prevBlockHeight) / float64(c.highestSeen)) * 100
+	r.RespChan <- rpcbus.Response{&node.SyncProgressResponse{Progress: c.syncProgress(prevBlockHeight)}, nil}
+}
+
+// syncProgress returns the percentage of the highest block seen from the
+// network which a chain at the given height represents
+func (c *Chain) syncProgress(height uint64) float32 {
+	highestSeen := atomic.LoadUint64(&c.highestSeen)
+	if highestSeen == 0 {
+		return 0
+	}
+
+	progressPercentage := (float64(height) / float64(highestSeen)) * 100
 
 	// Avoiding strange output when the chain can be ahead of the highest
 	// seen block, as in most cases, consensus terminates before we see
@@ -718,7 +735,28 @@ func (c *Chain) provideSyncProgress(r rpcbus.Request) {
 		progressPercentage = 100
 	}
 
-	r.RespChan <- rpcbus.Response{&node.SyncProgressResponse{Progress: float32(progressPercentage)}, nil}
+	return float32(progressPercentage)
+}
+
+// updateHighestSeen records the highest block seen from the network, and
+// notifies the resulting synchronization progress if it changed
+func (c *Chain) updateHighestSeen(height uint64) {
+	if atomic.SwapUint64(&c.highestSeen, height) == height {
+		return
+	}
+
+	c.mu.RLock()
+	prevBlockHeight := c.prevBlock.Header.Height
+	c.mu.RUnlock()
+
+	c.publishSyncProgress(prevBlockHeight)
+}
+
+// publishSyncProgress notifies the synchronization progress of a chain at the
+// given height under the SyncProgress topic
+func (c *Chain) publishSyncProgress(height uint64) {
+	msg := message.New(topics.SyncProgress, c.syncProgress(height))
+	c.eventBus.Publish(topics.SyncProgress, msg)
 }
 
 // mocks an intermediate block with a coinbase attributed to a standard
@@ -61,6 +61,12 @@ func (a *agreement) ID() uint32 {
 	return a.agreementID
 }
 
+// Consumes the Agreement topic.
+// Implements consensus.Consumer.
+func (a *agreement) Consumes() []topics.Topic {
+	return []topics.Topic{topics.Agreement}
+}
+
 // Filter an incoming Agreement message, by checking whether it was sent by a valid
 // member of the voting committee for the given round and step.
 func (a *agreement) Filter(hdr header.Header) bool {
@@ -86,6 +92,7 @@ func (a *agreement) listen() {
 	select {
 	case evs := <-a.accumulator.CollectedVotesChan:
 		lg.WithField("id", a.agreementID).Debugln("quorum reached")
+		a.eventPlayer.Trace(consensus.TraceAgreementQuorum, evs[0].State().BlockHash)
 		// Start a goroutine here to release the lock held by
 		// Coordinator.CollectEvent
 		// Send the Agreement to the Certificate Collector within the Chain
@@ -96,6 +103,7 @@ func (a *agreement) listen() {
 
 func (a *agreement) sendCertificate(ag message.Agreement) {
 	msg := message.New(topics.Agreement, ag)
+	a.eventPlayer.Trace(consensus.TraceCertificate, ag.State().BlockHash)
 	a.publisher.Publish(topics.Certificate, msg)
 }
 
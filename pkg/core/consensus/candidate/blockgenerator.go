@@ -71,6 +71,12 @@ func (bg *Generator) ID() uint32 {
 	return bg.scoreEventID
 }
 
+// Consumes the ScoreEvent topic.
+// Implements consensus.Consumer.
+func (bg *Generator) Consumes() []topics.Topic {
+	return []topics.Topic{topics.ScoreEvent}
+}
+
 // Finalize implements consensus.Component
 func (bg *Generator) Finalize() {}
 
@@ -0,0 +1,34 @@
+package consensus
+
+import "time"
+
+type (
+	// Clock is the source of time of the consensus timers. It allows to run
+	// the consensus against a virtual time, as done by the simulator
+	Clock interface {
+		// Now returns the current time
+		Now() time.Time
+		// AfterFunc calls f once the duration has elapsed
+		AfterFunc(time.Duration, func()) Alarm
+	}
+
+	// Alarm is a pending call scheduled through a Clock
+	Alarm interface {
+		// Stop prevents the call from firing. It returns false if the call
+		// already fired or was stopped
+		Stop() bool
+	}
+
+	systemClock struct{}
+)
+
+// SystemClock is the Clock based on the wall clock time
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Alarm {
+	return time.AfterFunc(d, f)
+}
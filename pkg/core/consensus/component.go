@@ -52,6 +52,9 @@ type EventPlayer interface {
 	Pause(uint32)
 	// Play resumes the Event forwarding for a Listener with the given ID.
 	Play(uint32)
+	// Trace records an event of the consensus loop, related to the given
+	// block hash, in the trace of the current round and step.
+	Trace(TraceKind, []byte)
 }
 
 // ComponentFactory holds the data to create a Component (i.e. Signer, EventPublisher, RPCBus). Its responsibility is to recreate it on demand
@@ -0,0 +1,111 @@
+package consensus
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// Dependencies declares the topics a consensus component sends internally
+// and the topics it listens to
+type Dependencies struct {
+	// Name of the component, used in the validation errors
+	Name     string
+	Produces []topics.Topic
+	Consumes []topics.Topic
+}
+
+// externalTopics are the topics consumed by the components which do not need
+// a producing component: Generation is dispatched by the Coordinator at the
+// beginning of every round, and the others are gossiped by the network
+var externalTopics = []topics.Topic{
+	topics.Generation,
+	topics.Candidate,
+	topics.Score,
+	topics.Reduction,
+	topics.Agreement,
+}
+
+// Producer is implemented by the Components sending events internally. The
+// Coordinator refuses to send the topics a Component does not declare
+type Producer interface {
+	// Produces returns the topics sent through Signer.SendInternally
+	Produces() []topics.Topic
+}
+
+// Consumer is implemented by the Components subscribing to topics. The
+// Coordinator refuses the subscriptions a Component does not declare
+type Consumer interface {
+	// Consumes returns the topics of the TopicListeners returned by
+	// Initialize
+	Consumes() []topics.Topic
+}
+
+// dependenciesOf collects the Dependencies declared by a Component. It is named
+// after its type, unless it implements fmt.Stringer
+func dependenciesOf(component Component) Dependencies {
+	d := Dependencies{Name: strings.TrimPrefix(fmt.Sprintf("%T", component), "*")}
+	if s, ok := component.(fmt.Stringer); ok {
+		d.Name = s.String()
+	}
+
+	if c, ok := component.(Consumer); ok {
+		d.Consumes = c.Consumes()
+	}
+
+	if p, ok := component.(Producer); ok {
+		d.Produces = p.Produces()
+	}
+
+	return d
+}
+
+// checkSubscriptions returns an error if a component subscribes to a topic it
+// does not declare to consume
+func checkSubscriptions(d Dependencies, subs []TopicListener) error {
+	consumed := make(map[topics.Topic]bool, len(d.Consumes))
+	for _, topic := range d.Consumes {
+		consumed[topic] = true
+	}
+
+	for _, sub := range subs {
+		if !consumed[sub.Topic] {
+			return fmt.Errorf("consensus component %q subscribes to topic %s, which it does not declare to consume", d.Name, sub.Topic)
+		}
+	}
+
+	return nil
+}
+
+// ValidateDependencies checks that every topic consumed by a component is
+// either produced by another component, dispatched by the Coordinator or
+// gossiped by the network. The Coordinator calls it when it is started
+func ValidateDependencies(deps ...Dependencies) error {
+	produced := make(map[topics.Topic]bool)
+	for _, topic := range externalTopics {
+		produced[topic] = true
+	}
+
+	names := make(map[string]bool)
+	for _, d := range deps {
+		if names[d.Name] {
+			return fmt.Errorf("consensus component %q is enabled twice", d.Name)
+		}
+		names[d.Name] = true
+
+		for _, topic := range d.Produces {
+			produced[topic] = true
+		}
+	}
+
+	for _, d := range deps {
+		for _, topic := range d.Consumes {
+			if !produced[topic] {
+				return fmt.Errorf("consensus component %q consumes topic %s, which no enabled component produces", d.Name, topic)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	selection := Dependencies{Name: "selection", Produces: []topics.Topic{topics.BestScore}, Consumes: []topics.Topic{topics.Generation, topics.Score}}
+	firstStep := Dependencies{Name: "firststep", Produces: []topics.Topic{topics.StepVotes}, Consumes: []topics.Topic{topics.BestScore, topics.Reduction}}
+
+	assert.NoError(t, ValidateDependencies(selection, firstStep))
+
+	// nothing produces the BestScore
+	assert.Error(t, ValidateDependencies(firstStep))
+
+	// the same component can not be enabled twice
+	assert.Error(t, ValidateDependencies(selection, firstStep, selection))
+}
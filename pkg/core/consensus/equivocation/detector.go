@@ -0,0 +1,217 @@
+package equivocation
+
+import (
+	"bytes"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "equivocation")
+
+// maxStep is the last step whose votes are recorded. A round lasting longer
+// is stalled anyway, and the bound caps the votes kept for each provisioner
+const maxStep = 96
+
+type (
+	// voteKey identifies the single vote a provisioner is allowed to cast
+	// for each kind of consensus message in a given round and step. The
+	// equivocations are identified by the key without the category, as the
+	// vote signatures do not cover it
+	voteKey struct {
+		category  topics.Topic
+		round     uint64
+		step      uint8
+		pubKeyBLS string
+	}
+
+	vote struct {
+		hdr header.Header
+		sig []byte
+	}
+
+	// Detector watches the Reduction and Agreement messages received from the
+	// network and detects provisioners signing two different block hashes for
+	// the same round and step. Evidences of such equivocations are persisted
+	// and gossiped, so that they can be used for slashing the misbehaving
+	// stakes. Evidences gossiped by other nodes are verified and persisted as
+	// well.
+	Detector struct {
+		broker    eventbus.Broker
+		db        database.DB
+		roundChan <-chan consensus.RoundUpdate
+		voteChan  chan message.Message
+
+		round uint64
+		p     user.Provisioners
+
+		// votes holds the first valid vote seen from each provisioner in the
+		// current and next rounds
+		votes map[voteKey]vote
+		// reported keeps track of the equivocations already persisted and
+		// gossiped, regardless of their category
+		reported map[voteKey]bool
+	}
+)
+
+// New creates a Detector. If db is nil, the default database is used
+func New(broker eventbus.Broker, db database.DB) *Detector {
+	if db == nil {
+		_, db = heavy.CreateDBConnection()
+	}
+
+	d := &Detector{
+		broker:    broker,
+		db:        db,
+		roundChan: consensus.InitRoundUpdate(broker),
+		voteChan:  make(chan message.Message, 1000),
+		votes:     make(map[voteKey]vote),
+		reported:  make(map[voteKey]bool),
+	}
+
+	l := eventbus.NewChanListener(d.voteChan)
+	broker.Subscribe(topics.Reduction, l)
+	broker.Subscribe(topics.Agreement, l)
+	broker.Subscribe(topics.Evidence, l)
+	return d
+}
+
+// Listen for consensus messages and round updates. It is meant to be run in
+// its own goroutine
+func (d *Detector) Listen() {
+	for {
+		select {
+		case r := <-d.roundChan:
+			d.onRoundUpdate(r)
+		case m := <-d.voteChan:
+			if err := d.collect(m); err != nil {
+				lg.WithError(err).Warnln("could not process consensus message")
+			}
+		}
+	}
+}
+
+// onRoundUpdate discards the votes out of the window of the new round
+func (d *Detector) onRoundUpdate(r consensus.RoundUpdate) {
+	d.round = r.Round
+	d.p = r.P
+
+	for k := range d.votes {
+		if !d.watched(k.round, k.step) {
+			delete(d.votes, k)
+		}
+	}
+
+	for k := range d.reported {
+		if !d.watched(k.round, k.step) {
+			delete(d.reported, k)
+		}
+	}
+}
+
+// watched tells if the votes of a round and step are checked. Only the
+// current and the next rounds are watched, up to maxStep
+func (d *Detector) watched(round uint64, step uint8) bool {
+	return round >= d.round && round <= d.round+1 && step <= maxStep
+}
+
+func (d *Detector) collect(m message.Message) error {
+	switch p := m.Payload().(type) {
+	case message.Reduction:
+		return d.check(topics.Reduction, p.State(), p.SignedHash)
+	case message.Agreement:
+		return d.check(topics.Agreement, p.State(), p.SignedVotes())
+	case message.Evidence:
+		return d.onEvidence(p)
+	}
+
+	return nil
+}
+
+// check compares a vote with the first one seen from the same provisioner
+// for the same round and step. Only the valid votes are recorded, so that a
+// forged vote can not shield the provisioner. The repeated votes are not
+// verified again
+func (d *Detector) check(category topics.Topic, hdr header.Header, sig []byte) error {
+	if !d.watched(hdr.Round, hdr.Step) || d.p.GetMember(hdr.PubKeyBLS) == nil {
+		return nil
+	}
+
+	k := voteKey{category, hdr.Round, hdr.Step, string(hdr.PubKeyBLS)}
+	first, ok := d.votes[k]
+	if ok && (bytes.Equal(first.hdr.BlockHash, hdr.BlockHash) || d.reported[k.equivocation()]) {
+		return nil
+	}
+
+	if err := message.VerifyVote(hdr, sig); err != nil {
+		return err
+	}
+
+	if !ok {
+		d.votes[k] = vote{hdr, sig}
+		return nil
+	}
+
+	return d.report(k, message.NewEvidence(category, first.hdr, first.sig, hdr, sig))
+}
+
+// onEvidence verifies an evidence gossiped by another node
+func (d *Detector) onEvidence(e message.Evidence) error {
+	hdr := e.State()
+	if !d.watched(hdr.Round, hdr.Step) || d.p.GetMember(hdr.PubKeyBLS) == nil {
+		return nil
+	}
+
+	k := voteKey{e.Category, hdr.Round, hdr.Step, string(hdr.PubKeyBLS)}
+	if d.reported[k.equivocation()] {
+		return nil
+	}
+
+	if err := e.Verify(); err != nil {
+		return err
+	}
+
+	return d.report(k, e)
+}
+
+// report persists the evidence and gossips it to the network
+func (d *Detector) report(k voteKey, e message.Evidence) error {
+	d.reported[k.equivocation()] = true
+
+	lg.WithField("evidence", e.String()).Warnln("provisioner equivocation detected")
+
+	buf := new(bytes.Buffer)
+	if err := message.MarshalEvidence(buf, e); err != nil {
+		return err
+	}
+
+	hdr := e.State()
+	err := d.db.Update(func(t database.Transaction) error {
+		return t.StoreEvidence(hdr.Round, hdr.Step, hdr.PubKeyBLS, buf.Bytes())
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := topics.Prepend(buf, topics.Evidence); err != nil {
+		return err
+	}
+
+	d.broker.Publish(topics.Gossip, message.New(topics.Evidence, *buf))
+	return nil
+}
+
+// equivocation returns the key of the equivocations of a vote. It leaves the
+// category out, so that an evidence relabeled with another category is not
+// reported again
+func (k voteKey) equivocation() voteKey {
+	return voteKey{round: k.round, step: k.step, pubKeyBLS: k.pubKeyBLS}
+}
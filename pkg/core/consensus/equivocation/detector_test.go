@@ -0,0 +1,148 @@
+package equivocation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that two Reduction votes for different hashes in the same round and
+// step produce a verifiable evidence, which gets persisted and gossiped
+func TestDetectEquivocation(t *testing.T) {
+	bus := eventbus.New()
+	gossipChan := make(chan message.Message, 1)
+	bus.Subscribe(topics.Gossip, eventbus.NewChanListener(gossipChan))
+
+	_, db := lite.CreateDBConnection()
+	d := New(bus, db)
+
+	p, keys := consensus.MockProvisioners(5)
+	d.onRoundUpdate(consensus.MockRoundUpdate(10, p, nil))
+
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+
+	// the same vote twice is no equivocation
+	red := message.MockReduction(hash1, 10, 2, keys)
+	assert.NoError(t, d.collect(message.New(topics.Reduction, red)))
+	assert.NoError(t, d.collect(message.New(topics.Reduction, red)))
+	assert.Empty(t, gossipChan)
+
+	// the same provisioner voting for a different hash in the next step is
+	// legit as well
+	assert.NoError(t, d.collect(message.New(topics.Reduction, message.MockReduction(hash2, 10, 3, keys))))
+	assert.Empty(t, gossipChan)
+
+	red2 := message.MockReduction(hash2, 10, 2, keys)
+	assert.NoError(t, d.collect(message.New(topics.Reduction, red2)))
+
+	m := <-gossipChan
+	buf := m.Payload().(bytes.Buffer)
+	gossiped, err := message.Unmarshal(&buf)
+	assert.NoError(t, err)
+
+	e := gossiped.Payload().(message.Evidence)
+	assert.NoError(t, e.Verify())
+	assert.Equal(t, topics.Reduction, e.Category)
+	assert.Equal(t, keys[0].BLSPubKeyBytes, e.Sender())
+
+	assert.NoError(t, db.View(func(tx database.Transaction) error {
+		evidences, err := tx.FetchEvidence(10)
+		if err != nil {
+			return err
+		}
+
+		assert.Equal(t, 1, len(evidences))
+		return nil
+	}))
+
+	// the equivocation is reported once
+	red3 := message.MockReduction(hash1, 10, 2, keys)
+	assert.NoError(t, d.collect(message.New(topics.Reduction, red3)))
+	assert.Empty(t, gossipChan)
+}
+
+// Test that votes from non-provisioners and evidences with a forged signature
+// are discarded
+func TestDiscardInvalid(t *testing.T) {
+	bus := eventbus.New()
+	gossipChan := make(chan message.Message, 1)
+	bus.Subscribe(topics.Gossip, eventbus.NewChanListener(gossipChan))
+
+	_, db := lite.CreateDBConnection()
+	d := New(bus, db)
+
+	p, keys := consensus.MockProvisioners(5)
+	_, outsiders := consensus.MockProvisioners(1)
+	d.onRoundUpdate(consensus.MockRoundUpdate(10, p, nil))
+
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+
+	// not a provisioner
+	assert.NoError(t, d.collect(message.New(topics.Reduction, message.MockReduction(hash1, 10, 2, outsiders))))
+	assert.NoError(t, d.collect(message.New(topics.Reduction, message.MockReduction(hash2, 10, 2, outsiders))))
+	assert.Empty(t, gossipChan)
+
+	// forged signature
+	r1 := message.MockReduction(hash1, 10, 2, keys)
+	r2 := message.MockReduction(hash2, 10, 2, keys, 1)
+	e := message.NewEvidence(topics.Reduction, r1.State(), r1.SignedHash, r1.State(), r2.SignedHash)
+	e.Second.BlockHash = hash2
+	assert.Error(t, e.Verify())
+	assert.Error(t, d.collect(message.New(topics.Evidence, e)))
+	assert.Empty(t, gossipChan)
+}
+
+// Test that only the votes of the current and next rounds are watched, that a
+// forged first vote does not shield the provisioner and that an evidence
+// relabeled with another category is not reported again
+func TestWatchedVotes(t *testing.T) {
+	bus := eventbus.New()
+	gossipChan := make(chan message.Message, 2)
+	bus.Subscribe(topics.Gossip, eventbus.NewChanListener(gossipChan))
+
+	_, db := lite.CreateDBConnection()
+	d := New(bus, db)
+
+	p, keys := consensus.MockProvisioners(5)
+	d.onRoundUpdate(consensus.MockRoundUpdate(10, p, nil))
+
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+
+	for _, round := range []uint64{9, 12} {
+		assert.NoError(t, d.collect(message.New(topics.Reduction, message.MockReduction(hash1, round, 2, keys))))
+	}
+	assert.NoError(t, d.collect(message.New(topics.Reduction, message.MockReduction(hash1, 10, maxStep+1, keys))))
+	assert.Empty(t, d.votes)
+
+	// a forged vote is not recorded
+	forged := message.MockReduction(hash1, 11, 2, keys)
+	forged.SignedHash = message.MockReduction(hash2, 11, 2, keys).SignedHash
+	assert.Error(t, d.collect(message.New(topics.Reduction, forged)))
+	assert.Empty(t, d.votes)
+
+	red1 := message.MockReduction(hash1, 11, 2, keys)
+	red2 := message.MockReduction(hash2, 11, 2, keys)
+	assert.NoError(t, d.collect(message.New(topics.Reduction, red1)))
+	assert.NoError(t, d.collect(message.New(topics.Reduction, red2)))
+	<-gossipChan
+
+	e := message.NewEvidence(topics.Agreement, red1.State(), red1.SignedHash, red2.State(), red2.SignedHash)
+	assert.NoError(t, d.collect(message.New(topics.Evidence, e)))
+	assert.Empty(t, gossipChan)
+
+	// the votes of the past rounds are discarded
+	d.onRoundUpdate(consensus.MockRoundUpdate(12, p, nil))
+	assert.Empty(t, d.votes)
+	assert.Empty(t, d.reported)
+}
@@ -3,14 +3,10 @@ package factory
 import (
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/candidate"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/generation"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/generation/score"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction/firststep"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction/secondstep"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/selection"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/dusk-network/dusk-wallet/v2/key"
@@ -46,14 +42,93 @@ func New(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, timerLength time.Du
 // start the consensus components.
 func (c *ConsensusFactory) StartConsensus() {
 	log.WithField("process", "factory").Info("Starting consensus")
-	gen := generation.NewFactory()
-	cgen := candidate.NewFactory(c.eventBus, c.rpcBus, c.walletPubKey)
-	sgen := score.NewFactory(c.eventBus, c.ConsensusKeys, nil)
-	sel := selection.NewFactory(c.eventBus, c.timerLength)
-	redFirstStep := firststep.NewFactory(c.eventBus, c.rpcBus, c.ConsensusKeys, c.timerLength)
-	redSecondStep := secondstep.NewFactory(c.eventBus, c.rpcBus, c.ConsensusKeys, c.timerLength)
-	agr := agreement.NewFactory(c.eventBus, c.ConsensusKeys)
-
-	consensus.Start(c.eventBus, c.ConsensusKeys, cgen, sgen, sel, redFirstStep, redSecondStep, agr, gen)
+
+	// the timeouts are shared by the components of all rounds, so that they
+	// can adapt to the network latency
+	timeouts := consensus.NewTimeoutController(c.timerLength, config.ConsensusMaxTimeOut)
+	timeoutsChan := make(chan rpcbus.Request, 1)
+	if err := c.rpcBus.Register(topics.GetConsensusTimeouts, timeoutsChan); err != nil {
+		log.WithField("process", "factory").WithError(err).Errorln("could not register the consensus timeouts")
+	}
+	go timeouts.Serve(timeoutsChan)
+
+	names := config.Get().Consensus.Components
+	if len(names) == 0 {
+		names = DefaultComponents
+	}
+
+	factories, err := instantiate(names, Environment{
+		EventBus:     c.eventBus,
+		RPCBus:       c.rpcBus,
+		Keys:         c.ConsensusKeys,
+		WalletPubKey: c.walletPubKey,
+		Timeouts:     timeouts,
+	})
+	if err != nil {
+		log.WithField("process", "factory").WithError(err).Panicln("invalid consensus components")
+	}
+
+	coordinator := consensus.Start(c.eventBus, c.ConsensusKeys, factories...)
+	c.serveFaults(coordinator)
+
+	tracesChan := make(chan rpcbus.Request, 1)
+	if err := c.rpcBus.Register(topics.GetRoundTraces, tracesChan); err != nil {
+		log.WithField("process", "factory").WithError(err).Errorln("could not register the round traces")
+	}
+	go coordinator.Tracer().Serve(tracesChan)
+
+	c.startInspector()
 	log.WithField("process", "factory").Info("Consensus Started")
 }
+
+// StartObserver starts a Coordinator following the consensus without taking
+// part in it, which needs neither a wallet nor consensus keys. The progress of
+// each round is published under the RoundProgress topic.
+func (c *ConsensusFactory) StartObserver() {
+	log.WithField("process", "factory").Info("Starting consensus observer")
+
+	factories, err := instantiate(ObserverComponents, Environment{
+		EventBus: c.eventBus,
+		RPCBus:   c.rpcBus,
+	})
+	if err != nil {
+		log.WithField("process", "factory").WithError(err).Panicln("invalid consensus components")
+	}
+
+	consensus.StartObserver(c.eventBus, factories...)
+
+	c.startInspector()
+	log.WithField("process", "factory").Info("Consensus observer started")
+}
+
+// startInspector exposes the committees, the next selection of the node, and
+// the provisioners and bid list of the current round on the RPCBus
+func (c *ConsensusFactory) startInspector() {
+	// the agreement verifies the votes of the reduction committees, so that
+	// they share the same size
+	inspector := consensus.NewInspector(c.eventBus, c.ConsensusKeys, reduction.MaxCommitteeSize)
+
+	committeeChan := make(chan rpcbus.Request, 1)
+	if err := c.rpcBus.Register(topics.GetCommittee, committeeChan); err != nil {
+		log.WithField("process", "factory").WithError(err).Errorln("could not register the committee inspection")
+	}
+	go inspector.ServeCommittee(committeeChan)
+
+	selectionChan := make(chan rpcbus.Request, 1)
+	if err := c.rpcBus.Register(topics.GetNextSelection, selectionChan); err != nil {
+		log.WithField("process", "factory").WithError(err).Errorln("could not register the next selection inspection")
+	}
+	go inspector.ServeNextSelection(selectionChan)
+
+	provisionersChan := make(chan rpcbus.Request, 1)
+	if err := c.rpcBus.Register(topics.GetProvisioners, provisionersChan); err != nil {
+		log.WithField("process", "factory").WithError(err).Errorln("could not register the provisioners inspection")
+	}
+	go inspector.ServeProvisioners(provisionersChan)
+
+	bidListChan := make(chan rpcbus.Request, 1)
+	if err := c.rpcBus.Register(topics.GetBidList, bidListChan); err != nil {
+		log.WithField("process", "factory").WithError(err).Errorln("could not register the bid list inspection")
+	}
+	go inspector.ServeBidList(bidListChan)
+}
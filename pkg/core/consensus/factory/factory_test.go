@@ -0,0 +1,15 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
+	"github.com/stretchr/testify/assert"
+)
+
+// The inspector describes the committees with the size of the reduction
+// ones, which the agreement must share
+func TestCommitteeSizes(t *testing.T) {
+	assert.Equal(t, reduction.MaxCommitteeSize, agreement.MaxCommitteeSize)
+}
@@ -0,0 +1,23 @@
+//go:build faults
+// +build faults
+
+package factory
+
+import (
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	log "github.com/sirupsen/logrus"
+)
+
+// serveFaults lets the faults injected by the Coordinator be changed at
+// runtime through the SetConsensusFaults topic
+func (c *ConsensusFactory) serveFaults(coordinator *consensus.Coordinator) {
+	faultsChan := make(chan rpcbus.Request, 1)
+	if err := c.rpcBus.Register(topics.SetConsensusFaults, faultsChan); err != nil {
+		log.WithField("process", "factory").WithError(err).Errorln("could not register the consensus faults")
+		return
+	}
+
+	go coordinator.Faults().Serve(faultsChan)
+}
@@ -0,0 +1,10 @@
+//go:build !faults
+// +build !faults
+
+package factory
+
+import "github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+
+// serveFaults does nothing, as the faults can only be changed in the builds
+// with the faults tag
+func (c *ConsensusFactory) serveFaults(coordinator *consensus.Coordinator) {}
@@ -0,0 +1,37 @@
+//go:build faults
+// +build faults
+
+package factory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeFaults(t *testing.T) {
+	keys, err := key.NewRandConsensusKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus, rb := eventbus.New(), rpcbus.New()
+	c := New(bus, rb, time.Second, nil, keys)
+	coordinator := consensus.Start(bus, keys)
+	c.serveFaults(coordinator)
+
+	resp, err := rb.Call(topics.SetConsensusFaults, rpcbus.NewRequest([]string{"withholdvote"}), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"withholdvote"}, resp)
+	assert.Equal(t, []string{"withholdvote"}, coordinator.Faults().Faults())
+
+	// unknown faults are refused
+	_, err = rb.Call(topics.SetConsensusFaults, rpcbus.NewRequest([]string{"sleepy"}), time.Second)
+	assert.Error(t, err)
+}
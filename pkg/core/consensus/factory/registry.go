@@ -0,0 +1,170 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/candidate"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/generation"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/generation/score"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/observer"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction/firststep"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction/secondstep"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/selection"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+)
+
+// Environment holds what the consensus components need to be created
+type Environment struct {
+	EventBus     *eventbus.EventBus
+	RPCBus       *rpcbus.RPCBus
+	Keys         key.ConsensusKeys
+	WalletPubKey *key.PublicKey
+	// Timeouts are shared by the components of all rounds
+	Timeouts *consensus.TimeoutController
+}
+
+// Component is a consensus component which can be enabled by name. The topics
+// it produces and consumes are declared by the consensus.Component itself, and
+// checked by the Coordinator
+type Component struct {
+	Name string
+	// New creates the ComponentFactory of the component
+	New func(Environment) consensus.ComponentFactory
+}
+
+// DefaultComponents are the components enabled when none is configured
+var DefaultComponents = []string{"candidate", "score", "selection", "firststep", "secondstep", "agreement", "generation"}
+
+// ObserverComponents are the components enabled in observer mode
+var ObserverComponents = []string{"observer"}
+
+var (
+	componentsMu sync.RWMutex
+	components   = make(map[string]Component)
+)
+
+func init() {
+	builtin := []Component{
+		{
+			Name: "candidate",
+			New: func(env Environment) consensus.ComponentFactory {
+				return candidate.NewFactory(env.EventBus, env.RPCBus, env.WalletPubKey)
+			},
+		},
+		{
+			Name: "score",
+			New: func(env Environment) consensus.ComponentFactory {
+				return score.NewFactory(env.EventBus, env.Keys, nil)
+			},
+		},
+		{
+			Name: "selection",
+			New: func(env Environment) consensus.ComponentFactory {
+				return selection.NewFactory(env.EventBus, env.Timeouts)
+			},
+		},
+		{
+			Name: "firststep",
+			New: func(env Environment) consensus.ComponentFactory {
+				return firststep.NewFactory(env.EventBus, env.RPCBus, env.Keys, env.Timeouts)
+			},
+		},
+		{
+			Name: "secondstep",
+			New: func(env Environment) consensus.ComponentFactory {
+				return secondstep.NewFactory(env.EventBus, env.RPCBus, env.Keys, env.Timeouts)
+			},
+		},
+		{
+			Name: "agreement",
+			New: func(env Environment) consensus.ComponentFactory {
+				return agreement.NewFactory(env.EventBus, env.Keys)
+			},
+		},
+		{
+			Name: "generation",
+			New: func(env Environment) consensus.ComponentFactory {
+				return generation.NewFactory()
+			},
+		},
+		{
+			Name: "observer",
+			New: func(env Environment) consensus.ComponentFactory {
+				return observer.NewFactory(env.EventBus)
+			},
+		},
+	}
+
+	for _, c := range builtin {
+		if err := Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register makes a consensus component available by its name, so that it can
+// be enabled through the configuration. If Register is called twice with the
+// same name or if the component has no constructor, it returns error.
+func Register(c Component) error {
+	componentsMu.Lock()
+	defer componentsMu.Unlock()
+
+	if c.New == nil {
+		return errors.New("cannot register a component without constructor")
+	}
+
+	if c.Name == "" {
+		return errors.New("cannot register a component without name")
+	}
+
+	if _, dup := components[c.Name]; dup {
+		return errors.New("duplicated component name: " + c.Name)
+	}
+
+	components[c.Name] = c
+	return nil
+}
+
+// Components returns a sorted list of the names of the registered components
+func Components() []string {
+	componentsMu.RLock()
+	defer componentsMu.RUnlock()
+	var list []string
+	for name := range components {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// instantiate creates the ComponentFactory of each of the named components
+func instantiate(names []string, env Environment) ([]consensus.ComponentFactory, error) {
+	componentsMu.RLock()
+	defer componentsMu.RUnlock()
+
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, ok := components[name]; !ok {
+			return nil, fmt.Errorf("unknown consensus component %q", name)
+		}
+
+		if enabled[name] {
+			return nil, fmt.Errorf("consensus component %q is enabled twice", name)
+		}
+		enabled[name] = true
+	}
+
+	factories := make([]consensus.ComponentFactory, len(names))
+	for i, name := range names {
+		factories[i] = components[name].New(env)
+	}
+
+	return factories, nil
+}
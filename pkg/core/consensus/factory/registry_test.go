@@ -0,0 +1,50 @@
+package factory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	for _, name := range DefaultComponents {
+		assert.Contains(t, Components(), name)
+	}
+
+	// duplicated names and missing constructors are refused
+	dup := Component{
+		Name: "selection",
+		New:  func(Environment) consensus.ComponentFactory { return nil },
+	}
+	assert.Error(t, Register(dup))
+	assert.Error(t, Register(Component{Name: "recorder"}))
+
+	recorder := Component{
+		Name: "recorder",
+		New:  func(Environment) consensus.ComponentFactory { return nil },
+	}
+	assert.NoError(t, Register(recorder))
+	assert.Contains(t, Components(), "recorder")
+}
+
+func TestInstantiate(t *testing.T) {
+	_, err := instantiate([]string{"nonexistent"}, Environment{})
+	assert.Error(t, err)
+
+	// the same component can not be enabled twice
+	_, err = instantiate([]string{"generation", "generation"}, Environment{})
+	assert.Error(t, err)
+
+	env := Environment{
+		EventBus: eventbus.New(),
+		RPCBus:   rpcbus.New(),
+		Timeouts: consensus.NewTimeoutController(time.Second, 10*time.Second),
+	}
+	factories, err := instantiate([]string{"firststep", "secondstep", "selection", "generation"}, env)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(factories))
+}
@@ -0,0 +1,248 @@
+//go:build faults
+// +build faults
+
+package consensus
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+)
+
+// Fault is a misbehaviour injected in the consensus messages gossiped by a
+// node. Faults are meant for testing the resilience of the consensus against
+// byzantine provisioners. They are only compiled in the builds with the faults
+// tag, so that a production node can never be made to equivocate.
+type Fault uint8
+
+const (
+	// RandomVote makes the node vote for random block hashes
+	RandomVote Fault = iota
+	// WithholdVote makes the node sign its votes without gossiping them
+	WithholdVote
+	// StaleRound makes the node gossip its votes for the previous round
+	StaleRound
+	// DuplicateScore makes the node gossip each Score several times
+	DuplicateScore
+	// ConflictingAgreement makes the node gossip, along with each of its
+	// Agreement, another one for a random block hash
+	ConflictingAgreement
+
+	faultsNum
+)
+
+// scoreDuplicates is the amount of times a Score is gossiped under the
+// DuplicateScore fault
+const scoreDuplicates = 10
+
+var faultNames = [faultsNum]string{"randomvote", "withholdvote", "staleround", "duplicatescore", "conflictingagreement"}
+
+func (f Fault) String() string {
+	if f < faultsNum {
+		return faultNames[f]
+	}
+	return "unknown"
+}
+
+// ParseFault returns the Fault with the given name
+func ParseFault(name string) (Fault, error) {
+	for i, n := range faultNames {
+		if n == name {
+			return Fault(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown consensus fault %q", name)
+}
+
+// FaultInjector alters the messages gossiped by the Coordinator according to
+// the enabled Faults. It is safe for concurrent use.
+type FaultInjector struct {
+	lock   sync.RWMutex
+	faults [faultsNum]bool
+}
+
+// NewFaultInjector creates a FaultInjector with the Faults of the given names
+// enabled
+func NewFaultInjector(names []string) (*FaultInjector, error) {
+	f := &FaultInjector{}
+	if err := f.Set(names); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Set enables the Faults of the given names, and disables all others. An
+// empty list disables all Faults
+func (f *FaultInjector) Set(names []string) error {
+	var faults [faultsNum]bool
+	for _, name := range names {
+		fault, err := ParseFault(name)
+		if err != nil {
+			return err
+		}
+
+		faults[fault] = true
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.faults = faults
+	return nil
+}
+
+// Enabled returns true if the Fault is enabled
+func (f *FaultInjector) Enabled(fault Fault) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.faults[fault]
+}
+
+// Faults returns the names of the enabled Faults
+func (f *FaultInjector) Faults() []string {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	names := make([]string, 0)
+	for i, enabled := range f.faults {
+		if enabled {
+			names = append(names, Fault(i).String())
+		}
+	}
+
+	return names
+}
+
+// Serve the requests for setting the Faults coming from the RPCBus. The
+// request parameter is the list of Fault names to enable, and the names of
+// the enabled Faults are returned. It is meant to be run in its own goroutine,
+// and it is registered under the SetConsensusFaults topic by the
+// ConsensusFactory
+func (f *FaultInjector) Serve(reqChan <-chan rpcbus.Request) {
+	for r := range reqChan {
+		names, ok := r.Params.([]string)
+		if !ok {
+			r.RespChan <- rpcbus.Response{Resp: nil, Err: fmt.Errorf("expected a list of faults, got %T", r.Params)}
+			continue
+		}
+
+		if err := f.Set(names); err != nil {
+			r.RespChan <- rpcbus.Response{Resp: nil, Err: err}
+			continue
+		}
+
+		lg.WithField("faults", names).Warnln("consensus faults changed")
+		r.RespChan <- rpcbus.Response{Resp: f.Faults(), Err: nil}
+	}
+}
+
+// apply the enabled Faults to a message about to be gossiped. It returns the
+// messages to gossip in its stead. Altered votes are signed again with the
+// keys of the node, bypassing the Journal on purpose
+func (f *FaultInjector) apply(keys key.ConsensusKeys, m message.Message) ([]message.Message, error) {
+	f.lock.RLock()
+	faults := f.faults
+	f.lock.RUnlock()
+
+	switch m.Category() {
+	case topics.Reduction, topics.Agreement:
+		if faults[WithholdVote] {
+			return nil, nil
+		}
+
+		if faults[RandomVote] || faults[StaleRound] {
+			var err error
+			m, err = resignVote(keys, m, func(hdr *header.Header) error {
+				if faults[StaleRound] && hdr.Round > 0 {
+					hdr.Round--
+				}
+
+				if faults[RandomVote] {
+					return randomizeHash(hdr)
+				}
+				return nil
+			})
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		msgs := []message.Message{m}
+		if m.Category() == topics.Agreement && faults[ConflictingAgreement] {
+			conflicting, err := resignVote(keys, m, randomizeHash)
+			if err != nil {
+				return nil, err
+			}
+
+			msgs = append(msgs, conflicting)
+		}
+
+		return msgs, nil
+	case topics.Score:
+		if faults[DuplicateScore] {
+			msgs := make([]message.Message, scoreDuplicates)
+			for i := range msgs {
+				msgs[i] = m
+			}
+			return msgs, nil
+		}
+	}
+
+	return []message.Message{m}, nil
+}
+
+// resignVote creates a copy of a Reduction or Agreement, with the header
+// altered by the mutate function, and signs it
+func resignVote(keys key.ConsensusKeys, m message.Message, mutate func(*header.Header) error) (message.Message, error) {
+	switch p := m.Payload().(type) {
+	case message.Reduction:
+		hdr := p.State()
+		if err := mutate(&hdr); err != nil {
+			return nil, err
+		}
+
+		sig, err := signVote(keys, hdr)
+		if err != nil {
+			return nil, err
+		}
+
+		red := message.NewReduction(hdr)
+		red.SignedHash = sig
+		return message.New(topics.Reduction, *red), nil
+	case message.Agreement:
+		hdr := p.State()
+		if err := mutate(&hdr); err != nil {
+			return nil, err
+		}
+
+		sig, err := signVote(keys, hdr)
+		if err != nil {
+			return nil, err
+		}
+
+		ag := message.NewAgreement(hdr)
+		ag.SetSignature(sig)
+		ag.VotesPerStep = p.VotesPerStep
+		return message.New(topics.Agreement, *ag), nil
+	}
+
+	return m, nil
+}
+
+func randomizeHash(hdr *header.Header) error {
+	hash, err := crypto.RandEntropy(32)
+	if err != nil {
+		return err
+	}
+
+	hdr.BlockHash = hash
+	return nil
+}
@@ -0,0 +1,37 @@
+//go:build !faults
+// +build !faults
+
+package consensus
+
+import (
+	"errors"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+)
+
+// errFaultsDisabled is returned when faults are requested from a build
+// without the faults tag
+var errFaultsDisabled = errors.New("consensus faults need a build with the faults tag")
+
+// FaultInjector leaves the gossiped messages untouched. The injection of
+// faults is only compiled in the builds with the faults tag
+type FaultInjector struct{}
+
+// NewFaultInjector creates a FaultInjector. It fails if any fault is given
+func NewFaultInjector(names []string) (*FaultInjector, error) {
+	if len(names) > 0 {
+		return nil, errFaultsDisabled
+	}
+
+	return &FaultInjector{}, nil
+}
+
+// Faults returns the names of the enabled Faults, which is always empty
+func (f *FaultInjector) Faults() []string {
+	return []string{}
+}
+
+func (f *FaultInjector) apply(keys key.ConsensusKeys, m message.Message) ([]message.Message, error) {
+	return []message.Message{m}, nil
+}
@@ -0,0 +1,30 @@
+//go:build !faults
+// +build !faults
+
+package consensus
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultsDisabled(t *testing.T) {
+	_, err := NewFaultInjector([]string{"randomvote"})
+	assert.Equal(t, errFaultsDisabled, err)
+
+	f, err := NewFaultInjector(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, f.Faults())
+
+	_, keys := MockProvisioners(1)
+	hash, _ := crypto.RandEntropy(32)
+	red := message.New(topics.Reduction, message.MockReduction(hash, 10, 2, keys))
+
+	msgs, err := f.apply(keys[0], red)
+	assert.NoError(t, err)
+	assert.Equal(t, []message.Message{red}, msgs)
+}
@@ -0,0 +1,68 @@
+//go:build faults
+// +build faults
+
+package consensus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFaults(t *testing.T) {
+	f, err := NewFaultInjector([]string{"randomvote", "staleround"})
+	assert.NoError(t, err)
+	assert.True(t, f.Enabled(RandomVote))
+	assert.True(t, f.Enabled(StaleRound))
+	assert.False(t, f.Enabled(WithholdVote))
+
+	// unknown faults leave the enabled ones untouched
+	assert.Error(t, f.Set([]string{"withholdvote", "sleepy"}))
+	assert.Equal(t, []string{"randomvote", "staleround"}, f.Faults())
+
+	assert.NoError(t, f.Set(nil))
+	assert.Empty(t, f.Faults())
+}
+
+func TestApplyFaults(t *testing.T) {
+	p, keys := MockProvisioners(1)
+	hash, _ := crypto.RandEntropy(32)
+	red := message.New(topics.Reduction, message.MockReduction(hash, 10, 2, keys))
+	agr := message.New(topics.Agreement, message.MockAgreement(hash, 10, 3, keys, p))
+
+	f, _ := NewFaultInjector(nil)
+
+	// no fault, no alteration
+	msgs, err := f.apply(keys[0], red)
+	assert.NoError(t, err)
+	assert.Equal(t, []message.Message{red}, msgs)
+
+	assert.NoError(t, f.Set([]string{"withholdvote"}))
+	msgs, err = f.apply(keys[0], red)
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	// altered votes carry a valid signature
+	assert.NoError(t, f.Set([]string{"randomvote", "staleround"}))
+	msgs, err = f.apply(keys[0], red)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(msgs))
+	altered := msgs[0].Payload().(message.Reduction)
+	assert.Equal(t, uint64(9), altered.State().Round)
+	assert.False(t, bytes.Equal(hash, altered.State().BlockHash))
+	assert.NoError(t, message.VerifyVote(altered.State(), altered.SignedHash))
+
+	assert.NoError(t, f.Set([]string{"conflictingagreement"}))
+	msgs, err = f.apply(keys[0], agr)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(msgs))
+	first := msgs[0].Payload().(message.Agreement)
+	second := msgs[1].Payload().(message.Agreement)
+	assert.Equal(t, hash, first.State().BlockHash)
+	assert.False(t, bytes.Equal(hash, second.State().BlockHash))
+	assert.NoError(t, message.VerifyVote(second.State(), second.SignedVotes()))
+}
@@ -51,6 +51,18 @@ func (g *Generator) ID() uint32 {
 	return g.restartID
 }
 
+// Consumes the Restart topic.
+// Implements consensus.Consumer.
+func (g *Generator) Consumes() []topics.Topic {
+	return []topics.Topic{topics.Restart}
+}
+
+// Produces the Generation topic.
+// Implements consensus.Producer.
+func (g *Generator) Produces() []topics.Topic {
+	return []topics.Topic{topics.Generation}
+}
+
 // Collect `Restart` events and triggers a Generation event
 func (g *Generator) Collect(_ consensus.InternalPacket) error {
 	packet := g.signer.Compose(restartFactory)
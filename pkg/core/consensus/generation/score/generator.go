@@ -46,6 +46,7 @@ type Generator struct {
 	threshold *consensus.Threshold
 
 	signer       consensus.Signer
+	eventPlayer  consensus.EventPlayer
 	generationID uint32
 }
 
@@ -84,6 +85,7 @@ func (sf scoreFactory) Create(sender []byte, round uint64, step uint8) consensus
 // Implements consensus.Component.
 func (g *Generator) Initialize(eventPlayer consensus.EventPlayer, signer consensus.Signer, ru consensus.RoundUpdate) []consensus.TopicListener {
 	g.signer = signer
+	g.eventPlayer = eventPlayer
 	g.roundInfo = ru
 	signedSeed, err := g.sign(ru.Seed)
 	if err != nil {
@@ -115,6 +117,18 @@ func (g *Generator) ID() uint32 {
 	return g.generationID
 }
 
+// Consumes the Generation topic.
+// Implements consensus.Consumer.
+func (g *Generator) Consumes() []topics.Topic {
+	return []topics.Topic{topics.Generation}
+}
+
+// Produces the ScoreEvent topic.
+// Implements consensus.Producer.
+func (g *Generator) Produces() []topics.Topic {
+	return []topics.Topic{topics.ScoreEvent}
+}
+
 // Finalize implements consensus.Component.
 func (g *Generator) Finalize() {}
 
@@ -152,7 +166,12 @@ func (g *Generator) generateScore() error {
 
 	score := g.signer.Compose(newFactory(g.seed, proof))
 	msg := message.New(topics.ScoreEvent, score)
-	return g.signer.SendInternally(topics.ScoreEvent, msg, g.ID())
+	if err := g.signer.SendInternally(topics.ScoreEvent, msg, g.ID()); err != nil {
+		return err
+	}
+
+	g.eventPlayer.Trace(consensus.TraceScoreGenerated, nil)
+	return nil
 }
 
 // bidsToScalars will take a global public list, take a subset from it, and then
@@ -13,6 +13,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/dusk-network/dusk-wallet/v2/block"
+	"github.com/dusk-network/dusk-wallet/v2/key"
 	"github.com/dusk-network/dusk-wallet/v2/transactions"
 	"github.com/dusk-network/dusk-wallet/v2/wallet"
 	zkproof "github.com/dusk-network/dusk-zkproof"
@@ -26,6 +27,12 @@ func LaunchConsensus(eventBroker *eventbus.EventBus, rpcBus *rpcbus.RPCBus, w *w
 	startProvisioner(eventBroker, rpcBus, w, counter)
 }
 
+// LaunchObserver starts following the consensus without taking part in it
+func LaunchObserver(eventBroker *eventbus.EventBus, rpcBus *rpcbus.RPCBus) {
+	f := factory.New(eventBroker, rpcBus, cfg.ConsensusTimeOut, nil, key.ConsensusKeys{})
+	f.StartObserver()
+}
+
 func startProvisioner(eventBroker *eventbus.EventBus, rpcBus *rpcbus.RPCBus, w *wallet.Wallet, counter *chainsync.Counter) {
 	// Setting up the consensus factory
 	pubKey := w.PublicKey()
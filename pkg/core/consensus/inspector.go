@@ -0,0 +1,190 @@
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+)
+
+const (
+	// firstVotingStep is the step of the first reduction of a round
+	firstVotingStep = 2
+	// MaxSelectionRounds is the maximum amount of rounds a next selection
+	// request can look at, as every step of every round runs a sortition
+	MaxSelectionRounds = 100
+)
+
+var errNoRoundUpdate = errors.New("no round update received yet")
+
+// CommitteeRequest is the parameter of a request for the committee of a round
+// and step
+type CommitteeRequest struct {
+	Round uint64
+	Step  uint8
+}
+
+// SelectionRequest is the parameter of a request for the next time the node is
+// extracted in a committee
+type SelectionRequest struct {
+	// Rounds is the amount of rounds to look at, starting from the current one
+	Rounds uint64
+	// LastStep is the last step of each round to look at
+	LastStep uint8
+}
+
+// Inspector runs the sortition on demand, with the provisioners of the last
+// round update, to expose the committees outside of the consensus components.
+// It is safe for concurrent use.
+type Inspector struct {
+	keys key.ConsensusKeys
+	// committeeSize is the maximum size of the voting committees
+	committeeSize int
+
+	lock  sync.RWMutex
+	round *RoundUpdate
+}
+
+// NewInspector creates an Inspector, subscribed to the round updates. The keys
+// are used to find out when the node is selected, and can be empty. The
+// committeeSize is the maximum size of the committees voting in the steps
+func NewInspector(subscriber eventbus.Subscriber, keys key.ConsensusKeys, committeeSize int) *Inspector {
+	i := &Inspector{keys: keys, committeeSize: committeeSize}
+	subscriber.Subscribe(topics.RoundUpdate, eventbus.NewCallbackListener(i.CollectRoundUpdate))
+	return i
+}
+
+// CollectRoundUpdate keeps track of the provisioners of the current round
+func (i *Inspector) CollectRoundUpdate(m message.Message) error {
+	r := m.Payload().(RoundUpdate)
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.round = &r
+	return nil
+}
+
+// Committee describes the committee of a round and step. The provisioners of
+// the current round are used, so that committees of far away rounds are not
+// reliable
+func (i *Inspector) Committee(round uint64, step uint8) (user.CommitteeInfo, error) {
+	r, err := i.currentRound()
+	if err != nil {
+		return user.CommitteeInfo{}, err
+	}
+
+	return r.P.InspectCommittee(round, step, i.committeeSize), nil
+}
+
+// NextSelection returns the first round and step, starting from the current
+// round, in which the node is extracted in a committee
+func (i *Inspector) NextSelection(rounds uint64, lastStep uint8) (user.Selection, error) {
+	if len(i.keys.BLSPubKeyBytes) == 0 {
+		return user.Selection{}, errors.New("the node has no consensus keys")
+	}
+
+	if rounds > MaxSelectionRounds {
+		return user.Selection{}, fmt.Errorf("cannot look at more than %d rounds", MaxSelectionRounds)
+	}
+
+	r, err := i.currentRound()
+	if err != nil {
+		return user.Selection{}, err
+	}
+
+	s, found := r.P.NextSelection(i.keys.BLSPubKeyBytes, r.Round, rounds, firstVotingStep, lastStep, i.committeeSize)
+	if !found {
+		return user.Selection{}, fmt.Errorf("the node is not selected within the next %d rounds", rounds)
+	}
+
+	return s, nil
+}
+
+// currentRound returns a copy of the last round update, so that the sortitions
+// run without holding the lock, which would block the round updates
+func (i *Inspector) currentRound() (RoundUpdate, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	if i.round == nil {
+		return RoundUpdate{}, errNoRoundUpdate
+	}
+
+	return *i.round, nil
+}
+
+// Provisioners returns the provisioners of the current round
+func (i *Inspector) Provisioners() (user.Provisioners, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	if i.round == nil {
+		return user.Provisioners{}, errNoRoundUpdate
+	}
+
+	return i.round.P, nil
+}
+
+// BidList returns the bid list of the current round
+func (i *Inspector) BidList() (user.BidList, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	if i.round == nil {
+		return nil, errNoRoundUpdate
+	}
+
+	return i.round.BidList, nil
+}
+
+// ServeCommittee serves the requests for the committees coming from the
+// RPCBus. The request parameter is a CommitteeRequest. It is meant to be run
+// in its own goroutine
+func (i *Inspector) ServeCommittee(reqChan <-chan rpcbus.Request) {
+	for r := range reqChan {
+		req, ok := r.Params.(CommitteeRequest)
+		if !ok {
+			r.RespChan <- rpcbus.Response{Resp: nil, Err: fmt.Errorf("expected a committee request, got %T", r.Params)}
+			continue
+		}
+
+		info, err := i.Committee(req.Round, req.Step)
+		r.RespChan <- rpcbus.Response{Resp: info, Err: err}
+	}
+}
+
+// ServeNextSelection serves the requests for the next selection of the node
+// coming from the RPCBus. The request parameter is a SelectionRequest. It is
+// meant to be run in its own goroutine
+func (i *Inspector) ServeNextSelection(reqChan <-chan rpcbus.Request) {
+	for r := range reqChan {
+		req, ok := r.Params.(SelectionRequest)
+		if !ok {
+			r.RespChan <- rpcbus.Response{Resp: nil, Err: fmt.Errorf("expected a selection request, got %T", r.Params)}
+			continue
+		}
+
+		s, err := i.NextSelection(req.Rounds, req.LastStep)
+		r.RespChan <- rpcbus.Response{Resp: s, Err: err}
+	}
+}
+
+// ServeProvisioners serves the requests for the provisioners of the current
+// round coming from the RPCBus. It is meant to be run in its own goroutine
+func (i *Inspector) ServeProvisioners(reqChan <-chan rpcbus.Request) {
+	for r := range reqChan {
+		p, err := i.Provisioners()
+		r.RespChan <- rpcbus.Response{Resp: p, Err: err}
+	}
+}
+
+// ServeBidList serves the requests for the bid list of the current round
+// coming from the RPCBus. It is meant to be run in its own goroutine
+func (i *Inspector) ServeBidList(reqChan <-chan rpcbus.Request) {
+	for r := range reqChan {
+		bidList, err := i.BidList()
+		r.RespChan <- rpcbus.Response{Resp: bidList, Err: err}
+	}
+}
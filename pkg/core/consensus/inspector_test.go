@@ -0,0 +1,47 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspector(t *testing.T) {
+	bus, rb := eventbus.New(), rpcbus.New()
+	p, keys := MockProvisioners(10)
+	i := NewInspector(bus, keys[0], 64)
+
+	reqChan := make(chan rpcbus.Request, 1)
+	assert.NoError(t, rb.Register(topics.GetCommittee, reqChan))
+	go i.ServeCommittee(reqChan)
+
+	// nothing to inspect before the first round update
+	_, err := rb.Call(topics.GetCommittee, rpcbus.NewRequest(CommitteeRequest{Round: 1, Step: 2}), 0)
+	assert.Error(t, err)
+
+	bus.Publish(topics.RoundUpdate, message.New(topics.RoundUpdate, MockRoundUpdate(1, p, nil)))
+
+	resp, err := rb.Call(topics.GetCommittee, rpcbus.NewRequest(CommitteeRequest{Round: 1, Step: 2}), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, p.InspectCommittee(1, 2, 64), resp)
+
+	provisioners, err := i.Provisioners()
+	assert.NoError(t, err)
+	assert.Equal(t, p.Set, provisioners.Set)
+
+	bidList, err := i.BidList()
+	assert.NoError(t, err)
+	assert.Len(t, bidList, 1)
+
+	s, err := i.NextSelection(10, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), s.Round)
+
+	// the amount of rounds is capped
+	_, err = i.NextSelection(MaxSelectionRounds+1, 3)
+	assert.Error(t, err)
+}
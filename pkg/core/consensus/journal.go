@@ -0,0 +1,211 @@
+package consensus
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+)
+
+// ErrConflictingVote is returned when asked to sign a block hash different than
+// the one already signed for the same round and step
+var ErrConflictingVote = errors.New("a different block hash was already signed for this round and step")
+
+// journalEntrySize is the size of a marshaled journal entry (round, step and
+// block hash)
+const journalEntrySize = 8 + 1 + 32
+
+type journalKey struct {
+	round uint64
+	step  uint8
+}
+
+// Journal is a write-ahead log of the votes signed by this node. Every vote is
+// recorded, and synced to disk, before the signature is produced. This
+// prevents a provisioner restarting mid-round from signing a vote conflicting
+// with one cast before the crash.
+//
+// Entries are appended to the file as they come. Pruning rewrites the file
+// with the entries of the rounds still in progress.
+type Journal struct {
+	lock  sync.Mutex
+	path  string
+	file  *os.File
+	votes map[journalKey][]byte
+}
+
+// OpenJournal loads the journal stored at path, creating it if needed. An
+// empty path creates a journal which is only kept in memory.
+func OpenJournal(path string) (*Journal, error) {
+	j := &Journal{
+		path:  path,
+		votes: make(map[journalKey][]byte),
+	}
+
+	if path == "" {
+		return j, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// a trailing partial entry is the result of a crash while recording.
+	// The related vote was never signed, so it is just discarded
+	buf := bytes.NewBuffer(data[:len(data)-len(data)%journalEntrySize])
+	for buf.Len() > 0 {
+		k, hash, err := unmarshalJournalEntry(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		j.votes[k] = hash
+	}
+
+	if err := j.rewrite(); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Record a vote before signing it. It returns ErrConflictingVote if a different
+// block hash was already recorded for the same round and step
+func (j *Journal) Record(h header.Header) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	k := journalKey{h.Round, h.Step}
+	if hash, ok := j.votes[k]; ok {
+		if !bytes.Equal(hash, h.BlockHash) {
+			return ErrConflictingVote
+		}
+		return nil
+	}
+
+	if j.file != nil {
+		buf := new(bytes.Buffer)
+		if err := marshalJournalEntry(buf, k, h.BlockHash); err != nil {
+			return err
+		}
+
+		if _, err := j.file.Write(buf.Bytes()); err != nil {
+			return err
+		}
+
+		if err := j.file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	hash := make([]byte, len(h.BlockHash))
+	copy(hash, h.BlockHash)
+	j.votes[k] = hash
+	return nil
+}
+
+// Prune removes the votes of the rounds preceding the given one
+func (j *Journal) Prune(round uint64) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	var pruned bool
+	for k := range j.votes {
+		if k.round < round {
+			delete(j.votes, k)
+			pruned = true
+		}
+	}
+
+	if !pruned {
+		return nil
+	}
+
+	return j.rewrite()
+}
+
+// Close the journal file
+func (j *Journal) Close() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// rewrite dumps all votes into a temporary file which then replaces the
+// journal. The journal file is reopened for appending
+func (j *Journal) rewrite() error {
+	if j.path == "" {
+		return nil
+	}
+
+	buf := new(bytes.Buffer)
+	for k, hash := range j.votes {
+		if err := marshalJournalEntry(buf, k, hash); err != nil {
+			return err
+		}
+	}
+
+	tmpPath := j.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+
+	if j.file != nil {
+		_ = j.file.Close()
+		j.file = nil
+	}
+
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	j.file = f
+	return nil
+}
+
+func marshalJournalEntry(r *bytes.Buffer, k journalKey, hash []byte) error {
+	if err := encoding.WriteUint64LE(r, k.round); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteUint8(r, k.step); err != nil {
+		return err
+	}
+
+	return encoding.Write256(r, hash)
+}
+
+func unmarshalJournalEntry(r *bytes.Buffer) (journalKey, []byte, error) {
+	var k journalKey
+	if err := encoding.ReadUint64LE(r, &k.round); err != nil {
+		return k, nil, err
+	}
+
+	if err := encoding.ReadUint8(r, &k.step); err != nil {
+		return k, nil, err
+	}
+
+	hash := make([]byte, 32)
+	if err := encoding.Read256(r, hash); err != nil {
+		return k, nil, err
+	}
+
+	return k, hash, nil
+}
@@ -0,0 +1,106 @@
+package consensus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that a conflicting vote is refused after the journal is reopened, as
+// it would happen after a restart
+func TestJournalConflictingVote(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "consensus.wal")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+	vote := header.Header{Round: 1, Step: 2, BlockHash: hash1}
+	assert.NoError(t, j.Record(vote))
+	assert.NoError(t, j.Close())
+
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	// signing the same vote again is harmless
+	assert.NoError(t, j.Record(vote))
+	assert.Equal(t, ErrConflictingVote, j.Record(header.Header{Round: 1, Step: 2, BlockHash: hash2}))
+	assert.NoError(t, j.Record(header.Header{Round: 1, Step: 3, BlockHash: hash2}))
+}
+
+// Test that pruning drops the votes of the past rounds from the file
+func TestJournalPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "consensus.wal")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+	assert.NoError(t, j.Record(header.Header{Round: 1, Step: 1, BlockHash: hash1}))
+	assert.NoError(t, j.Record(header.Header{Round: 2, Step: 1, BlockHash: hash1}))
+	assert.NoError(t, j.Prune(2))
+	assert.NoError(t, j.Close())
+
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	assert.Equal(t, 1, len(j.votes))
+	assert.NoError(t, j.Record(header.Header{Round: 1, Step: 1, BlockHash: hash2}))
+	assert.Equal(t, ErrConflictingVote, j.Record(header.Header{Round: 2, Step: 1, BlockHash: hash2}))
+}
+
+// Test that a partially written entry is discarded
+func TestJournalTruncatedEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "consensus.wal")
+	hash, _ := crypto.RandEntropy(32)
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, j.Record(header.Header{Round: 1, Step: 1, BlockHash: hash}))
+	_, err = j.file.Write(make([]byte, journalEntrySize/2))
+	assert.NoError(t, err)
+	assert.NoError(t, j.Close())
+
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	assert.Equal(t, 1, len(j.votes))
+}
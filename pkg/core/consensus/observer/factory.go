@@ -0,0 +1,22 @@
+package observer
+
+import (
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+)
+
+// Factory creates the Observer component
+type Factory struct {
+	publisher eventbus.Publisher
+}
+
+// NewFactory instantiates a Factory
+func NewFactory(publisher eventbus.Publisher) *Factory {
+	return &Factory{publisher}
+}
+
+// Instantiate an Observer and return it.
+// Implements consensus.ComponentFactory.
+func (f *Factory) Instantiate() consensus.Component {
+	return NewComponent(f.publisher)
+}
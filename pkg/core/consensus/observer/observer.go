@@ -0,0 +1,275 @@
+package observer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/selection"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "observer")
+
+var _ consensus.Component = (*Observer)(nil)
+
+var (
+	errNotMember = errors.New("sender is not part of the committee")
+	errDuplicate = errors.New("sender already voted in this step")
+)
+
+type voter struct {
+	step   uint8
+	pubkey string
+}
+
+type (
+	// StepTally holds the votes cast by the committee of a reduction step
+	StepTally struct {
+		Step uint8
+		// Votes are the votes of the committee members, weighted by their
+		// occurrences in the committee, per hex encoded block hash
+		Votes map[string]int
+		// Quorum is the amount of votes needed for a block hash to pass the
+		// step
+		Quorum int
+	}
+
+	// Progress is the state of a round, as observed from the messages
+	// gossiped by the provisioners
+	Progress struct {
+		Round uint64
+		// Step is the highest step of the valid votes received
+		Step uint8
+		// Scores is the amount of valid scores received
+		Scores int
+		// BestScore is the hash of the candidate block with the highest
+		// valid score, if any
+		BestScore []byte
+		Steps     []StepTally
+		// Agreements is the amount of valid Agreement messages received
+		Agreements int
+		// Winner is the hash of the block the first valid Agreement was
+		// reached on, if any
+		Winner []byte
+		// Rejected is the amount of invalid messages, or messages sent by
+		// provisioners outside of the committee
+		Rejected int
+	}
+)
+
+// Observer is a Component validating and recording the Score, Reduction and
+// Agreement messages of a round, without taking part in the consensus. The
+// Progress of the round is published under the RoundProgress topic after
+// each message.
+type Observer struct {
+	publisher eventbus.Publisher
+
+	eventPlayer      consensus.EventPlayer
+	scoreHandler     *selection.ScoreHandler
+	reductionHandler *reduction.Handler
+	agreementHandler agreement.Handler
+
+	lock      sync.Mutex
+	progress  Progress
+	bestScore []byte
+	// voters are the provisioners which already voted, per step
+	voters map[voter]bool
+
+	scoreID     uint32
+	reductionID uint32
+	agreementID uint32
+}
+
+// NewComponent creates an Observer
+func NewComponent(publisher eventbus.Publisher) *Observer {
+	return &Observer{publisher: publisher}
+}
+
+// Initialize the Observer, by creating the handlers needed to validate the
+// messages of the round, and returning the Listeners.
+// Implements consensus.Component.
+func (o *Observer) Initialize(eventPlayer consensus.EventPlayer, _ consensus.Signer, r consensus.RoundUpdate) []consensus.TopicListener {
+	o.eventPlayer = eventPlayer
+	// committee membership does not depend on our keys
+	o.scoreHandler = selection.NewScoreHandler(r.BidList)
+	o.reductionHandler = reduction.NewHandler(key.ConsensusKeys{}, r.P)
+	o.agreementHandler = agreement.NewHandler(key.ConsensusKeys{}, r.P)
+	o.progress = Progress{Round: r.Round}
+	o.voters = make(map[voter]bool)
+
+	scoreSubscriber := consensus.TopicListener{
+		Topic:    topics.Score,
+		Listener: consensus.NewSimpleListener(o.CollectScore, consensus.LowPriority, false),
+	}
+	o.scoreID = scoreSubscriber.ID()
+
+	reductionSubscriber := consensus.TopicListener{
+		Topic:    topics.Reduction,
+		Listener: consensus.NewSimpleListener(o.CollectReduction, consensus.LowPriority, false),
+	}
+	o.reductionID = reductionSubscriber.ID()
+
+	agreementSubscriber := consensus.TopicListener{
+		Topic:    topics.Agreement,
+		Listener: consensus.NewSimpleListener(o.CollectAgreement, consensus.LowPriority, false),
+	}
+	o.agreementID = agreementSubscriber.ID()
+
+	return []consensus.TopicListener{scoreSubscriber, reductionSubscriber, agreementSubscriber}
+}
+
+// ID returns the ID of the Score message Listener.
+// Implements consensus.Component.
+func (o *Observer) ID() uint32 {
+	return o.scoreID
+}
+
+// Consumes the Score, Reduction and Agreement topics.
+// Implements consensus.Consumer.
+func (o *Observer) Consumes() []topics.Topic {
+	return []topics.Topic{topics.Score, topics.Reduction, topics.Agreement}
+}
+
+// Finalize pauses event streaming.
+// Implements consensus.Component.
+func (o *Observer) Finalize() {
+	o.eventPlayer.Pause(o.scoreID)
+	o.eventPlayer.Pause(o.reductionID)
+	o.eventPlayer.Pause(o.agreementID)
+}
+
+// Progress returns a copy of the current state of the round
+func (o *Observer) Progress() Progress {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.progress.copy()
+}
+
+// CollectScore records a valid Score, keeping track of the best one
+func (o *Observer) CollectScore(packet consensus.InternalPacket) error {
+	score := packet.(message.Score)
+	if err := o.scoreHandler.Verify(score); err != nil {
+		o.reject(err)
+		return err
+	}
+
+	o.lock.Lock()
+	o.progress.Scores++
+	if o.bestScore == nil || bytes.Compare(score.Score, o.bestScore) == 1 {
+		o.bestScore = score.Score
+		o.progress.BestScore = score.VoteHash
+	}
+	o.lock.Unlock()
+
+	o.publish()
+	return nil
+}
+
+// CollectReduction records the vote of a committee member
+func (o *Observer) CollectReduction(packet consensus.InternalPacket) error {
+	red := packet.(message.Reduction)
+	hdr := red.State()
+	if !o.reductionHandler.IsMember(hdr.PubKeyBLS, hdr.Round, hdr.Step) {
+		o.reject(errNotMember)
+		return errNotMember
+	}
+
+	if err := o.reductionHandler.VerifySignature(red); err != nil {
+		o.reject(err)
+		return err
+	}
+
+	votes := o.reductionHandler.VotesFor(hdr.PubKeyBLS, hdr.Round, hdr.Step)
+	o.lock.Lock()
+	v := voter{hdr.Step, string(hdr.PubKeyBLS)}
+	if o.voters[v] {
+		o.progress.Rejected++
+		o.lock.Unlock()
+		return errDuplicate
+	}
+	o.voters[v] = true
+
+	tally := o.progress.tally(hdr.Step, o.reductionHandler.Quorum(hdr.Round))
+	tally.Votes[hex.EncodeToString(hdr.BlockHash)] += votes
+	if hdr.Step > o.progress.Step {
+		o.progress.Step = hdr.Step
+	}
+	o.lock.Unlock()
+
+	o.publish()
+	return nil
+}
+
+// CollectAgreement records a valid Agreement. The first one determines the
+// winning block of the round
+func (o *Observer) CollectAgreement(packet consensus.InternalPacket) error {
+	ag := packet.(message.Agreement)
+	hdr := ag.State()
+	if !o.agreementHandler.IsMember(hdr.PubKeyBLS, hdr.Round, hdr.Step) {
+		o.reject(errNotMember)
+		return errNotMember
+	}
+
+	if err := o.agreementHandler.Verify(ag); err != nil {
+		o.reject(err)
+		return err
+	}
+
+	o.lock.Lock()
+	o.progress.Agreements++
+	if o.progress.Winner == nil {
+		o.progress.Winner = hdr.BlockHash
+	}
+	o.lock.Unlock()
+
+	o.publish()
+	return nil
+}
+
+func (o *Observer) reject(err error) {
+	lg.WithError(err).Debugln("rejected consensus message")
+	o.lock.Lock()
+	o.progress.Rejected++
+	o.lock.Unlock()
+}
+
+func (o *Observer) publish() {
+	msg := message.New(topics.RoundProgress, o.Progress())
+	o.publisher.Publish(topics.RoundProgress, msg)
+}
+
+// tally returns the StepTally of a step, creating it if needed
+func (p *Progress) tally(step uint8, quorum int) *StepTally {
+	for i := range p.Steps {
+		if p.Steps[i].Step == step {
+			return &p.Steps[i]
+		}
+	}
+
+	p.Steps = append(p.Steps, StepTally{Step: step, Votes: make(map[string]int), Quorum: quorum})
+	return &p.Steps[len(p.Steps)-1]
+}
+
+// copy the Progress, so that it can be published while votes keep coming
+func (p Progress) copy() Progress {
+	steps := make([]StepTally, len(p.Steps))
+	for i, s := range p.Steps {
+		votes := make(map[string]int, len(s.Votes))
+		for hash, n := range s.Votes {
+			votes[hash] = n
+		}
+		steps[i] = StepTally{Step: s.Step, Votes: votes, Quorum: s.Quorum}
+	}
+
+	p.Steps = steps
+	return p
+}
@@ -0,0 +1,57 @@
+package observer
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveRound(t *testing.T) {
+	bus := eventbus.New()
+	progressChan := make(chan message.Message, 10)
+	bus.Subscribe(topics.RoundProgress, eventbus.NewChanListener(progressChan))
+
+	p, keys := consensus.MockProvisioners(10)
+	o := NewComponent(bus)
+	o.Initialize(consensus.NewSimplePlayer(), nil, consensus.MockRoundUpdate(1, p, nil))
+
+	hash, _ := crypto.RandEntropy(32)
+	votes := 0
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, o.CollectReduction(message.MockReduction(hash, 1, 2, keys, i)))
+		votes += o.reductionHandler.VotesFor(keys[i].BLSPubKeyBytes, 1, 2)
+	}
+
+	// votes are counted once per provisioner and step
+	assert.Error(t, o.CollectReduction(message.MockReduction(hash, 1, 2, keys, 0)))
+
+	// votes of provisioners outside of the committee are rejected
+	_, outsiders := consensus.MockProvisioners(1)
+	assert.Error(t, o.CollectReduction(message.MockReduction(hash, 1, 2, outsiders)))
+
+	assert.NoError(t, o.CollectAgreement(message.MockAgreement(hash, 1, 3, keys, p)))
+
+	progress := o.Progress()
+	assert.Equal(t, uint64(1), progress.Round)
+	assert.Equal(t, uint8(2), progress.Step)
+	assert.Equal(t, 1, len(progress.Steps))
+	assert.Equal(t, votes, progress.Steps[0].Votes[hex.EncodeToString(hash)])
+	assert.Equal(t, 1, progress.Agreements)
+	assert.Equal(t, hash, progress.Winner)
+	assert.Equal(t, 2, progress.Rejected)
+
+	// the progress is published after each valid message
+	assert.Equal(t, 4, len(progressChan))
+	last := progress
+	for i := 0; i < 4; i++ {
+		m := <-progressChan
+		last = m.Payload().(Progress)
+	}
+	assert.Equal(t, progress, last)
+}
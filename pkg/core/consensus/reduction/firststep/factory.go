@@ -3,6 +3,7 @@ package firststep
 import (
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -17,18 +18,18 @@ type Factory struct {
 	Bus         eventbus.Broker
 	RBus        *rpcbus.RPCBus
 	Keys        key.ConsensusKeys
-	timeout     time.Duration
+	timeouts    *consensus.TimeoutController
 	Republisher *republisher.Republisher
 }
 
 // NewFactory instantiates a Factory
-func NewFactory(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeout time.Duration) *Factory {
+func NewFactory(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeouts *consensus.TimeoutController) *Factory {
 	r := republisher.New(broker, topics.Reduction)
 	return &Factory{
 		broker,
 		rpcBus,
 		keys,
-		timeout,
+		timeouts,
 		r,
 	}
 }
@@ -36,12 +37,12 @@ func NewFactory(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.Consensu
 // Instantiate a first step reduction Component
 // Implements consensus.ComponentFactory.
 func (f *Factory) Instantiate() consensus.Component {
-	return NewComponent(f.Bus, f.RBus, f.Keys, f.timeout)
+	return NewComponent(f.Bus, f.RBus, f.Keys, f.timeouts)
 }
 
 // CreateReducer is a reduction.FactoryFunc
 func CreateReducer(broker *eventbus.EventBus, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeout time.Duration) reduction.Reducer {
-	f := NewFactory(broker, rpcBus, keys, timeout)
+	f := NewFactory(broker, rpcBus, keys, consensus.NewTimeoutController(timeout, config.ConsensusMaxTimeOut))
 	component := f.Instantiate()
 	return component.(*Reducer)
 }
@@ -33,18 +33,19 @@ type Reducer struct {
 
 	handler    *reduction.Handler
 	aggregator *aggregator
-	timeOut    time.Duration
+	timeouts   *consensus.TimeoutController
+	startTime  time.Time
 	Timer      *reduction.Timer
 	round      uint64
 }
 
 // NewComponent returns an uninitialized reduction component.
-func NewComponent(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeOut time.Duration) reduction.Reducer {
+func NewComponent(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeouts *consensus.TimeoutController) reduction.Reducer {
 	return &Reducer{
-		broker:  broker,
-		rpcBus:  rpcBus,
-		keys:    keys,
-		timeOut: timeOut,
+		broker:   broker,
+		rpcBus:   rpcBus,
+		keys:     keys,
+		timeouts: timeouts,
 	}
 }
 
@@ -55,7 +56,7 @@ func (r *Reducer) Initialize(eventPlayer consensus.EventPlayer, signer consensus
 	r.eventPlayer = eventPlayer
 	r.signer = signer
 	r.handler = reduction.NewHandler(r.keys, ru.P)
-	r.Timer = reduction.NewTimer(r.Halt)
+	r.Timer = reduction.NewTimer(r.Halt, r.timeouts.Clock())
 	r.round = ru.Round
 
 	bestScoreSubscriber := consensus.TopicListener{
@@ -78,6 +79,18 @@ func (r *Reducer) ID() uint32 {
 	return r.reductionID
 }
 
+// Consumes the BestScore and Reduction topics.
+// Implements consensus.Consumer.
+func (r *Reducer) Consumes() []topics.Topic {
+	return []topics.Topic{topics.BestScore, topics.Reduction}
+}
+
+// Produces the StepVotes topic.
+// Implements consensus.Producer.
+func (r *Reducer) Produces() []topics.Topic {
+	return []topics.Topic{topics.StepVotes}
+}
+
 // Finalize the Reducer component by killing the timer, and pausing event streaming.
 // This will stop a reduction cycle short, and renders this Reducer useless
 // after calling.
@@ -113,7 +126,8 @@ func (r *Reducer) Filter(hdr header.Header) bool {
 }
 
 func (r *Reducer) startReduction() {
-	r.Timer.Start(r.timeOut)
+	r.startTime = r.timeouts.Clock().Now()
+	r.Timer.Start(r.timeouts.TimeOut(consensus.FirstReductionPhase))
 	r.aggregator = newAggregator(r.Halt, r.handler, r.rpcBus)
 }
 
@@ -161,6 +175,8 @@ func (r *Reducer) Halt(hash []byte, svs ...*message.StepVotes) {
 	r.eventPlayer.Pause(r.reductionID)
 
 	if len(svs) > 0 {
+		r.eventPlayer.Trace(consensus.TraceStepVotesQuorum, hash)
+		r.timeouts.Succeeded(consensus.FirstReductionPhase, r.timeouts.Clock().Now().Sub(r.startTime))
 		sv := *svs[0]
 		svm = message.NewStepVotesMsg(r.round, hash, r.keys.BLSPubKeyBytes, sv)
 	} else {
@@ -171,10 +187,13 @@ func (r *Reducer) Halt(hash []byte, svs ...*message.StepVotes) {
 			hash: hash,
 		}
 		// Increase timeout if we did not have a good result
-		r.timeOut = r.timeOut * 2
+		r.eventPlayer.Trace(consensus.TraceStepTimeout, nil)
+		r.timeouts.Failed(consensus.FirstReductionPhase)
 		svm = r.signer.Compose(factory).(message.StepVotesMsg)
 	}
 
+	r.eventPlayer.Trace(consensus.TraceStepEnd, hash)
+
 	msg := message.New(topics.StepVotes, svm)
 	r.signer.SendInternally(topics.StepVotes, msg, r.ID())
 }
@@ -187,8 +206,9 @@ func (r *Reducer) CollectBestScore(e consensus.InternalPacket) error {
 	step := r.eventPlayer.Forward(r.ID())
 	r.eventPlayer.Play(r.reductionID)
 
+	hash := e.State().BlockHash
+	r.eventPlayer.Trace(consensus.TraceStepStart, hash)
 	if r.handler.AmMember(r.round, step) {
-		hash := e.State().BlockHash
 		r.sendReduction(step, hash)
 	}
 
@@ -36,7 +36,7 @@ func TestFirstStep(t *testing.T) {
 	// test that the Player is PAUSED
 	assert.Equal(t, consensus.PAUSED, hlp.State())
 	// test that the timeout is still 1 second
-	assert.Equal(t, 1*time.Second, hlp.Reducer.(*Reducer).timeOut)
+	assert.Equal(t, 1*time.Second, hlp.Reducer.(*Reducer).timeouts.TimeOut(consensus.FirstReductionPhase))
 }
 
 func TestMoreSteps(t *testing.T) {
@@ -59,7 +59,7 @@ func TestMoreSteps(t *testing.T) {
 	// test that the Player is PAUSED
 	assert.Equal(t, consensus.PAUSED, hlp.State())
 	// test that the timeout is still 1 second
-	assert.Equal(t, 1*time.Second, hlp.Reducer.(*Reducer).timeOut)
+	assert.Equal(t, 1*time.Second, hlp.Reducer.(*Reducer).timeouts.TimeOut(consensus.FirstReductionPhase))
 }
 
 func TestFirstStepTimeOut(t *testing.T) {
@@ -80,7 +80,7 @@ func TestFirstStepTimeOut(t *testing.T) {
 	// test that the Player is PAUSED
 	assert.Equal(t, consensus.PAUSED, hlp.State())
 	// test that the timeout has doubled
-	assert.Equal(t, timeOut*2, hlp.Reducer.(*Reducer).timeOut)
+	assert.Equal(t, timeOut*2, hlp.Reducer.(*Reducer).timeouts.TimeOut(consensus.FirstReductionPhase))
 }
 
 func BenchmarkFirstStep(b *testing.B) {
@@ -12,7 +12,9 @@ import (
 	"github.com/dusk-network/dusk-wallet/v2/key"
 )
 
-const maxCommitteeSize = 64
+// MaxCommitteeSize is the maximum size of the committees voting in the
+// reduction steps
+const MaxCommitteeSize = 64
 
 type (
 	// Handler is responsible for performing operations that need to know
@@ -32,15 +34,15 @@ func NewHandler(keys key.ConsensusKeys, p user.Provisioners) *Handler {
 
 // AmMember checks if we are part of the committee.
 func (b *Handler) AmMember(round uint64, step uint8) bool {
-	return b.Handler.AmMember(round, step, maxCommitteeSize)
+	return b.Handler.AmMember(round, step, MaxCommitteeSize)
 }
 
 func (b *Handler) IsMember(pubKeyBLS []byte, round uint64, step uint8) bool {
-	return b.Handler.IsMember(pubKeyBLS, round, step, maxCommitteeSize)
+	return b.Handler.IsMember(pubKeyBLS, round, step, MaxCommitteeSize)
 }
 
 func (b *Handler) VotesFor(pubKeyBLS []byte, round uint64, step uint8) int {
-	return b.Handler.VotesFor(pubKeyBLS, round, step, maxCommitteeSize)
+	return b.Handler.VotesFor(pubKeyBLS, round, step, MaxCommitteeSize)
 }
 
 // Verify the BLS signature of the Reduction event. Since the payload is nil, verifying the signature equates to verifying solely the Header
@@ -60,10 +62,10 @@ func (b *Handler) VerifySignature(red message.Reduction) error {
 }
 
 func (b *Handler) Quorum(round uint64) int {
-	return int(math.Ceil(float64(b.CommitteeSize(round, maxCommitteeSize)) * 0.75))
+	return int(math.Ceil(float64(b.CommitteeSize(round, MaxCommitteeSize)) * 0.75))
 }
 
 // Committee returns a VotingCommittee for a given round and step.
 func (b *Handler) Committee(round uint64, step uint8) user.VotingCommittee {
-	return b.Handler.Committee(round, step, maxCommitteeSize)
+	return b.Handler.Committee(round, step, MaxCommitteeSize)
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction/firststep"
@@ -106,11 +107,34 @@ func collectEvents(t *testing.T, c *consensus.Coordinator, evs []message.Reducti
 	}
 }
 
+// bestScoreFactory stands in for the selection, as the tests publish the
+// BestScore themselves
+type bestScoreFactory struct{}
+
+func (f *bestScoreFactory) Instantiate() consensus.Component {
+	return &bestScoreFactory{}
+}
+
+func (f *bestScoreFactory) Initialize(consensus.EventPlayer, consensus.Signer, consensus.RoundUpdate) []consensus.TopicListener {
+	return nil
+}
+
+func (f *bestScoreFactory) Finalize() {}
+
+func (f *bestScoreFactory) ID() uint32 {
+	return 0
+}
+
+func (f *bestScoreFactory) Produces() []topics.Topic {
+	return []topics.Topic{topics.BestScore}
+}
+
 func wireReduction(t *testing.T, bus *eventbus.EventBus, rpcBus *rpcbus.RPCBus) (*consensus.Coordinator, *firststep.Helper) {
 	hlp := firststep.NewHelper(bus, rpcBus, 10, 1*time.Second)
-	f1 := firststep.NewFactory(bus, rpcBus, hlp.Keys[0], 1*time.Second)
-	f2 := secondstep.NewFactory(bus, rpcBus, hlp.Keys[0], 1*time.Second)
-	c := consensus.Start(bus, hlp.Keys[0], f1, f2)
+	timeouts := consensus.NewTimeoutController(1*time.Second, config.ConsensusMaxTimeOut)
+	f1 := firststep.NewFactory(bus, rpcBus, hlp.Keys[0], timeouts)
+	f2 := secondstep.NewFactory(bus, rpcBus, hlp.Keys[0], timeouts)
+	c := consensus.Start(bus, hlp.Keys[0], &bestScoreFactory{}, f1, f2)
 	// Starting the coordinator
 	ru := consensus.MockRoundUpdate(1, hlp.P, nil)
 	msg := message.New(topics.RoundUpdate, ru)
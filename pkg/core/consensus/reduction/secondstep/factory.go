@@ -3,6 +3,7 @@ package secondstep
 import (
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
@@ -12,31 +13,31 @@ import (
 
 // Factory creates a second step reduction Component
 type Factory struct {
-	Bus     eventbus.Broker
-	RBus    *rpcbus.RPCBus
-	Keys    key.ConsensusKeys
-	timeout time.Duration
+	Bus      eventbus.Broker
+	RBus     *rpcbus.RPCBus
+	Keys     key.ConsensusKeys
+	timeouts *consensus.TimeoutController
 }
 
 // NewFactory creates a Factory
-func NewFactory(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeout time.Duration) *Factory {
+func NewFactory(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeouts *consensus.TimeoutController) *Factory {
 	return &Factory{
 		broker,
 		rpcBus,
 		keys,
-		timeout,
+		timeouts,
 	}
 }
 
 // Instantiate a second step reduction Component
 // Implements consensus.ComponentFactory.
 func (f *Factory) Instantiate() consensus.Component {
-	return NewComponent(f.Bus, f.RBus, f.Keys, f.timeout)
+	return NewComponent(f.Bus, f.RBus, f.Keys, f.timeouts)
 }
 
 // CreateReducer is callback used by reduction.Helper to wire up the tests
 var CreateReducer reduction.FactoryFunc = func(eb *eventbus.EventBus, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeout time.Duration) reduction.Reducer {
-	f := NewFactory(eb, rpcBus, keys, timeout)
+	f := NewFactory(eb, rpcBus, keys, consensus.NewTimeoutController(timeout, config.ConsensusMaxTimeOut))
 	a := f.Instantiate()
 	return a.(*Reducer)
 }
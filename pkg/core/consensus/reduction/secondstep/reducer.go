@@ -37,18 +37,19 @@ type Reducer struct {
 
 	handler    *reduction.Handler
 	aggregator *aggregator
-	timeOut    time.Duration
+	timeouts   *consensus.TimeoutController
+	startTime  time.Time
 	timer      *reduction.Timer
 	round      uint64
 }
 
 // NewComponent returns an uninitialized reduction component.
-func NewComponent(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeOut time.Duration) reduction.Reducer {
+func NewComponent(broker eventbus.Broker, rpcBus *rpcbus.RPCBus, keys key.ConsensusKeys, timeouts *consensus.TimeoutController) reduction.Reducer {
 	return &Reducer{
-		broker:  broker,
-		rpcBus:  rpcBus,
-		keys:    keys,
-		timeOut: timeOut,
+		broker:   broker,
+		rpcBus:   rpcBus,
+		keys:     keys,
+		timeouts: timeouts,
 	}
 }
 
@@ -59,7 +60,7 @@ func (r *Reducer) Initialize(eventPlayer consensus.EventPlayer, signer consensus
 	r.eventPlayer = eventPlayer
 	r.signer = signer
 	r.handler = reduction.NewHandler(r.keys, ru.P)
-	r.timer = reduction.NewTimer(r.Halt)
+	r.timer = reduction.NewTimer(r.Halt, r.timeouts.Clock())
 	r.round = ru.Round
 
 	stepVotesSubscriber := consensus.TopicListener{
@@ -82,6 +83,18 @@ func (r *Reducer) ID() uint32 {
 	return r.reductionID
 }
 
+// Consumes the StepVotes and Reduction topics.
+// Implements consensus.Consumer.
+func (r *Reducer) Consumes() []topics.Topic {
+	return []topics.Topic{topics.StepVotes, topics.Reduction}
+}
+
+// Produces the Restart topic.
+// Implements consensus.Producer.
+func (r *Reducer) Produces() []topics.Topic {
+	return []topics.Topic{topics.Restart}
+}
+
 // Finalize the Reducer component by killing the timer, and pausing event streaming.
 // This will stop a reduction cycle short, and renders this Reducer useless
 // after calling.
@@ -116,7 +129,8 @@ func (r *Reducer) Filter(hdr header.Header) bool {
 }
 
 func (r *Reducer) startReduction(sv message.StepVotesMsg) {
-	r.timer.Start(r.timeOut)
+	r.startTime = r.timeouts.Clock().Now()
+	r.timer.Start(r.timeouts.TimeOut(consensus.SecondReductionPhase))
 	r.aggregator = newAggregator(r.Halt, r.handler, &sv.StepVotes)
 }
 
@@ -148,14 +162,25 @@ func (r *Reducer) Halt(hash []byte, b ...*message.StepVotes) {
 	r.timer.Stop()
 	r.eventPlayer.Pause(r.reductionID)
 
+	quorum := hash != nil && !bytes.Equal(hash, emptyHash[:]) && stepVotesAreValid(b)
+	if quorum {
+		r.eventPlayer.Trace(consensus.TraceStepVotesQuorum, hash)
+	} else {
+		r.eventPlayer.Trace(consensus.TraceStepTimeout, nil)
+	}
+	r.eventPlayer.Trace(consensus.TraceStepEnd, hash)
+
 	// Sending of agreement happens on it's own step
 	step := r.eventPlayer.Forward(r.ID())
-	if hash != nil && !bytes.Equal(hash, emptyHash[:]) && stepVotesAreValid(b) && r.handler.AmMember(r.round, step) {
-		lg.WithField("step", step).Debugln("sending agreement")
-		r.sendAgreement(step, hash, b)
+	if quorum {
+		r.timeouts.Succeeded(consensus.SecondReductionPhase, r.timeouts.Clock().Now().Sub(r.startTime))
+		if r.handler.AmMember(r.round, step) {
+			lg.WithField("step", step).Debugln("sending agreement")
+			r.sendAgreement(step, hash, b)
+		}
 	} else {
 		// Increase timeout if we had no agreement
-		r.timeOut = r.timeOut * 2
+		r.timeouts.Failed(consensus.SecondReductionPhase)
 	}
 
 	restart := r.signer.Compose(restartFactory)
@@ -177,6 +202,7 @@ func (r *Reducer) CollectStepVotes(e consensus.InternalPacket) error {
 	// fetch the right step
 	step := r.eventPlayer.Forward(r.ID())
 	r.eventPlayer.Play(r.reductionID)
+	r.eventPlayer.Trace(consensus.TraceStepStart, hdr.BlockHash)
 
 	if r.handler.AmMember(r.round, step) {
 		// propagating a new StepVoteMsg with the right step
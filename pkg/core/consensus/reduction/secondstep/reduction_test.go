@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,7 +35,7 @@ func TestSecondStep(t *testing.T) {
 	assert.NoError(t, hlp.Verify(hash, *ag.VotesPerStep[1], 1))
 
 	// Timeout should be the same
-	assert.Equal(t, 1*time.Second, hlp.Reducer.(*Reducer).timeOut)
+	assert.Equal(t, 1*time.Second, hlp.Reducer.(*Reducer).timeouts.TimeOut(consensus.SecondReductionPhase))
 }
 
 func TestSecondStepAfterFailure(t *testing.T) {
@@ -58,7 +59,7 @@ func TestSecondStepAfterFailure(t *testing.T) {
 		t.Fatal("not supposed to construct an agreement if the first StepVotes is nil")
 	case <-time.After(time.Second * 1):
 		// Ensure timeout was doubled
-		assert.Equal(t, timeOut*2, hlp.Reducer.(*Reducer).timeOut)
+		assert.Equal(t, timeOut*2, hlp.Reducer.(*Reducer).timeouts.TimeOut(consensus.SecondReductionPhase))
 		// Success
 	}
 }
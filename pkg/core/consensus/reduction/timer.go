@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	log "github.com/sirupsen/logrus"
 )
@@ -12,20 +13,22 @@ var emptyHash [32]byte
 
 type Timer struct {
 	requestHalt func([]byte, ...*message.StepVotes)
+	clock       consensus.Clock
 	lock        sync.RWMutex
-	t           *time.Timer
+	t           consensus.Alarm
 }
 
-func NewTimer(requestHalt func([]byte, ...*message.StepVotes)) *Timer {
+func NewTimer(requestHalt func([]byte, ...*message.StepVotes), clock consensus.Clock) *Timer {
 	return &Timer{
 		requestHalt: requestHalt,
+		clock:       clock,
 	}
 }
 
 func (t *Timer) Start(timeOut time.Duration) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
-	t.t = time.AfterFunc(timeOut, t.Trigger)
+	t.t = t.clock.AfterFunc(timeOut, t.Trigger)
 }
 
 func (t *Timer) Stop() {
@@ -3,6 +3,7 @@ package reduction_test
 import (
 	"testing"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,6 @@ import (
 // Ensure that stopping a timer which was never started does not result
 // in a panic.
 func TestStopNilTimer(t *testing.T) {
-	timer := reduction.NewTimer(func([]byte, ...*message.StepVotes) {})
+	timer := reduction.NewTimer(func([]byte, ...*message.StepVotes) {}, consensus.SystemClock)
 	assert.NotPanics(t, timer.Stop)
 }
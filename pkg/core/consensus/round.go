@@ -7,6 +7,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
@@ -23,12 +24,16 @@ var emptyPayload = new(bytes.Buffer)
 
 var lg = log.WithField("process", "coordinator")
 
+var errObserver = errors.New("an observing Coordinator does not take part in the consensus")
+
 // roundStore is the central registry for all consensus components and listeners.
 // It is used for message dispatching and controlling the stream of events.
 type roundStore struct {
 	lock        sync.RWMutex
 	subscribers map[topics.Topic][]Listener
 	components  []Component
+	// produced holds the topics each component may send internally, by ID
+	produced    map[uint32]map[topics.Topic]bool
 	coordinator *Coordinator
 }
 
@@ -36,6 +41,7 @@ func newStore(c *Coordinator) *roundStore {
 	s := &roundStore{
 		subscribers: make(map[topics.Topic][]Listener),
 		components:  make([]Component, 0),
+		produced:    make(map[uint32]map[topics.Topic]bool),
 		coordinator: c,
 	}
 
@@ -59,10 +65,24 @@ func (s *roundStore) initializeComponents(round RoundUpdate) []TopicListener {
 	allSubs := make([]TopicListener, 0, len(s.components)*2)
 	for _, component := range s.components {
 		subs := component.Initialize(s.coordinator, s.coordinator, round)
+		d := dependenciesOf(component)
+		if err := checkSubscriptions(d, subs); err != nil {
+			log.Panic(err)
+		}
+
 		for _, sub := range subs {
 			s.subscribe(sub.Topic, sub.Listener)
 		}
 
+		produced := make(map[topics.Topic]bool, len(d.Produces))
+		for _, topic := range d.Produces {
+			produced[topic] = true
+		}
+
+		s.lock.Lock()
+		s.produced[component.ID()] = produced
+		s.lock.Unlock()
+
 		allSubs = append(allSubs, subs...)
 	}
 	s.lock.Lock()
@@ -72,6 +92,26 @@ func (s *roundStore) initializeComponents(round RoundUpdate) []TopicListener {
 	return allSubs
 }
 
+// dependencies returns the Dependencies declared by the components
+func (s *roundStore) dependencies() []Dependencies {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	deps := make([]Dependencies, len(s.components))
+	for i, component := range s.components {
+		deps[i] = dependenciesOf(component)
+	}
+
+	return deps
+}
+
+// produces returns whether the component with the given ID declared the topic
+// among the ones it sends internally
+func (s *roundStore) produces(id uint32, topic topics.Topic) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.produced[id][topic]
+}
+
 func (s *roundStore) subscribe(topic topics.Topic, sub Listener) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -164,6 +204,9 @@ type Coordinator struct {
 	*SyncState
 	eventBus   *eventbus.EventBus
 	keys       key.ConsensusKeys
+	journal    *Journal
+	faults     *FaultInjector
+	tracer     *Tracer
 	factories  []ComponentFactory
 	components []Component
 	eventqueue *Queue
@@ -176,20 +219,48 @@ type Coordinator struct {
 	unsynced bool
 
 	stopped bool
+	// observer is true if the Coordinator follows the consensus without
+	// taking part in it
+	observer bool
 }
 
-// Start the coordinator by wiring the listener to the RoundUpdate
+// Start the coordinator by wiring the listener to the RoundUpdate. The votes
+// are recorded in the journal file of the configuration
 func Start(eventBus *eventbus.EventBus, keys key.ConsensusKeys, factories ...ComponentFactory) *Coordinator {
+	journal, err := OpenJournal(config.Get().Consensus.JournalFile)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return StartWithJournal(eventBus, keys, journal, factories...)
+}
+
+// StartWithJournal starts the coordinator like Start does, recording the votes
+// in the given Journal. It lets the nodes sharing a process, such as the
+// simulated ones, keep their own journal
+func StartWithJournal(eventBus *eventbus.EventBus, keys key.ConsensusKeys, journal *Journal, factories ...ComponentFactory) *Coordinator {
 	pkBuf := new(bytes.Buffer)
 
 	if err := encoding.WriteVarBytes(pkBuf, keys.BLSPubKeyBytes); err != nil {
 		log.Panic(err)
 	}
 
+	faults, err := NewFaultInjector(config.Get().Consensus.Faults)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	if f := faults.Faults(); len(f) > 0 {
+		lg.WithField("faults", f).Warnln("consensus faults are enabled")
+	}
+
 	c := &Coordinator{
 		SyncState:  NewState(),
 		eventBus:   eventBus,
 		keys:       keys,
+		journal:    journal,
+		faults:     faults,
+		tracer:     NewTracer(),
 		factories:  factories,
 		eventqueue: NewQueue(),
 		roundQueue: NewQueue(),
@@ -198,7 +269,46 @@ func Start(eventBus *eventbus.EventBus, keys key.ConsensusKeys, factories ...Com
 		stopped:    true,
 	}
 
-	// completing the initialization
+	c.listen()
+	return c
+}
+
+// StartObserver starts a Coordinator which follows the consensus without
+// holding consensus keys. Since observing components do not take part in the
+// steps, events of the current round are dispatched regardless of their step,
+// and the Coordinator refuses to sign or gossip
+func StartObserver(eventBus *eventbus.EventBus, factories ...ComponentFactory) *Coordinator {
+	journal, _ := OpenJournal("")
+	faults, _ := NewFaultInjector(nil)
+	c := &Coordinator{
+		SyncState:  NewState(),
+		eventBus:   eventBus,
+		journal:    journal,
+		faults:     faults,
+		tracer:     NewTracer(),
+		factories:  factories,
+		eventqueue: NewQueue(),
+		roundQueue: NewQueue(),
+		unsynced:   true,
+		stopped:    true,
+		observer:   true,
+	}
+
+	c.listen()
+	return c
+}
+
+// listen completes the initialization, by wiring the listeners to the
+// EventBus. It panics if the dependencies of the components are not
+// satisfied, as the consensus would never progress
+func (c *Coordinator) listen() {
+	c.reinstantiateStore()
+	// the components are the same on every round, so that checking them
+	// once is enough
+	if err := ValidateDependencies(c.store.dependencies()...); err != nil {
+		log.Panic(err)
+	}
+
 	listener := eventbus.NewCallbackListener(c.CollectEvent)
 	c.eventBus.SubscribeDefault(listener)
 
@@ -207,9 +317,6 @@ func Start(eventBus *eventbus.EventBus, keys key.ConsensusKeys, factories ...Com
 
 	stopListener := eventbus.NewCallbackListener(c.StopConsensus)
 	c.eventBus.Subscribe(topics.StopConsensus, stopListener)
-
-	c.reinstantiateStore()
-	return c
 }
 
 func (c *Coordinator) StopConsensus(m message.Message) error {
@@ -248,8 +355,17 @@ func (c *Coordinator) CollectRoundUpdate(m message.Message) error {
 	if !c.stopped {
 		c.stopConsensus()
 	}
+
+	// votes of the finalized rounds cannot conflict with new ones anymore
+	if err := c.journal.Prune(r.Round); err != nil {
+		lg.WithError(err).Warnln("could not prune the vote journal")
+	}
+
+	// the committees memoized before the chain changed the stakes are stale
+	r.P.Invalidate()
 	c.onNewRound(r, c.unsynced)
 	c.Update(r.Round)
+	c.tracer.Record(r.Round, c.Step(), TraceRoundUpdate, r.Hash)
 	c.unsynced = false
 	c.stopped = false
 	go c.flushRoundQueue()
@@ -323,7 +439,7 @@ func (c *Coordinator) CollectEvent(m message.Message) error {
 	// TODO: once go 1.14 is out, re-examine the overhead of using `defer`.
 	var comparison header.Phase
 	c.lock.RLock()
-	if m.Category() == topics.Agreement {
+	if m.Category() == topics.Agreement || c.observer {
 		comparison = hdr.CompareRound(c.Round())
 	} else {
 		comparison = hdr.CompareRoundAndStep(c.Round(), c.Step())
@@ -340,8 +456,9 @@ func (c *Coordinator) CollectEvent(m message.Message) error {
 		// If it is a future agreement event, we store it on the
 		// `roundQueue`. This means that the event will be dispatched
 		// as soon as the Coordinator reaches the round in the event
-		// header.
-		if m.Category() == topics.Agreement {
+		// header. The same goes for all events when observing, as the
+		// step of an observing Coordinator never advances.
+		if m.Category() == topics.Agreement || c.observer {
 			c.roundQueue.PutEvent(hdr.Round, hdr.Step, m)
 			c.lock.RUnlock()
 			return nil
@@ -389,13 +506,28 @@ func (c *Coordinator) dispatchQueuedEvents() {
 // XXX: adjust the signature verification on reduction (and agreement)
 // Sign uses the blockhash (which is lost when decoupling the Header and the Payload) to recompose the Header and sign the Payload
 // by adding it to the signature. Argument packet can be nil
+// The vote is recorded in the journal beforehand, so that a conflicting vote
+// for the same round and step is never signed, even across restarts
 func (c *Coordinator) Sign(h header.Header) ([]byte, error) {
+	if c.observer {
+		return nil, errObserver
+	}
+
+	if err := c.journal.Record(h); err != nil {
+		return nil, err
+	}
+
+	return signVote(c.keys, h)
+}
+
+// signVote signs the vote described by the header with the BLS keys
+func signVote(keys key.ConsensusKeys, h header.Header) ([]byte, error) {
 	preimage := new(bytes.Buffer)
 	if err := header.MarshalSignableVote(preimage, h); err != nil {
 		return nil, err
 	}
 
-	signedHash, err := bls.Sign(c.keys.BLSSecretKey, c.keys.BLSPubKey, preimage.Bytes())
+	signedHash, err := bls.Sign(keys.BLSSecretKey, keys.BLSPubKey, preimage.Bytes())
 	if err != nil {
 		return nil, err
 	}
@@ -404,30 +536,48 @@ func (c *Coordinator) Sign(h header.Header) ([]byte, error) {
 }
 
 // Gossip concatenates the topic, the header and the payload,
-// and gossips it to the rest of the network.
+// and gossips it to the rest of the network. The enabled Faults are applied
+// beforehand.
 // TODO: interface - marshalling should actually be done after the Gossip to
 // respect the simmetry of the architecture
 func (c *Coordinator) Gossip(msg message.Message, id uint32) error {
+	if c.observer {
+		return errObserver
+	}
+
 	if !c.store.hasComponent(id) {
 		return fmt.Errorf("caller with ID %d is unregistered", id)
 	}
 
-	// message.Marshal takes care of prepending the topic, marshalling the
-	// header, etc
-	buf, err := message.Marshal(msg)
+	msgs, err := c.faults.apply(c.keys, msg)
 	if err != nil {
 		return err
 	}
 
-	// TODO: interface - setting the payload to a buffer will go away as soon as the Marshalling
-	// is performed where it is supposed to (i.e. after the Gossip)
-	serialized := message.New(msg.Category(), buf)
+	for _, m := range msgs {
+		// message.Marshal takes care of prepending the topic, marshalling the
+		// header, etc
+		buf, err := message.Marshal(m)
+		if err != nil {
+			return err
+		}
+
+		// TODO: interface - setting the payload to a buffer will go away as soon as the Marshalling
+		// is performed where it is supposed to (i.e. after the Gossip)
+		serialized := message.New(m.Category(), buf)
+
+		// gossip away
+		c.eventBus.Publish(topics.Gossip, serialized)
+	}
 
-	// gossip away
-	c.eventBus.Publish(topics.Gossip, serialized)
 	return nil
 }
 
+// Faults returns the FaultInjector altering the gossiped messages
+func (c *Coordinator) Faults() *FaultInjector {
+	return c.faults
+}
+
 func (c *Coordinator) Compose(pf PacketFactory) InternalPacket {
 	return pf.Create(c.keys.BLSPubKeyBytes, c.Round(), c.Step())
 }
@@ -437,6 +587,10 @@ func (c *Coordinator) SendInternally(topic topics.Topic, msg message.Message, id
 		return fmt.Errorf("caller with ID %d is unregistered", id)
 	}
 
+	if !c.store.produces(id, topic) {
+		return fmt.Errorf("caller with ID %d does not declare topic %s among the ones it produces", id, topic)
+	}
+
 	c.eventBus.Publish(topic, msg)
 	return nil
 }
@@ -466,6 +620,16 @@ func (c *Coordinator) SendInternally(topic topics.Topic, hash []byte, payload *b
 }
 */
 
+// Trace records an event in the trace of the current round and step
+func (c *Coordinator) Trace(kind TraceKind, hash []byte) {
+	c.tracer.Record(c.Round(), c.Step(), kind, hash)
+}
+
+// Tracer returns the Tracer recording the events of the last rounds
+func (c *Coordinator) Tracer() *Tracer {
+	return c.tracer
+}
+
 // Pause event streaming for the listener with the specified ID.
 func (c *Coordinator) Pause(id uint32) {
 	lg.WithField("id", id).Traceln("pausing")
@@ -149,6 +149,25 @@ func TestQueueDispatchAgreement(t *testing.T) {
 	<-agComp.receivedEvents
 }
 
+// Test that the Coordinator refuses to start components whose dependencies are
+// not satisfied, and that the undeclared topics can not be sent internally.
+func TestComponentDependencies(t *testing.T) {
+	keys, err := key.NewRandConsensusKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing produces the StepVotes
+	assert.Panics(t, func() { Start(eventbus.New(), keys, &mockFactory{topics.StepVotes}) })
+
+	// the same component can not be started twice
+	assert.Panics(t, func() { Start(eventbus.New(), keys, &mockFactory{topics.Reduction}, &mockFactory{topics.Reduction}) })
+
+	c, comps := initCoordinatorTest(t, topics.Reduction)
+	msg := mockMessage(t, topics.StepVotes, 1, 1)
+	assert.Error(t, c.SendInternally(topics.StepVotes, msg, comps[0].ID()))
+}
+
 // Initialize a coordinator with a single component.
 func initCoordinatorTest(t *testing.T, tpcs ...topics.Topic) (*Coordinator, []Component) {
 	bus := eventbus.New()
@@ -219,6 +238,16 @@ func (m *mockComponent) ID() uint32 {
 	return m.id
 }
 
+func (m *mockComponent) Consumes() []topics.Topic {
+	return []topics.Topic{m.topic}
+}
+
+// String names the component after its topic, so that the components of a
+// test are told apart
+func (m *mockComponent) String() string {
+	return "mock " + m.topic.String()
+}
+
 func (m *mockComponent) Collect(ev InternalPacket) error {
 	m.receivedEvents <- ev
 	return nil
@@ -1,28 +1,26 @@
 package selection
 
 import (
-	"time"
-
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 )
 
 // Factory creates the selection component.
 type Factory struct {
-	Bus     eventbus.Broker
-	timeout time.Duration
+	Bus      eventbus.Broker
+	timeouts *consensus.TimeoutController
 }
 
 // NewFactory instantiates a Factory.
-func NewFactory(bus eventbus.Broker, timeout time.Duration) *Factory {
+func NewFactory(bus eventbus.Broker, timeouts *consensus.TimeoutController) *Factory {
 	return &Factory{
 		bus,
-		timeout,
+		timeouts,
 	}
 }
 
 // Instantiate a Selector and return it.
 // Implements consensus.ComponentFactory.
 func (f *Factory) Instantiate() consensus.Component {
-	return NewComponent(f.Bus, f.timeout)
+	return NewComponent(f.Bus, f.timeouts)
 }
@@ -23,8 +23,12 @@ type Selector struct {
 	lock      sync.RWMutex
 	bestEvent message.Score
 
-	timer   *timer
-	timeout time.Duration
+	timer    *timer
+	timeouts *consensus.TimeoutController
+	// startTime and bestTime are used to measure how long it took to
+	// receive the best score
+	startTime time.Time
+	bestTime  time.Time
 
 	scoreID uint32
 
@@ -48,9 +52,9 @@ func (e emptyScoreFactory) Create(pubkey []byte, round uint64, step uint8) conse
 // NewComponent creates and launches the component which responsibility is to validate
 // and select the best score among the blind bidders. The component publishes under
 // the topic BestScoreTopic
-func NewComponent(publisher eventbus.Publisher, timeout time.Duration) *Selector {
+func NewComponent(publisher eventbus.Publisher, timeouts *consensus.TimeoutController) *Selector {
 	return &Selector{
-		timeout:   timeout,
+		timeouts:  timeouts,
 		publisher: publisher,
 		bestEvent: message.EmptyScore(),
 	}
@@ -84,6 +88,18 @@ func (s *Selector) ID() uint32 {
 	return s.scoreID
 }
 
+// Consumes the Score and Generation topics.
+// Implements consensus.Consumer.
+func (s *Selector) Consumes() []topics.Topic {
+	return []topics.Topic{topics.Score, topics.Generation}
+}
+
+// Produces the BestScore topic.
+// Implements consensus.Producer.
+func (s *Selector) Produces() []topics.Topic {
+	return []topics.Topic{topics.BestScore}
+}
+
 // Finalize pauses event streaming and stops the timer.
 // Implements consensus.Component.
 func (s *Selector) Finalize() {
@@ -126,6 +142,7 @@ func (s *Selector) CollectScoreEvent(packet consensus.InternalPacket) error {
 		"new best": score.Score,
 	}).Debugln("swapping best score")
 	s.bestEvent = score
+	s.bestTime = s.timeouts.Clock().Now()
 	return nil
 }
 
@@ -140,18 +157,16 @@ func (s *Selector) CollectGeneration(packet consensus.InternalPacket) error {
 }
 
 func (s *Selector) startSelection() {
+	// The times of the previous selection are reset before any score of this
+	// one is collected
+	s.lock.Lock()
+	s.startTime = s.timeouts.Clock().Now()
+	s.bestTime = s.startTime
+	s.timer.start(s.timeouts.TimeOut(consensus.SelectionPhase))
+	s.lock.Unlock()
+
 	// Empty queue in a goroutine to avoid letting other listeners wait
 	go s.eventPlayer.Play(s.scoreID)
-	s.lock.RLock()
-	defer s.lock.RUnlock()
-	s.timer.start(s.timeout)
-}
-
-// IncreaseTimeOut increases the timeout after a failed selection
-func (s *Selector) IncreaseTimeOut() {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-	s.timeout = s.timeout * 2
 }
 
 func (s *Selector) sendBestEvent() error {
@@ -159,17 +174,22 @@ func (s *Selector) sendBestEvent() error {
 	s.eventPlayer.Pause(s.scoreID)
 	s.lock.RLock()
 	bestEvent = s.bestEvent
+	elapsed := s.bestTime.Sub(s.startTime)
 	s.lock.RUnlock()
 
 	// If we had no best event, we should send an empty hash
 	if bestEvent.(message.Score).IsEmpty() {
 		bestEvent = s.signer.Compose(emptyScoreFactory{})
+		s.timeouts.Failed(consensus.SelectionPhase)
+		s.eventPlayer.Trace(consensus.TraceBestScore, nil)
+	} else {
+		s.timeouts.Succeeded(consensus.SelectionPhase, elapsed)
+		s.eventPlayer.Trace(consensus.TraceBestScore, bestEvent.State().BlockHash)
 	}
 
 	msg := message.New(topics.BestScore, bestEvent)
 	s.signer.SendInternally(topics.BestScore, msg, s.ID())
 	s.handler.LowerThreshold()
-	s.IncreaseTimeOut()
 	return nil
 }
 
@@ -3,6 +3,7 @@ package selection
 import (
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
@@ -63,7 +64,7 @@ type Helper struct {
 // NewHelper creates a Helper
 func NewHelper(eb *eventbus.EventBus) *Helper {
 	bidList := consensus.MockBidList(10)
-	factory := NewFactory(eb, 1000*time.Millisecond)
+	factory := NewFactory(eb, consensus.NewTimeoutController(1000*time.Millisecond, config.ConsensusMaxTimeOut))
 	s := factory.Instantiate()
 	sel := s.(*Selector)
 	keys, _ := key.NewRandConsensusKeys()
@@ -2,15 +2,17 @@ package selection
 
 import (
 	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 )
 
 type timer struct {
 	s *Selector
-	t *time.Timer
+	t consensus.Alarm
 }
 
 func (t *timer) start(timeOut time.Duration) {
-	t.t = time.AfterFunc(timeOut, t.trigger)
+	t.t = t.s.timeouts.Clock().AfterFunc(timeOut, t.trigger)
 }
 
 func (t *timer) stop() {
@@ -0,0 +1,145 @@
+package simulation
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+)
+
+var _ consensus.Clock = (*VirtualClock)(nil)
+
+type (
+	alarm struct {
+		clock *VirtualClock
+		at    time.Time
+		// seq breaks the ties among alarms due at the same time, so that they
+		// fire in the order they were scheduled
+		seq   uint64
+		f     func()
+		index int
+	}
+
+	alarmQueue []*alarm
+
+	// VirtualClock is a consensus.Clock whose time only moves when the
+	// simulator says so. Alarms are fired synchronously by the goroutine
+	// advancing the clock.
+	VirtualClock struct {
+		lock  sync.Mutex
+		now   time.Time
+		seq   uint64
+		queue alarmQueue
+	}
+)
+
+// NewVirtualClock creates a VirtualClock set at the given time
+func NewVirtualClock(now time.Time) *VirtualClock {
+	return &VirtualClock{now: now}
+}
+
+// Now returns the virtual time
+func (c *VirtualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// AfterFunc schedules f to be called once the virtual time has advanced by d
+func (c *VirtualClock) AfterFunc(d time.Duration, f func()) consensus.Alarm {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if d < 0 {
+		d = 0
+	}
+
+	c.seq++
+	a := &alarm{clock: c, at: c.now.Add(d), seq: c.seq, f: f}
+	heap.Push(&c.queue, a)
+	return a
+}
+
+// FireNext advances the clock to the earliest pending alarm and fires all the
+// alarms due at that time. Alarms later than until are not fired: the clock
+// is moved to until and false is returned
+func (c *VirtualClock) FireNext(until time.Time) bool {
+	c.lock.Lock()
+	if len(c.queue) == 0 || c.queue[0].at.After(until) {
+		if c.now.Before(until) {
+			c.now = until
+		}
+		c.lock.Unlock()
+		return false
+	}
+
+	at := c.queue[0].at
+	c.now = at
+	due := make([]*alarm, 0)
+	for len(c.queue) > 0 && !c.queue[0].at.After(at) {
+		due = append(due, heap.Pop(&c.queue).(*alarm))
+	}
+	c.lock.Unlock()
+
+	// the alarms are fired without holding the lock, as they are likely to
+	// schedule new ones
+	for _, a := range due {
+		a.f()
+	}
+
+	return true
+}
+
+// Pending returns the amount of alarms yet to fire
+func (c *VirtualClock) Pending() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.queue)
+}
+
+// Stop cancels the alarm. It returns false if the alarm already fired or was
+// stopped
+func (a *alarm) Stop() bool {
+	a.clock.lock.Lock()
+	defer a.clock.lock.Unlock()
+
+	if a.index < 0 {
+		return false
+	}
+
+	heap.Remove(&a.clock.queue, a.index)
+	return true
+}
+
+func (q alarmQueue) Len() int { return len(q) }
+
+func (q alarmQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at.Before(q[j].at)
+}
+
+func (q alarmQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *alarmQueue) Push(x interface{}) {
+	a := x.(*alarm)
+	a.index = len(*q)
+	*q = append(*q, a)
+}
+
+func (q *alarmQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	a := old[n-1]
+	old[n-1] = nil
+	// an index of -1 marks the alarm as no longer pending
+	a.index = -1
+	*q = old[:n-1]
+	return a
+}
@@ -0,0 +1,313 @@
+package simulation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-crypto/bls"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+)
+
+// Behaviour of a simulated node toward the rest of the network
+type Behaviour uint8
+
+const (
+	// Honest nodes follow the protocol
+	Honest Behaviour = iota
+	// Silent nodes never send any message
+	Silent
+	// Equivocating nodes send their votes to half of their peers and a
+	// conflicting vote, for a different block hash, to the other half
+	Equivocating
+	// Late nodes send all their messages with an extra delay
+	Late
+)
+
+var behaviourNames = [...]string{"honest", "silent", "equivocating", "late"}
+
+func (b Behaviour) String() string {
+	if int(b) < len(behaviourNames) {
+		return behaviourNames[b]
+	}
+	return "unknown"
+}
+
+// Rules determine how the virtual network treats the messages. Every
+// decision is derived from the simulation seed and the message being sent,
+// so that it does not depend on the order the nodes gossip in.
+type Rules struct {
+	// DropRate is the probability for a message not to reach a peer
+	DropRate float64
+	// MinDelay and MaxDelay bound the latency of a message. Latencies are
+	// picked at random in between, which reorders the messages
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// LateDelay is added to the latency of the messages sent by Late nodes
+	LateDelay time.Duration
+	// Tick is the granularity of the latencies. Messages reaching a node in
+	// the same tick are processed together
+	Tick time.Duration
+}
+
+type (
+	// deliveryKey identifies a message sent to a peer. It is used to
+	// discard the copies relayed by other nodes, as the dupemap would
+	deliveryKey struct {
+		to     int
+		digest [sha256.Size]byte
+	}
+
+	draw struct {
+		drop  bool
+		delay time.Duration
+		// conflicting tells an Equivocating node to send the conflicting
+		// vote to this peer
+		conflicting bool
+	}
+
+	// network connects the simulated nodes. Every message gossiped by a node
+	// is delivered to all of them, the sender included, according to the
+	// Rules and to the Behaviour of the sender.
+	network struct {
+		seed  int64
+		rules Rules
+		clock *VirtualClock
+		nodes []*node
+
+		lock  sync.Mutex
+		round uint64
+		seen  map[deliveryKey]uint64
+
+		delivered uint64
+		dropped   uint64
+		// activity is increased every time a node gossips. It is used to
+		// detect when the nodes are done processing
+		activity uint64
+	}
+)
+
+func newNetwork(seed int64, rules Rules, clock *VirtualClock) *network {
+	return &network{
+		seed:  seed,
+		rules: rules,
+		clock: clock,
+		seen:  make(map[deliveryKey]uint64),
+	}
+}
+
+// gossip returns the callback collecting the messages gossiped by a node
+func (n *network) gossip(from int) func(message.Message) error {
+	return func(m message.Message) error {
+		buf := m.Payload().(bytes.Buffer)
+		return n.broadcast(from, buf.Bytes())
+	}
+}
+
+func (n *network) broadcast(from int, data []byte) error {
+	atomic.AddUint64(&n.activity, 1)
+
+	sender := n.nodes[from]
+	if sender.behaviour == Silent {
+		atomic.AddUint64(&n.dropped, uint64(len(n.nodes)))
+		return nil
+	}
+
+	// the payload is copied, as the buffer is shared with the sender
+	payload := make([]byte, len(data))
+	copy(payload, data)
+
+	m, err := message.Unmarshal(bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+
+	var hdr header.Header
+	if p, ok := m.Payload().(consensus.InternalPacket); ok {
+		hdr = p.State()
+	}
+
+	var conflicting []byte
+	if sender.behaviour == Equivocating && bytes.Equal(hdr.PubKeyBLS, sender.keys.BLSPubKeyBytes) {
+		if conflicting, err = equivocate(sender.keys, m); err != nil {
+			return err
+		}
+	}
+
+	for to := range n.nodes {
+		if to == from {
+			// a node always gets its own messages back
+			n.send(to, payload, draw{})
+			continue
+		}
+
+		d := n.draw(m.Category(), hdr, from, to)
+		if sender.behaviour == Late {
+			d.delay += n.rules.LateDelay
+		}
+
+		if conflicting != nil && d.conflicting {
+			n.send(to, conflicting, d)
+			continue
+		}
+
+		n.send(to, payload, d)
+	}
+
+	return nil
+}
+
+// draw the fate of a message sent to a peer
+func (n *network) draw(topic topics.Topic, hdr header.Header, from, to int) draw {
+	h := sha256.New()
+	_ = binary.Write(h, binary.LittleEndian, n.seed)
+	_ = binary.Write(h, binary.LittleEndian, uint8(topic))
+	_ = binary.Write(h, binary.LittleEndian, hdr.Round)
+	_ = binary.Write(h, binary.LittleEndian, hdr.Step)
+	_, _ = h.Write(hdr.PubKeyBLS)
+	_, _ = h.Write(hdr.BlockHash)
+	_ = binary.Write(h, binary.LittleEndian, uint32(from))
+	_ = binary.Write(h, binary.LittleEndian, uint32(to))
+	sum := h.Sum(nil)
+
+	// 53 bits are enough for an evenly distributed float64 in [0, 1)
+	p := float64(binary.LittleEndian.Uint64(sum[:8])>>11) / (1 << 53)
+	d := draw{
+		drop:        p < n.rules.DropRate,
+		delay:       n.rules.MinDelay,
+		conflicting: sum[16]&1 == 1,
+	}
+
+	if spread := n.rules.MaxDelay - n.rules.MinDelay; spread > 0 {
+		d.delay += time.Duration(binary.LittleEndian.Uint64(sum[8:16]) % uint64(spread))
+	}
+
+	return d
+}
+
+func (n *network) send(to int, payload []byte, d draw) {
+	k := deliveryKey{to, sha256.Sum256(payload)}
+
+	n.lock.Lock()
+	if _, ok := n.seen[k]; ok {
+		n.lock.Unlock()
+		return
+	}
+
+	if d.drop {
+		n.lock.Unlock()
+		atomic.AddUint64(&n.dropped, 1)
+		return
+	}
+
+	n.seen[k] = n.round
+	n.lock.Unlock()
+
+	atomic.AddUint64(&n.delivered, 1)
+	n.clock.AfterFunc(n.quantize(d.delay), func() {
+		n.deliver(to, payload)
+	})
+}
+
+func (n *network) deliver(to int, payload []byte) {
+	buf := make([]byte, len(payload))
+	copy(buf, payload)
+
+	m, err := message.Unmarshal(bytes.NewBuffer(buf))
+	if err != nil {
+		lg.WithError(err).Warnln("could not deliver message")
+		return
+	}
+
+	n.nodes[to].bus.Publish(m.Category(), m)
+}
+
+// quantize rounds a latency up to the next Tick
+func (n *network) quantize(d time.Duration) time.Duration {
+	if n.rules.Tick <= 0 {
+		return d
+	}
+
+	return ((d + n.rules.Tick - 1) / n.rules.Tick) * n.rules.Tick
+}
+
+// prune forgets the messages of the rounds preceding the previous one. Late
+// copies of such messages are delivered again, but the nodes discard them
+// as obsolete anyway
+func (n *network) prune(round uint64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.round = round
+	for k, r := range n.seen {
+		if r+1 < round {
+			delete(n.seen, k)
+		}
+	}
+}
+
+// equivocate creates a vote conflicting with the one in the message. It
+// returns nil if the message is not a vote
+func equivocate(keys key.ConsensusKeys, m message.Message) ([]byte, error) {
+	var conflicting message.Message
+	switch p := m.Payload().(type) {
+	case message.Reduction:
+		hdr := p.State()
+		hdr.BlockHash = conflictingHash(hdr)
+		sig, err := sign(keys, hdr)
+		if err != nil {
+			return nil, err
+		}
+
+		red := message.NewReduction(hdr)
+		red.SignedHash = sig
+		conflicting = message.New(topics.Reduction, *red)
+	case message.Agreement:
+		hdr := p.State()
+		hdr.BlockHash = conflictingHash(hdr)
+		sig, err := sign(keys, hdr)
+		if err != nil {
+			return nil, err
+		}
+
+		ag := message.NewAgreement(hdr)
+		ag.SetSignature(sig)
+		ag.VotesPerStep = p.VotesPerStep
+		conflicting = message.New(topics.Agreement, *ag)
+	default:
+		return nil, nil
+	}
+
+	buf, err := message.Marshal(conflicting)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func conflictingHash(hdr header.Header) []byte {
+	h := sha256.Sum256(append(append([]byte{}, hdr.BlockHash...), hdr.Step))
+	return h[:]
+}
+
+func sign(keys key.ConsensusKeys, hdr header.Header) ([]byte, error) {
+	preimage := new(bytes.Buffer)
+	if err := header.MarshalSignableVote(preimage, hdr); err != nil {
+		return nil, err
+	}
+
+	sig, err := bls.Sign(keys.BLSSecretKey, keys.BLSPubKey, preimage.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.Compress(), nil
+}
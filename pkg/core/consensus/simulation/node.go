@@ -0,0 +1,100 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/agreement"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/generation"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction/firststep"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/reduction/secondstep"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+)
+
+// node is a simulated provisioner. It runs a Coordinator with its own
+// EventBus and RPCBus, and the consensus components from the reduction
+// onward. Candidate blocks are always found and valid.
+type node struct {
+	index     int
+	keys      key.ConsensusKeys
+	behaviour Behaviour
+	bus       *eventbus.EventBus
+	rpcBus    *rpcbus.RPCBus
+	timeouts  *consensus.TimeoutController
+
+	// round is the round the node is running. It is guarded by the
+	// Simulator lock
+	round uint64
+	quit  chan struct{}
+}
+
+func newNode(index int, keys key.ConsensusKeys, behaviour Behaviour, clock consensus.Clock, timeOut time.Duration) (*node, error) {
+	n := &node{
+		index:     index,
+		keys:      keys,
+		behaviour: behaviour,
+		bus:       eventbus.New(),
+		rpcBus:    rpcbus.New(),
+		timeouts:  consensus.NewTimeoutController(timeOut, config.ConsensusMaxTimeOut),
+		quit:      make(chan struct{}),
+	}
+	n.timeouts.SetClock(clock)
+
+	getCandidateChan := make(chan rpcbus.Request, 1)
+	if err := n.rpcBus.Register(topics.GetCandidate, getCandidateChan); err != nil {
+		return nil, err
+	}
+
+	verifyCandidateChan := make(chan rpcbus.Request, 1)
+	if err := n.rpcBus.Register(topics.VerifyCandidateBlock, verifyCandidateChan); err != nil {
+		return nil, err
+	}
+
+	go n.serveCandidates(getCandidateChan, verifyCandidateChan)
+
+	// The nodes keep their own journal, in memory, as they share the
+	// configuration
+	journal, err := consensus.OpenJournal("")
+	if err != nil {
+		return nil, err
+	}
+
+	consensus.StartWithJournal(n.bus, keys, journal,
+		generation.NewFactory(),
+		&proposerFactory{n.timeouts},
+		firststep.NewFactory(n.bus, n.rpcBus, keys, n.timeouts),
+		secondstep.NewFactory(n.bus, n.rpcBus, keys, n.timeouts),
+		agreement.NewFactory(n.bus, keys),
+	)
+
+	return n, nil
+}
+
+func (n *node) serveCandidates(getCandidateChan, verifyCandidateChan <-chan rpcbus.Request) {
+	for {
+		select {
+		case r := <-getCandidateChan:
+			r.RespChan <- rpcbus.Response{Resp: message.Candidate{}, Err: nil}
+		case r := <-verifyCandidateChan:
+			r.RespChan <- rpcbus.Response{Resp: nil, Err: nil}
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// startRound feeds the node with the RoundUpdate, as the Chain would after
+// accepting a block
+func (n *node) startRound(ru consensus.RoundUpdate) {
+	n.bus.Publish(topics.RoundUpdate, message.New(topics.RoundUpdate, ru))
+}
+
+func (n *node) stop() {
+	n.bus.Publish(topics.StopConsensus, message.New(topics.StopConsensus, nil))
+	close(n.quit)
+}
@@ -0,0 +1,122 @@
+package simulation
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+var _ consensus.Component = (*proposer)(nil)
+
+type (
+	proposerFactory struct {
+		timeouts *consensus.TimeoutController
+	}
+
+	// proposer stands in for the score generation and selection, which
+	// require zero-knowledge proofs. Once the selection timeout expires, it
+	// proposes the candidate of the round for reduction. The candidate hash
+	// is derived from the hash of the previous block, so that all nodes
+	// propose the same one.
+	proposer struct {
+		timeouts    *consensus.TimeoutController
+		eventPlayer consensus.EventPlayer
+		signer      consensus.Signer
+		candidate   []byte
+		id          uint32
+
+		lock  sync.Mutex
+		alarm consensus.Alarm
+	}
+
+	candidateFactory struct {
+		hash []byte
+	}
+)
+
+// Instantiate a proposer.
+// Implements consensus.ComponentFactory
+func (f *proposerFactory) Instantiate() consensus.Component {
+	return &proposer{timeouts: f.timeouts}
+}
+
+// Create the BestScore packet for the candidate
+func (c candidateFactory) Create(sender []byte, round uint64, step uint8) consensus.InternalPacket {
+	return header.Header{
+		Round:     round,
+		Step:      step,
+		PubKeyBLS: sender,
+		BlockHash: c.hash,
+	}
+}
+
+// Initialize the proposer by subscribing to the Generation topic.
+// Implements consensus.Component
+func (p *proposer) Initialize(eventPlayer consensus.EventPlayer, signer consensus.Signer, ru consensus.RoundUpdate) []consensus.TopicListener {
+	p.eventPlayer = eventPlayer
+	p.signer = signer
+	p.candidate = candidateHash(ru.Hash)
+
+	generationSubscriber := consensus.TopicListener{
+		Topic:    topics.Generation,
+		Listener: consensus.NewSimpleListener(p.collectGeneration, consensus.HighPriority, false),
+	}
+	p.id = generationSubscriber.Listener.ID()
+
+	return []consensus.TopicListener{generationSubscriber}
+}
+
+// ID of the Generation listener.
+// Implements consensus.Component
+func (p *proposer) ID() uint32 {
+	return p.id
+}
+
+// Consumes the Generation topic.
+// Implements consensus.Consumer
+func (p *proposer) Consumes() []topics.Topic {
+	return []topics.Topic{topics.Generation}
+}
+
+// Produces the BestScore topic.
+// Implements consensus.Producer
+func (p *proposer) Produces() []topics.Topic {
+	return []topics.Topic{topics.BestScore}
+}
+
+// Finalize stops the selection timer.
+// Implements consensus.Component
+func (p *proposer) Finalize() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.alarm != nil {
+		p.alarm.Stop()
+	}
+}
+
+// collectGeneration starts the selection step, as the Selector would
+func (p *proposer) collectGeneration(consensus.InternalPacket) error {
+	_ = p.eventPlayer.Forward(p.ID())
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.alarm = p.timeouts.Clock().AfterFunc(p.timeouts.TimeOut(consensus.SelectionPhase), p.sendBestScore)
+	return nil
+}
+
+func (p *proposer) sendBestScore() {
+	best := p.signer.Compose(candidateFactory{p.candidate})
+	msg := message.New(topics.BestScore, best)
+	if err := p.signer.SendInternally(topics.BestScore, msg, p.ID()); err != nil {
+		lg.WithError(err).Warnln("could not propose the candidate")
+	}
+}
+
+func candidateHash(prevBlockHash []byte) []byte {
+	h := sha256.Sum256(prevBlockHash)
+	return h[:]
+}
@@ -0,0 +1,352 @@
+package simulation
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	log "github.com/sirupsen/logrus"
+)
+
+var lg = log.WithField("process", "simulation")
+
+// idlePasses is the number of passes the buses of the nodes need to be idle
+// for the nodes to be considered done with the current instant
+const idlePasses = 10
+
+type (
+	// Config of a simulation
+	Config struct {
+		// Seed drives the network Rules and the hashes of the simulated
+		// blocks
+		Seed int64
+		// Nodes is the amount of provisioners
+		Nodes int
+		// Byzantine maps the index of a node to its Behaviour. The nodes
+		// which are not listed are Honest
+		Byzantine map[int]Behaviour
+		Rules     Rules
+		// TimeOut is the base timeout of the consensus phases
+		TimeOut time.Duration
+		// RoundDeadline is the virtual time given to a round to produce a
+		// certificate. Past it, the round is considered stalled
+		RoundDeadline time.Duration
+	}
+
+	// Report of a simulation
+	Report struct {
+		// Rounds is the amount of rounds completed by all nodes
+		Rounds uint64
+		// Certificates holds, for each round, how many nodes certified each
+		// block hash
+		Certificates map[uint64]map[string]int
+		// Conflicts lists the rounds in which different block hashes were
+		// certified. It is empty unless safety is broken
+		Conflicts []uint64
+		// Stalled is the round which did not produce any certificate before
+		// the deadline. It is zero unless liveness is broken
+		Stalled   uint64
+		Delivered uint64
+		Dropped   uint64
+		// Elapsed is the virtual time the simulation took
+		Elapsed time.Duration
+	}
+
+	// Simulator runs the consensus of a set of provisioners within the same
+	// process. The nodes are linked through a virtual network, and their
+	// timers run against a virtual clock controlled by the Simulator. The
+	// Simulator also plays the part of the Chain, by propagating the
+	// certified blocks and starting the new rounds.
+	//
+	// The fate of every message, as well as the block hashes, only depends
+	// on the seed. The virtual clock is only advanced once the buses of all
+	// nodes are drained, so that the timers never fire while the events of
+	// the current instant are being processed.
+	Simulator struct {
+		cfg   Config
+		clock *VirtualClock
+		start time.Time
+		net   *network
+		nodes []*node
+		p     *user.Provisioners
+
+		lock         sync.Mutex
+		updates      map[uint64]consensus.RoundUpdate
+		certificates map[uint64]map[string]int
+		conflicts    []uint64
+	}
+)
+
+// DefaultConfig returns the Config of a reliable network of seven honest
+// provisioners
+func DefaultConfig(seed int64) Config {
+	return Config{
+		Seed:  seed,
+		Nodes: 7,
+		Rules: Rules{
+			MinDelay:  10 * time.Millisecond,
+			MaxDelay:  200 * time.Millisecond,
+			LateDelay: 2 * time.Second,
+			Tick:      50 * time.Millisecond,
+		},
+		TimeOut:       5 * time.Second,
+		RoundDeadline: 5 * time.Minute,
+	}
+}
+
+// New creates a Simulator and its nodes. The nodes wait for Run to be called
+func New(cfg Config) (*Simulator, error) {
+	if cfg.Nodes <= 0 {
+		return nil, errors.New("a simulation needs at least one node")
+	}
+
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	p, keys := consensus.MockProvisioners(cfg.Nodes)
+
+	s := &Simulator{
+		cfg:          cfg,
+		clock:        clock,
+		start:        start,
+		net:          newNetwork(cfg.Seed, cfg.Rules, clock),
+		nodes:        make([]*node, 0, cfg.Nodes),
+		p:            p,
+		updates:      make(map[uint64]consensus.RoundUpdate),
+		certificates: make(map[uint64]map[string]int),
+	}
+
+	for i, k := range keys {
+		n, err := newNode(i, k, cfg.Byzantine[i], clock, cfg.TimeOut)
+		if err != nil {
+			return nil, err
+		}
+
+		n.bus.Subscribe(topics.Gossip, eventbus.NewCallbackListener(s.net.gossip(i)))
+		n.bus.Subscribe(topics.Certificate, eventbus.NewCallbackListener(s.collectCertificate(i)))
+		s.nodes = append(s.nodes, n)
+	}
+
+	s.net.nodes = s.nodes
+	return s, nil
+}
+
+// Run the consensus for the given amount of rounds, starting from round 1.
+// It stops early if a round stalls
+func (s *Simulator) Run(rounds uint64) Report {
+	for _, n := range s.nodes {
+		s.advance(n, 1)
+	}
+
+	var stalled, completed uint64
+	for round := uint64(1); round <= rounds; round++ {
+		s.net.prune(round)
+		deadline := s.clock.Now().Add(s.cfg.RoundDeadline)
+		for !s.completed(round) {
+			s.settle()
+			if s.clock.FireNext(deadline) {
+				continue
+			}
+
+			// nothing is left to happen before the deadline
+			if !s.completed(round) {
+				stalled = round
+			}
+			break
+		}
+
+		if stalled != 0 {
+			lg.WithField("round", round).Errorln("round stalled")
+			break
+		}
+
+		completed = round
+	}
+
+	return s.report(completed, stalled)
+}
+
+// Stop the consensus of all nodes
+func (s *Simulator) Stop() {
+	for _, n := range s.nodes {
+		n.stop()
+	}
+}
+
+func (s *Simulator) report(completed, stalled uint64) Report {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	certificates := make(map[uint64]map[string]int, len(s.certificates))
+	for round, hashes := range s.certificates {
+		certificates[round] = make(map[string]int, len(hashes))
+		for hash, count := range hashes {
+			certificates[round][hash] = count
+		}
+	}
+
+	return Report{
+		Rounds:       completed,
+		Certificates: certificates,
+		Conflicts:    append([]uint64(nil), s.conflicts...),
+		Stalled:      stalled,
+		Delivered:    atomic.LoadUint64(&s.net.delivered),
+		Dropped:      atomic.LoadUint64(&s.net.dropped),
+		Elapsed:      s.clock.Now().Sub(s.start),
+	}
+}
+
+// collectCertificate returns the callback recording the certificates
+// produced by a node. The first certificate of a round is turned into a
+// block, which is propagated to the other nodes
+func (s *Simulator) collectCertificate(i int) func(message.Message) error {
+	return func(m message.Message) error {
+		atomic.AddUint64(&s.net.activity, 1)
+		hdr := m.Payload().(message.Agreement).State()
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		hashes, ok := s.certificates[hdr.Round]
+		if !ok {
+			hashes = make(map[string]int)
+			s.certificates[hdr.Round] = hashes
+		}
+
+		hashes[string(hdr.BlockHash)]++
+		// safety is broken as soon as a second block hash gets certified
+		if len(hashes) == 2 && hashes[string(hdr.BlockHash)] == 1 {
+			lg.WithField("round", hdr.Round).Errorln("conflicting certificates")
+			s.conflicts = append(s.conflicts, hdr.Round)
+		}
+
+		s.schedule(i, hdr.Round+1, 0)
+		if !ok {
+			s.propagate(i, hdr)
+		}
+
+		return nil
+	}
+}
+
+// propagate the block certified by a node to the others
+func (s *Simulator) propagate(from int, hdr header.Header) {
+	for to := range s.nodes {
+		if to == from {
+			continue
+		}
+
+		d := s.net.draw(topics.Block, hdr, from, to)
+		s.schedule(to, hdr.Round+1, s.net.quantize(d.delay))
+	}
+}
+
+func (s *Simulator) schedule(i int, round uint64, delay time.Duration) {
+	s.clock.AfterFunc(delay, func() {
+		s.advance(s.nodes[i], round)
+	})
+}
+
+// advance a node to the given round, unless it got there already
+func (s *Simulator) advance(n *node, round uint64) {
+	s.lock.Lock()
+	if n.round >= round {
+		s.lock.Unlock()
+		return
+	}
+
+	n.round = round
+	ru := s.roundUpdate(round)
+	s.lock.Unlock()
+
+	n.startRound(ru)
+}
+
+// roundUpdate returns the RoundUpdate of a round. The hash of the previous
+// block is derived from the seed
+func (s *Simulator) roundUpdate(round uint64) consensus.RoundUpdate {
+	if ru, ok := s.updates[round]; ok {
+		return ru
+	}
+
+	h := sha256.New()
+	_ = binary.Write(h, binary.LittleEndian, s.cfg.Seed)
+	_ = binary.Write(h, binary.LittleEndian, round)
+	hash := h.Sum(nil)
+
+	seed := make([]byte, 33)
+	copy(seed, hash)
+
+	ru := consensus.RoundUpdate{
+		Round: round,
+		P:     *s.p,
+		Seed:  seed,
+		Hash:  hash,
+	}
+
+	s.updates[round] = ru
+	delete(s.updates, round-2)
+	return ru
+}
+
+// completed returns true if all nodes moved past the given round
+func (s *Simulator) completed(round uint64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, n := range s.nodes {
+		if n.round <= round {
+			return false
+		}
+	}
+
+	return true
+}
+
+// settle drains the buses of the nodes until they stop gossiping
+func (s *Simulator) settle() {
+	for {
+		before := atomic.LoadUint64(&s.net.activity)
+		s.drain()
+		if atomic.LoadUint64(&s.net.activity) == before {
+			return
+		}
+	}
+}
+
+// drain yields to the nodes until no message is being forwarded on their
+// buses for idlePasses passes in a row. The passes give the goroutines
+// started by the components the chance to publish their results
+func (s *Simulator) drain() {
+	for idle := 0; idle < idlePasses; {
+		runtime.Gosched()
+
+		idle++
+		for _, n := range s.nodes {
+			if !n.bus.Idle() {
+				idle = 0
+				break
+			}
+		}
+	}
+}
+
+// Safe returns true if no two different blocks were certified in the same
+// round
+func (r Report) Safe() bool {
+	return len(r.Conflicts) == 0
+}
+
+// Live returns true if every round produced a certificate in time
+func (r Report) Live() bool {
+	return r.Stalled == 0
+}
@@ -0,0 +1,132 @@
+package simulation
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the simulations are kept short by default, besides TestLongRun which is
+// skipped in short mode. Run with -sim.rounds=5000 for a soak test
+var (
+	rounds     = flag.Uint64("sim.rounds", 30, "amount of rounds run by each simulation")
+	longRounds = flag.Uint64("sim.longrounds", 1000, "amount of rounds run by the long simulation")
+)
+
+func simulate(t *testing.T, cfg Config) Report {
+	return simulateRounds(t, cfg, *rounds)
+}
+
+func simulateRounds(t *testing.T, cfg Config, rounds uint64) Report {
+	s, err := New(cfg)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer s.Stop()
+
+	r := s.Run(rounds)
+	t.Logf("%d rounds, %d messages delivered, %d dropped, %s of virtual time", r.Rounds, r.Delivered, r.Dropped, r.Elapsed)
+	return r
+}
+
+func TestHonestNetwork(t *testing.T) {
+	r := simulate(t, DefaultConfig(1))
+	assert.True(t, r.Safe())
+	assert.True(t, r.Live())
+	assert.Equal(t, *rounds, r.Rounds)
+
+	// every round gets a single block certified
+	for round := uint64(1); round <= r.Rounds; round++ {
+		assert.Equal(t, 1, len(r.Certificates[round]))
+	}
+}
+
+func TestLossyNetwork(t *testing.T) {
+	cfg := DefaultConfig(2)
+	cfg.Rules.DropRate = 0.1
+	cfg.Rules.MaxDelay = time.Second
+
+	r := simulate(t, cfg)
+	assert.True(t, r.Safe())
+	assert.True(t, r.Live())
+	assert.Equal(t, *rounds, r.Rounds)
+	assert.NotZero(t, r.Dropped)
+}
+
+func TestByzantineNodes(t *testing.T) {
+	for _, b := range []Behaviour{Silent, Equivocating, Late} {
+		t.Run(b.String(), func(t *testing.T) {
+			cfg := DefaultConfig(3)
+			cfg.Byzantine = map[int]Behaviour{0: b}
+
+			r := simulate(t, cfg)
+			assert.True(t, r.Safe())
+			assert.True(t, r.Live())
+			assert.Equal(t, *rounds, r.Rounds)
+		})
+	}
+}
+
+// Test that safety holds with as many equivocating nodes as the protocol
+// tolerates
+func TestEquivocatingMinority(t *testing.T) {
+	cfg := DefaultConfig(4)
+	cfg.Nodes = 10
+	cfg.Byzantine = map[int]Behaviour{0: Equivocating, 1: Equivocating}
+
+	r := simulate(t, cfg)
+	assert.True(t, r.Safe())
+}
+
+// Test that the network stalls, rather than forking, when too many
+// provisioners are silent
+func TestSilentMajority(t *testing.T) {
+	cfg := DefaultConfig(5)
+	cfg.Byzantine = map[int]Behaviour{0: Silent, 1: Silent, 2: Silent, 3: Silent}
+	cfg.RoundDeadline = time.Minute
+
+	r := simulate(t, cfg)
+	assert.True(t, r.Safe())
+	assert.False(t, r.Live())
+	assert.Equal(t, uint64(1), r.Stalled)
+}
+
+// Test that safety and liveness hold over a long run of a lossy network with
+// a byzantine node
+func TestLongRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the long simulation in short mode")
+	}
+
+	cfg := DefaultConfig(6)
+	cfg.Nodes = 10
+	cfg.Rules.DropRate = 0.05
+	cfg.Byzantine = map[int]Behaviour{0: Equivocating, 1: Late}
+
+	r := simulateRounds(t, cfg, *longRounds)
+	assert.True(t, r.Safe())
+	assert.True(t, r.Live())
+	assert.Equal(t, *longRounds, r.Rounds)
+}
+
+func TestVirtualClock(t *testing.T) {
+	c := NewVirtualClock(time.Unix(0, 0))
+	fired := make([]int, 0)
+	c.AfterFunc(2*time.Second, func() { fired = append(fired, 2) })
+	c.AfterFunc(time.Second, func() { fired = append(fired, 1) })
+	a := c.AfterFunc(time.Second, func() { fired = append(fired, 3) })
+	assert.True(t, a.Stop())
+	assert.False(t, a.Stop())
+
+	assert.True(t, c.FireNext(time.Unix(10, 0)))
+	assert.Equal(t, []int{1}, fired)
+	assert.Equal(t, time.Unix(1, 0), c.Now())
+
+	// alarms past the limit are not fired
+	assert.False(t, c.FireNext(time.Unix(1, 500)))
+	assert.Equal(t, 1, c.Pending())
+	assert.True(t, c.FireNext(time.Unix(10, 0)))
+	assert.Equal(t, []int{1, 2}, fired)
+}
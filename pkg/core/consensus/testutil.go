@@ -58,6 +58,9 @@ func (s *SimplePlayer) Play(id uint32) {
 	s.state = RUNNING
 }
 
+// Trace as specified by the EventPlayer interface. Events are not recorded
+func (s *SimplePlayer) Trace(TraceKind, []byte) {}
+
 // State s a threadsafe method to return whether the player is paused or not
 func (s *SimplePlayer) State() State {
 	s.lock.RLock()
@@ -0,0 +1,195 @@
+package consensus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+)
+
+// TimeoutPhase identifies a phase of the consensus loop driven by a timer
+type TimeoutPhase uint8
+
+const (
+	// SelectionPhase is the collection of the Score events
+	SelectionPhase TimeoutPhase = iota
+	// FirstReductionPhase is the first step of the Reduction
+	FirstReductionPhase
+	// SecondReductionPhase is the second step of the Reduction
+	SecondReductionPhase
+
+	timeoutPhasesNum
+)
+
+var timeoutPhaseNames = [timeoutPhasesNum]string{"selection", "firststep", "secondstep"}
+
+func (p TimeoutPhase) String() string {
+	if p < timeoutPhasesNum {
+		return timeoutPhaseNames[p]
+	}
+	return "unknown"
+}
+
+const (
+	// latencyWindow is the number of successful phases tracked to estimate
+	// the network latency
+	latencyWindow = 10
+	// latencyMargin multiplies the slowest recent phase duration to get the
+	// timeout
+	latencyMargin = 2
+)
+
+type (
+	phaseTimeout struct {
+		timeOut time.Duration
+		// recent holds the durations of the last successful phases
+		recent   []time.Duration
+		next     int
+		failures uint
+	}
+
+	// TimeoutState is a snapshot of the timeout of a consensus phase. It is
+	// returned for monitoring purposes
+	TimeoutState struct {
+		Phase   string
+		TimeOut time.Duration
+		// Slowest is the slowest of the recent successful phases
+		Slowest time.Duration
+		// Failures is the number of consecutive phases ended by a timeout
+		Failures uint
+	}
+
+	// TimeoutController adapts the timeouts of the consensus phases to the
+	// observed network latency. A timeout is doubled, up to a cap, every time
+	// a phase fails. After a successful phase, the timeout shrinks back toward
+	// a multiple of the slowest recent phase duration, though never below the
+	// base timeout. It is shared among the components of all rounds and
+	// safe for concurrent use. It also holds the Clock the timers of the
+	// components are run against.
+	TimeoutController struct {
+		lock   sync.RWMutex
+		clock  Clock
+		base   time.Duration
+		max    time.Duration
+		phases [timeoutPhasesNum]phaseTimeout
+	}
+)
+
+// NewTimeoutController creates a TimeoutController with all timeouts set to
+// base and capped at max. The timers run against the SystemClock
+func NewTimeoutController(base, max time.Duration) *TimeoutController {
+	if max < base {
+		max = base
+	}
+
+	t := &TimeoutController{clock: SystemClock, base: base, max: max}
+	for i := range t.phases {
+		t.phases[i] = phaseTimeout{
+			timeOut: base,
+			recent:  make([]time.Duration, 0, latencyWindow),
+		}
+	}
+	return t
+}
+
+// SetClock replaces the Clock the consensus timers are run against
+func (t *TimeoutController) SetClock(c Clock) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.clock = c
+}
+
+// Clock returns the Clock the consensus timers are run against
+func (t *TimeoutController) Clock() Clock {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.clock
+}
+
+// TimeOut returns the current timeout of the given phase
+func (t *TimeoutController) TimeOut(p TimeoutPhase) time.Duration {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.phases[p].timeOut
+}
+
+// Failed doubles the timeout of a phase which did not reach a result in time
+func (t *TimeoutController) Failed(p TimeoutPhase) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ph := &t.phases[p]
+	ph.failures++
+	ph.timeOut *= 2
+	if ph.timeOut > t.max {
+		ph.timeOut = t.max
+	}
+}
+
+// Succeeded records how long a successful phase took and adjusts its timeout.
+// The timeout shrinks at most by half per successful phase, so that a single
+// fast phase does not bring it down too aggressively
+func (t *TimeoutController) Succeeded(p TimeoutPhase, elapsed time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ph := &t.phases[p]
+	ph.failures = 0
+	if len(ph.recent) < latencyWindow {
+		ph.recent = append(ph.recent, elapsed)
+	} else {
+		ph.recent[ph.next] = elapsed
+	}
+	ph.next = (ph.next + 1) % latencyWindow
+
+	target := latencyMargin * ph.slowest()
+	if target < t.base {
+		target = t.base
+	}
+
+	if target > t.max {
+		target = t.max
+	}
+
+	if target < ph.timeOut/2 {
+		target = ph.timeOut / 2
+	}
+
+	ph.timeOut = target
+}
+
+// State returns a snapshot of the timeouts of all phases
+func (t *TimeoutController) State() []TimeoutState {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	state := make([]TimeoutState, 0, len(t.phases))
+	for i, ph := range t.phases {
+		state = append(state, TimeoutState{
+			Phase:    TimeoutPhase(i).String(),
+			TimeOut:  ph.timeOut,
+			Slowest:  ph.slowest(),
+			Failures: ph.failures,
+		})
+	}
+
+	return state
+}
+
+// Serve the requests for the timeouts state coming from the RPCBus. It is
+// meant to be run in its own goroutine
+func (t *TimeoutController) Serve(reqChan <-chan rpcbus.Request) {
+	for r := range reqChan {
+		r.RespChan <- rpcbus.Response{Resp: t.State(), Err: nil}
+	}
+}
+
+func (ph phaseTimeout) slowest() time.Duration {
+	var slowest time.Duration
+	for _, d := range ph.recent {
+		if d > slowest {
+			slowest = d
+		}
+	}
+	return slowest
+}
@@ -0,0 +1,63 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutGrowth(t *testing.T) {
+	c := NewTimeoutController(time.Second, 5*time.Second)
+
+	c.Failed(FirstReductionPhase)
+	assert.Equal(t, 2*time.Second, c.TimeOut(FirstReductionPhase))
+	c.Failed(FirstReductionPhase)
+	assert.Equal(t, 4*time.Second, c.TimeOut(FirstReductionPhase))
+
+	// growth is capped
+	c.Failed(FirstReductionPhase)
+	assert.Equal(t, 5*time.Second, c.TimeOut(FirstReductionPhase))
+
+	// other phases are not affected
+	assert.Equal(t, time.Second, c.TimeOut(SelectionPhase))
+}
+
+func TestTimeoutShrink(t *testing.T) {
+	c := NewTimeoutController(time.Second, 16*time.Second)
+	for i := 0; i < 4; i++ {
+		c.Failed(SecondReductionPhase)
+	}
+	assert.Equal(t, 16*time.Second, c.TimeOut(SecondReductionPhase))
+
+	// the timeout is halved at most per successful phase
+	c.Succeeded(SecondReductionPhase, 100*time.Millisecond)
+	assert.Equal(t, 8*time.Second, c.TimeOut(SecondReductionPhase))
+	c.Succeeded(SecondReductionPhase, 100*time.Millisecond)
+	c.Succeeded(SecondReductionPhase, 100*time.Millisecond)
+	c.Succeeded(SecondReductionPhase, 100*time.Millisecond)
+
+	// never below the base timeout
+	assert.Equal(t, time.Second, c.TimeOut(SecondReductionPhase))
+}
+
+func TestTimeoutFollowsLatency(t *testing.T) {
+	c := NewTimeoutController(time.Second, 16*time.Second)
+
+	// a slow network pushes the timeout above the base
+	c.Succeeded(SelectionPhase, 3*time.Second)
+	assert.Equal(t, 6*time.Second, c.TimeOut(SelectionPhase))
+
+	// the slow phase is forgotten once it is out of the window, then the
+	// timeout shrinks gradually
+	for i := 0; i < 2*latencyWindow; i++ {
+		c.Succeeded(SelectionPhase, 500*time.Millisecond)
+	}
+	assert.Equal(t, time.Second, c.TimeOut(SelectionPhase))
+
+	state := c.State()
+	assert.Equal(t, int(timeoutPhasesNum), len(state))
+	assert.Equal(t, "selection", state[0].Phase)
+	assert.Equal(t, 500*time.Millisecond, state[0].Slowest)
+	assert.Equal(t, uint(0), state[0].Failures)
+}
@@ -0,0 +1,223 @@
+package consensus
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+)
+
+// TraceKind is the kind of an event recorded in a round trace
+type TraceKind string
+
+const (
+	// TraceRoundUpdate is recorded when the Coordinator starts a new round
+	TraceRoundUpdate TraceKind = "roundupdate"
+	// TraceScoreGenerated is recorded when the node generates a score
+	TraceScoreGenerated TraceKind = "scoregenerated"
+	// TraceBestScore is recorded when the selection ends. The hash is empty
+	// if no score was received
+	TraceBestScore TraceKind = "bestscore"
+	// TraceStepStart is recorded when a reduction step starts
+	TraceStepStart TraceKind = "stepstart"
+	// TraceStepVotesQuorum is recorded when a reduction step reaches quorum
+	TraceStepVotesQuorum TraceKind = "stepvotesquorum"
+	// TraceStepTimeout is recorded when a reduction step ends without
+	// reaching quorum
+	TraceStepTimeout TraceKind = "steptimeout"
+	// TraceStepEnd is recorded when a reduction step ends
+	TraceStepEnd TraceKind = "stepend"
+	// TraceAgreementQuorum is recorded when the agreement reaches quorum
+	TraceAgreementQuorum TraceKind = "agreementquorum"
+	// TraceCertificate is recorded when the certificate is sent to the Chain
+	TraceCertificate TraceKind = "certificate"
+)
+
+// traceCapacity is the amount of rounds kept by a Tracer
+const traceCapacity = 100
+
+type (
+	// TraceEvent is an event of the consensus loop
+	TraceEvent struct {
+		Kind TraceKind `json:"kind"`
+		Step uint8     `json:"step"`
+		Time time.Time `json:"time"`
+		// Hash is the hex encoded block hash related to the event, if any
+		Hash string `json:"hash,omitempty"`
+	}
+
+	// RoundTrace holds the events of a round, in the order they happened
+	RoundTrace struct {
+		Round  uint64       `json:"round"`
+		Events []TraceEvent `json:"events"`
+	}
+
+	// Tracer records the events of the consensus loop of the last rounds. It
+	// is safe for concurrent use.
+	Tracer struct {
+		lock   sync.RWMutex
+		rounds []RoundTrace
+		// next is the position in rounds where the trace of the next round
+		// is stored, once the Tracer is full
+		next int
+	}
+)
+
+// NewTracer creates a Tracer keeping the traces of the last rounds
+func NewTracer() *Tracer {
+	return &Tracer{rounds: make([]RoundTrace, 0, traceCapacity)}
+}
+
+// Record an event of a round. Events of rounds older than the ones kept by
+// the Tracer are discarded
+func (t *Tracer) Record(round uint64, step uint8, kind TraceKind, hash []byte) {
+	e := TraceEvent{Kind: kind, Step: step, Time: time.Now()}
+	if len(hash) > 0 {
+		e.Hash = hex.EncodeToString(hash)
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for i := range t.rounds {
+		if t.rounds[i].Round == round {
+			t.rounds[i].Events = append(t.rounds[i].Events, e)
+			return
+		}
+	}
+
+	rt := RoundTrace{Round: round, Events: []TraceEvent{e}}
+	if len(t.rounds) < traceCapacity {
+		t.rounds = append(t.rounds, rt)
+		return
+	}
+
+	// the oldest round gets replaced, unless the event is even older
+	if round < t.rounds[t.next].Round {
+		return
+	}
+
+	t.rounds[t.next] = rt
+	t.next = (t.next + 1) % traceCapacity
+}
+
+// Last returns the traces of the last n rounds, the most recent first
+func (t *Tracer) Last(n int) []RoundTrace {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if n <= 0 || n > len(t.rounds) {
+		n = len(t.rounds)
+	}
+
+	traces := make([]RoundTrace, 0, n)
+	for i := 0; i < len(t.rounds) && len(traces) < n; i++ {
+		// walking backward from the most recent trace
+		idx := (t.next - 1 - i + 2*len(t.rounds)) % len(t.rounds)
+		rt := t.rounds[idx]
+		events := make([]TraceEvent, len(rt.Events))
+		copy(events, rt.Events)
+		traces = append(traces, RoundTrace{Round: rt.Round, Events: events})
+	}
+
+	return traces
+}
+
+// Serve the requests for the round traces coming from the RPCBus. The
+// request parameter is the amount of rounds to return. It is meant to be run
+// in its own goroutine
+func (t *Tracer) Serve(reqChan <-chan rpcbus.Request) {
+	for r := range reqChan {
+		n, ok := r.Params.(int)
+		if !ok {
+			r.RespChan <- rpcbus.Response{Resp: nil, Err: fmt.Errorf("expected the amount of rounds, got %T", r.Params)}
+			continue
+		}
+
+		r.RespChan <- rpcbus.Response{Resp: t.Last(n), Err: nil}
+	}
+}
+
+// WriteJSON exports the round traces as JSON
+func WriteJSON(w io.Writer, traces []RoundTrace) error {
+	return json.NewEncoder(w).Encode(traces)
+}
+
+// traceEvent is an event of the Trace Event Format, as understood by
+// chrome://tracing and Perfetto
+type traceEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Duration  int64             `json:"dur,omitempty"`
+	PID       int               `json:"pid"`
+	TID       int               `json:"tid"`
+	Scope     string            `json:"s,omitempty"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+// WriteTraceEvents exports the round traces in the Trace Event Format, which
+// can be loaded in chrome://tracing or Perfetto. Rounds and reduction steps
+// are exported as spans, all other events as instants
+func WriteTraceEvents(w io.Writer, traces []RoundTrace) error {
+	events := make([]traceEvent, 0)
+	for _, rt := range traces {
+		if len(rt.Events) == 0 {
+			continue
+		}
+
+		first, last := rt.Events[0].Time, rt.Events[len(rt.Events)-1].Time
+		events = append(events, traceEvent{
+			Name:      fmt.Sprintf("round %d", rt.Round),
+			Category:  "round",
+			Phase:     "X",
+			Timestamp: first.UnixNano() / int64(time.Microsecond),
+			Duration:  int64(last.Sub(first) / time.Microsecond),
+		})
+
+		var stepStart *TraceEvent
+		for i, e := range rt.Events {
+			ts := e.Time.UnixNano() / int64(time.Microsecond)
+			switch e.Kind {
+			case TraceStepStart:
+				stepStart = &rt.Events[i]
+			case TraceStepEnd:
+				if stepStart == nil {
+					continue
+				}
+
+				events = append(events, traceEvent{
+					Name:      fmt.Sprintf("step %d", stepStart.Step),
+					Category:  "step",
+					Phase:     "X",
+					Timestamp: stepStart.Time.UnixNano() / int64(time.Microsecond),
+					Duration:  int64(e.Time.Sub(stepStart.Time) / time.Microsecond),
+					Args:      map[string]string{"round": fmt.Sprint(rt.Round)},
+				})
+				stepStart = nil
+			default:
+				events = append(events, traceEvent{
+					Name:      string(e.Kind),
+					Category:  "event",
+					Phase:     "i",
+					Timestamp: ts,
+					Scope:     "t",
+					Args: map[string]string{
+						"round": fmt.Sprint(rt.Round),
+						"step":  fmt.Sprint(e.Step),
+						"hash":  e.Hash,
+					},
+				})
+			}
+		}
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}{events})
+}
@@ -0,0 +1,59 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracerKeepsLastRounds(t *testing.T) {
+	tr := NewTracer()
+	for round := uint64(1); round <= traceCapacity+5; round++ {
+		tr.Record(round, 0, TraceRoundUpdate, nil)
+		tr.Record(round, 1, TraceStepStart, []byte{1, 2})
+	}
+
+	// events of rounds which were dropped are discarded
+	tr.Record(1, 3, TraceCertificate, nil)
+
+	traces := tr.Last(0)
+	assert.Equal(t, traceCapacity, len(traces))
+	assert.Equal(t, uint64(traceCapacity+5), traces[0].Round)
+	assert.Equal(t, uint64(6), traces[len(traces)-1].Round)
+
+	last := tr.Last(2)
+	assert.Equal(t, 2, len(last))
+	assert.Equal(t, uint64(traceCapacity+4), last[1].Round)
+	assert.Equal(t, []TraceKind{TraceRoundUpdate, TraceStepStart}, []TraceKind{last[1].Events[0].Kind, last[1].Events[1].Kind})
+	assert.Equal(t, "0102", last[1].Events[1].Hash)
+}
+
+func TestWriteTraceEvents(t *testing.T) {
+	tr := NewTracer()
+	tr.Record(1, 0, TraceRoundUpdate, nil)
+	tr.Record(1, 2, TraceStepStart, []byte{1})
+	tr.Record(1, 2, TraceStepVotesQuorum, []byte{1})
+	tr.Record(1, 2, TraceStepEnd, []byte{1})
+	tr.Record(1, 4, TraceCertificate, []byte{1})
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, WriteTraceEvents(buf, tr.Last(1)))
+
+	var doc struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	// a span for the round and one for the step, plus three instants
+	assert.Equal(t, 5, len(doc.TraceEvents))
+	assert.Equal(t, "round 1", doc.TraceEvents[0].Name)
+	assert.Equal(t, "X", doc.TraceEvents[0].Phase)
+
+	phases := make(map[string]int)
+	for _, e := range doc.TraceEvents {
+		phases[e.Phase]++
+	}
+	assert.Equal(t, map[string]int{"X": 2, "i": 3}, phases)
+}
@@ -0,0 +1,106 @@
+package user
+
+type (
+	// CommitteeMember is a member of a VotingCommittee, along with the
+	// amount of votes it was extracted for
+	CommitteeMember struct {
+		PubKeyBLS []byte `json:"pubkey"`
+		Votes     int    `json:"votes"`
+	}
+
+	// CommitteeInfo describes the VotingCommittee of a round and step
+	CommitteeInfo struct {
+		Round uint64 `json:"round"`
+		Step  uint8  `json:"step"`
+		// Size is the total amount of votes in the committee
+		Size    int               `json:"size"`
+		Members []CommitteeMember `json:"members"`
+		// TotalWeight is the stake of all the provisioners, active at the
+		// round
+		TotalWeight uint64 `json:"totalweight"`
+	}
+
+	// Selection is a round and step for which a provisioner is extracted in
+	// the VotingCommittee
+	Selection struct {
+		Round uint64 `json:"round"`
+		Step  uint8  `json:"step"`
+		Votes int    `json:"votes"`
+	}
+)
+
+// VotesOf returns the votes of a provisioner in the committee, or 0 if the
+// provisioner is not a member
+func (c CommitteeInfo) VotesOf(pubKeyBLS []byte) int {
+	for _, m := range c.Members {
+		if string(m.PubKeyBLS) == string(pubKeyBLS) {
+			return m.Votes
+		}
+	}
+
+	return 0
+}
+
+// CommitteeSize returns the size of the VotingCommittee of a round, which is
+// capped by the amount of provisioners active at the round
+func (p Provisioners) CommitteeSize(round uint64, maxSize int) int {
+	size := p.SubsetSizeAt(round)
+	if size > maxSize {
+		return maxSize
+	}
+
+	return size
+}
+
+// ActiveWeight returns the total amount of stake active at the given round
+func (p Provisioners) ActiveWeight(round uint64) (weight uint64) {
+	for _, member := range p.Members {
+		for _, stake := range member.Stakes {
+			if stake.StartHeight <= round && round <= stake.EndHeight {
+				weight += stake.Amount
+			}
+		}
+	}
+
+	return weight
+}
+
+// InspectCommittee runs the sortition for the given round and step, and
+// describes the resulting VotingCommittee
+func (p Provisioners) InspectCommittee(round uint64, step uint8, maxSize int) CommitteeInfo {
+	committee := p.CreateVotingCommittee(round, step, p.CommitteeSize(round, maxSize))
+	members := make([]CommitteeMember, 0, committee.Set.Len())
+	for _, pk := range committee.Set {
+		members = append(members, CommitteeMember{
+			PubKeyBLS: pk.Bytes(),
+			Votes:     committee.OccurrencesOf(pk.Bytes()),
+		})
+	}
+
+	return CommitteeInfo{
+		Round:       round,
+		Step:        step,
+		Size:        committee.Size(),
+		Members:     members,
+		TotalWeight: p.ActiveWeight(round),
+	}
+}
+
+// NextSelection returns the first round and step, starting from the given
+// round and within the given amount of rounds, in which the provisioner is
+// extracted in the VotingCommittee. Only the steps between firstStep and
+// lastStep of each round are looked at. The second return value is false if
+// the provisioner is never extracted
+func (p Provisioners) NextSelection(pubKeyBLS []byte, round, rounds uint64, firstStep, lastStep uint8, maxSize int) (Selection, bool) {
+	for r := round; r < round+rounds; r++ {
+		size := p.CommitteeSize(r, maxSize)
+		for step := int(firstStep); step <= int(lastStep); step++ {
+			committee := p.CreateVotingCommittee(r, uint8(step), size)
+			if votes := committee.OccurrencesOf(pubKeyBLS); votes > 0 {
+				return Selection{Round: r, Step: uint8(step), Votes: votes}, true
+			}
+		}
+	}
+
+	return Selection{}, false
+}
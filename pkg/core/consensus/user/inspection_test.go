@@ -0,0 +1,52 @@
+package user_test
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspectCommittee(t *testing.T) {
+	p, ks := consensus.MockProvisioners(10)
+	info := p.InspectCommittee(1, 2, 64)
+
+	// the committee size is capped by the amount of active provisioners
+	assert.Equal(t, 10, info.Size)
+	assert.Equal(t, uint64(5000), info.TotalWeight)
+
+	votes := 0
+	for _, m := range info.Members {
+		votes += m.Votes
+	}
+	assert.Equal(t, info.Size, votes)
+	assert.True(t, info.VotesOf(ks[0].BLSPubKeyBytes) > 0)
+
+	outsider, _ := key.NewRandConsensusKeys()
+	assert.Equal(t, 0, info.VotesOf(outsider.BLSPubKeyBytes))
+}
+
+func TestNextSelection(t *testing.T) {
+	p, ks := consensus.MockProvisioners(100)
+	pk := ks[0].BLSPubKeyBytes
+
+	s, found := p.NextSelection(pk, 1, 50, 2, 3, 64)
+	assert.True(t, found)
+	assert.True(t, s.Votes > 0)
+	assert.True(t, p.CreateVotingCommittee(s.Round, s.Step, 64).IsMember(pk))
+
+	// no earlier step of the rounds looked at selects the provisioner
+	for r := uint64(1); r <= s.Round; r++ {
+		for step := uint8(2); step <= 3; step++ {
+			if r == s.Round && step == s.Step {
+				break
+			}
+			assert.False(t, p.CreateVotingCommittee(r, step, 64).IsMember(pk))
+		}
+	}
+
+	// stakes expire at round 10000
+	_, found = p.NextSelection(pk, 20000, 10, 2, 3, 64)
+	assert.False(t, found)
+}
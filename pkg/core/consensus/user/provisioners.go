@@ -22,6 +22,9 @@ type (
 	Provisioners struct {
 		Set     sortedset.Set
 		Members map[string]*Member
+		// cache memoizes the voting committees. It is nil for Provisioners
+		// not created through NewProvisioners or UnmarshalProvisioners
+		cache *sortitionCache
 	}
 
 	Stake struct {
@@ -33,14 +36,17 @@ type (
 
 func (m *Member) AddStake(stake Stake) {
 	m.Stakes = append(m.Stakes, stake)
+	stakesChanged()
 }
 
 func (m *Member) RemoveStake(idx int) {
 	m.Stakes[idx] = m.Stakes[len(m.Stakes)-1]
 	m.Stakes = m.Stakes[:len(m.Stakes)-1]
+	stakesChanged()
 }
 
 func (m *Member) SubtractFromStake(amount uint64) uint64 {
+	stakesChanged()
 	for i := 0; i < len(m.Stakes); i++ {
 		if m.Stakes[i].Amount > 0 {
 			if m.Stakes[i].Amount < amount {
@@ -61,6 +67,16 @@ func NewProvisioners() *Provisioners {
 	return &Provisioners{
 		Set:     sortedset.New(),
 		Members: make(map[string]*Member),
+		cache:   newSortitionCache(),
+	}
+}
+
+// Invalidate drops the voting committees memoized by the Provisioners. It
+// should be called whenever the Stakes of a Member, or the members
+// themselves, are changed other than through the Member methods.
+func (p Provisioners) Invalidate() {
+	if p.cache != nil {
+		p.cache.invalidate()
 	}
 }
 
@@ -207,6 +223,7 @@ func UnmarshalProvisioners(r *bytes.Buffer) (Provisioners, error) {
 	return Provisioners{
 		Set:     set,
 		Members: memberMap,
+		cache:   newSortitionCache(),
 	}, nil
 }
 
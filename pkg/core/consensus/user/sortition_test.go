@@ -2,6 +2,8 @@ package user_test
 
 import (
 	"bytes"
+	"encoding/binary"
+	"fmt"
 	"math/big"
 	"sort"
 	"testing"
@@ -9,7 +11,9 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/sortedset"
+	"github.com/dusk-network/dusk-crypto/hash"
 	"github.com/dusk-network/dusk-wallet/v2/key"
+	"github.com/dusk-network/dusk-wallet/v2/wallet"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -94,3 +98,149 @@ func TestRemoveStake(t *testing.T) {
 	// Now, extract a committee for round 1 step 1
 	assert.NotPanics(t, func() { p.CreateVotingCommittee(1, 1, 10) })
 }
+
+// Test that the sortition extracts the same committees as a plain walk
+// through the provisioner set would, with stakes of several DUSK, some of
+// which are not active.
+func TestCreateVotingCommitteeMatchesWalk(t *testing.T) {
+	p, _ := mockWeightedProvisioners(30)
+	for round := uint64(1); round < 6; round++ {
+		for step := uint8(1); step < 4; step++ {
+			expected := walkSortition(*p, round, step, 64)
+			committee := p.CreateVotingCommittee(round, step, 64)
+			assert.True(t, expected.Equal(&committee), "round %d step %d", round, step)
+		}
+	}
+}
+
+// Test that memoized committees are dropped when the stakes change.
+func TestCreateVotingCommitteeAfterStakeChange(t *testing.T) {
+	p, _ := mockWeightedProvisioners(10)
+	first := p.CreateVotingCommittee(1, 1, 10)
+	again := p.CreateVotingCommittee(1, 1, 10)
+	assert.True(t, first.Equal(&again))
+
+	// Giving most of the weight to a single provisioner
+	m, err := p.MemberAt(3)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	m.AddStake(user.Stake{Amount: 1000 * wallet.DUSK, StartHeight: 0, EndHeight: 10000})
+
+	expected := walkSortition(*p, 1, 1, 10)
+	committee := p.CreateVotingCommittee(1, 1, 10)
+	assert.True(t, expected.Equal(&committee))
+	assert.False(t, first.Equal(&committee))
+}
+
+// Test that memoized committees are dropped on request, when the stakes are
+// changed directly.
+func TestCreateVotingCommitteeAfterInvalidate(t *testing.T) {
+	p, _ := mockWeightedProvisioners(10)
+	first := p.CreateVotingCommittee(1, 1, 10)
+
+	m, err := p.MemberAt(3)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	m.Stakes[0].Amount = 1000 * wallet.DUSK
+	p.Invalidate()
+
+	expected := walkSortition(*p, 1, 1, 10)
+	committee := p.CreateVotingCommittee(1, 1, 10)
+	assert.True(t, expected.Equal(&committee))
+	assert.False(t, first.Equal(&committee))
+}
+
+// Test that modifying a returned committee does not affect the next ones.
+func TestCreateVotingCommitteeIsolation(t *testing.T) {
+	p, _ := mockWeightedProvisioners(10)
+	committee := p.CreateVotingCommittee(1, 1, 10)
+	size := committee.Size()
+	committee.Insert(committee.Set[0].Bytes())
+
+	again := p.CreateVotingCommittee(1, 1, 10)
+	assert.Equal(t, size, again.Size())
+}
+
+func BenchmarkCreateVotingCommittee(b *testing.B) {
+	for _, amount := range []int{100, 1000, 5000} {
+		p, _ := mockWeightedProvisioners(amount)
+
+		b.Run(fmt.Sprintf("%d provisioners", amount), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				// a new round every time, so that nothing is memoized
+				p.CreateVotingCommittee(uint64(i+1), 1, 64)
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d provisioners memoized", amount), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.CreateVotingCommittee(1, uint8(i%3)+1, 64)
+			}
+		})
+	}
+}
+
+// mockWeightedProvisioners creates provisioners with stakes of a few DUSK,
+// along with stakes that are not active in the first rounds
+func mockWeightedProvisioners(amount int) (*user.Provisioners, []key.ConsensusKeys) {
+	p, ks := consensus.MockProvisioners(amount)
+	for i, k := range ks {
+		m := p.GetMember(k.BLSPubKeyBytes)
+		m.Stakes[0].Amount = uint64(i%7+1) * wallet.DUSK / 2
+		if i%3 == 0 {
+			m.AddStake(user.Stake{Amount: 5 * wallet.DUSK, StartHeight: 3, EndHeight: 10000})
+		}
+		if i%4 == 0 {
+			m.AddStake(user.Stake{Amount: 2 * wallet.DUSK, StartHeight: 0, EndHeight: 10000})
+		}
+	}
+
+	return p, ks
+}
+
+// walkSortition extracts a committee by walking through the provisioner set
+// for every extraction, on a copy of the provisioners
+func walkSortition(p user.Provisioners, round uint64, step uint8, size int) user.VotingCommittee {
+	members := make(map[string]*user.Member, len(p.Members))
+	var W uint64
+	for k, m := range p.Members {
+		c := &user.Member{PublicKeyBLS: m.PublicKeyBLS, Stakes: make([]user.Stake, len(m.Stakes))}
+		copy(c.Stakes, m.Stakes)
+		for i := 0; i < len(c.Stakes); {
+			if c.Stakes[i].StartHeight > round || c.Stakes[i].EndHeight < round {
+				c.RemoveStake(i)
+				continue
+			}
+			W += c.Stakes[i].Amount
+			i++
+		}
+		members[k] = c
+	}
+	p.Members = members
+
+	committee := user.VotingCommittee{Cluster: sortedset.NewCluster()}
+	for i := 0; committee.Size() < size && W > 0; i++ {
+		msg := make([]byte, 12)
+		binary.LittleEndian.PutUint64(msg[:8], round)
+		binary.LittleEndian.PutUint32(msg[8:12], uint32(i))
+		h, _ := hash.Sha3256(append(msg, step))
+		score := new(big.Int).Mod(new(big.Int).SetBytes(h), new(big.Int).SetUint64(W)).Uint64()
+
+		var m *user.Member
+		for j := 0; j < len(p.Set); j++ {
+			m = p.GetMember(p.Set[j].Bytes())
+			stake, _ := p.GetStake(m.PublicKeyBLS)
+			if stake >= score {
+				break
+			}
+			score -= stake
+		}
+
+		committee.Insert(m.PublicKeyBLS)
+		W -= m.SubtractFromStake(1 * wallet.DUSK)
+	}
+
+	return committee
+}
@@ -0,0 +1,247 @@
+package user
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dusk-network/dusk-wallet/v2/wallet"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// cachedRounds is the amount of rounds for which the sortition data is
+	// kept by a sortitionCache
+	cachedRounds = 4
+)
+
+// stakesVersion is bumped by every change to the stakes made through the
+// Member mutators, so that the sortition caches can tell they are stale
+var stakesVersion uint64
+
+type (
+	// roundWeights holds the stakes of the provisioners active at a round,
+	// in the order of the provisioner set
+	roundWeights struct {
+		keys [][]byte
+		// stakes holds the active stakes of each provisioner, in the order
+		// they are subtracted from during sortition
+		stakes [][]uint64
+		// tree is a Fenwick tree of the total active stake of each
+		// provisioner, allowing for cumulative weight lookups in
+		// logarithmic time
+		tree  []uint64
+		total uint64
+
+		committees map[committeeKey]VotingCommittee
+	}
+
+	committeeKey struct {
+		step uint8
+		size int
+	}
+
+	// sortitionCache memoizes the sortition of the last rounds. It is shared
+	// by the copies of a Provisioners, and it is safe for concurrent use.
+	sortitionCache struct {
+		lock sync.Mutex
+		// version is the stakesVersion the cached data was computed at
+		version uint64
+		rounds  map[uint64]*roundWeights
+	}
+)
+
+func newSortitionCache() *sortitionCache {
+	return &sortitionCache{rounds: make(map[uint64]*roundWeights)}
+}
+
+// committee returns the memoized VotingCommittee of a round and step, running
+// the sortition if needed. The cache is dropped altogether as soon as the
+// stakes change
+func (c *sortitionCache) committee(p Provisioners, round uint64, step uint8, size int) VotingCommittee {
+	version := atomic.LoadUint64(&stakesVersion)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if version != c.version {
+		c.version = version
+		c.rounds = make(map[uint64]*roundWeights)
+	}
+
+	w, ok := c.rounds[round]
+	if !ok {
+		w = p.weightsAt(round)
+		c.evict()
+		c.rounds[round] = w
+	}
+
+	key := committeeKey{step, size}
+	committee, ok := w.committees[key]
+	if !ok {
+		committee = w.extract(round, step, size)
+		w.committees[key] = committee
+	}
+
+	return VotingCommittee{committee.Copy()}
+}
+
+// evict the oldest round, if the cache is full
+func (c *sortitionCache) evict() {
+	if len(c.rounds) < cachedRounds {
+		return
+	}
+
+	oldest := uint64(0)
+	first := true
+	for round := range c.rounds {
+		if first || round < oldest {
+			oldest = round
+			first = false
+		}
+	}
+
+	delete(c.rounds, oldest)
+}
+
+// invalidate drops the cached data
+func (c *sortitionCache) invalidate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rounds = make(map[uint64]*roundWeights)
+}
+
+// stakesChanged invalidates the data of all the sortition caches
+func stakesChanged() {
+	atomic.AddUint64(&stakesVersion, 1)
+}
+
+// weightsAt computes the active stakes of the provisioners at a round. Stakes
+// which are not active are removed in the same order as they always were, as
+// the order of the remaining ones determines how they are subtracted from
+func (p Provisioners) weightsAt(round uint64) *roundWeights {
+	w := &roundWeights{
+		keys:       make([][]byte, len(p.Set)),
+		stakes:     make([][]uint64, len(p.Set)),
+		tree:       make([]uint64, len(p.Set)+1),
+		committees: make(map[committeeKey]VotingCommittee),
+	}
+
+	for i, k := range p.Set {
+		w.keys[i] = k.Bytes()
+		m, found := p.Members[string(w.keys[i])]
+		if !found {
+			continue
+		}
+
+		stakes := make([]Stake, len(m.Stakes))
+		copy(stakes, m.Stakes)
+		for j := 0; j < len(stakes); {
+			if stakes[j].StartHeight > round || stakes[j].EndHeight < round {
+				stakes[j] = stakes[len(stakes)-1]
+				stakes = stakes[:len(stakes)-1]
+				continue
+			}
+			j++
+		}
+
+		amounts := make([]uint64, len(stakes))
+		for j, stake := range stakes {
+			amounts[j] = stake.Amount
+			w.tree[i+1] += stake.Amount
+		}
+
+		w.stakes[i] = amounts
+		w.total += w.tree[i+1]
+	}
+
+	// turning the weights into a Fenwick tree in linear time
+	for i := 1; i < len(w.tree); i++ {
+		if parent := i + (i & -i); parent < len(w.tree) {
+			w.tree[parent] += w.tree[i]
+		}
+	}
+
+	return w
+}
+
+// extract the members of a VotingCommittee. Every extraction subtracts up to
+// one DUSK from the extracted provisioner, on a copy of the weights
+func (w *roundWeights) extract(round uint64, step uint8, size int) VotingCommittee {
+	votingCommittee := newCommittee()
+	tree := make([]uint64, len(w.tree))
+	copy(tree, w.tree)
+	// the stakes of the extracted provisioners are copied on write
+	subtracted := make(map[int][]uint64)
+
+	W := w.total
+	for i, extracted := 0, 0; extracted < size && W > 0; i++ {
+		hash, err := createSortitionHash(round, step, i)
+		if err != nil {
+			log.Panic(err)
+		}
+
+		score := generateSortitionScore(hash, new(big.Int).SetUint64(W))
+		idx := search(tree, score)
+		votingCommittee.Insert(w.keys[idx])
+		extracted++
+
+		stakes, ok := subtracted[idx]
+		if !ok {
+			stakes = make([]uint64, len(w.stakes[idx]))
+			copy(stakes, w.stakes[idx])
+			subtracted[idx] = stakes
+		}
+
+		amount := subtractFromStakes(stakes, 1*wallet.DUSK)
+		for j := idx + 1; j < len(tree); j += j & -j {
+			tree[j] -= amount
+		}
+		W -= amount
+	}
+
+	return *votingCommittee
+}
+
+// search the Fenwick tree for the first provisioner whose cumulative weight
+// is greater than or equal to the score
+func search(tree []uint64, score uint64) int {
+	n := len(tree) - 1
+	step := 1
+	for step*2 <= n {
+		step *= 2
+	}
+
+	pos := 0
+	for ; step > 0; step /= 2 {
+		if pos+step <= n && tree[pos+step] < score {
+			pos += step
+			score -= tree[pos]
+		}
+	}
+
+	if pos >= n {
+		// handling the eventuality of an out of bound score
+		return 0
+	}
+
+	return pos
+}
+
+// subtractFromStakes subtracts up to amount from the first non-empty stake,
+// like Member.SubtractFromStake, and returns the subtracted amount
+func subtractFromStakes(stakes []uint64, amount uint64) uint64 {
+	for i := range stakes {
+		if stakes[i] > 0 {
+			if stakes[i] < amount {
+				subtracted := stakes[i]
+				stakes[i] = 0
+				return subtracted
+			}
+
+			stakes[i] -= amount
+			return amount
+		}
+	}
+
+	return 0
+}
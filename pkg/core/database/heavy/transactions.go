@@ -48,6 +48,7 @@ var (
 	StatePrefix     = []byte{0x06}
 	OutputKeyPrefix = []byte{0x07}
 	BidValuesPrefix = []byte{0x08}
+	EvidencePrefix  = []byte{0x09}
 )
 
 type transaction struct {
@@ -652,6 +653,41 @@ func (t transaction) FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (
 
 }
 
+// StoreEvidence stores the evidence of a provisioner equivocation
+func (t transaction) StoreEvidence(round uint64, step uint8, pubKeyBLS []byte, evidence []byte) error {
+
+	// Schema Key = EvidencePrefix + round + step + pubKeyBLS
+	//
+	// Value = evidence
+	key := evidenceKey(round)
+	key = append(key, step)
+	key = append(key, pubKeyBLS...)
+	t.put(key, evidence)
+	return nil
+}
+
+// FetchEvidence retrieves all evidences stored for the given round
+func (t transaction) FetchEvidence(round uint64) ([][]byte, error) {
+	iterator := t.snapshot.NewIterator(util.BytesPrefix(evidenceKey(round)), nil)
+	defer iterator.Release()
+
+	evidences := make([][]byte, 0)
+	for iterator.Next() {
+		value := make([]byte, len(iterator.Value()))
+		copy(value, iterator.Value())
+		evidences = append(evidences, value)
+	}
+
+	return evidences, iterator.Error()
+}
+
+func evidenceKey(round uint64) []byte {
+	key := make([]byte, len(EvidencePrefix)+8)
+	copy(key, EvidencePrefix)
+	byteOrder.PutUint64(key[len(EvidencePrefix):], round)
+	return key
+}
+
 // ClearDatabase will wipe all of the data currently in the database.
 func (t transaction) ClearDatabase() error {
 	iter := t.snapshot.NewIterator(nil, nil)
@@ -109,6 +109,15 @@ type Transaction interface {
 	// sinceUnixTime starting the search from height (tip - offset)
 	FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (uint64, error)
 
+	// StoreEvidence stores the marshaled evidence of a provisioner
+	// equivocating at the given round and step. Only one evidence per
+	// provisioner, round and step is kept.
+	StoreEvidence(round uint64, step uint8, pubKeyBLS []byte, evidence []byte) error
+
+	// FetchEvidence retrieves all the marshaled evidences stored for the
+	// given round.
+	FetchEvidence(round uint64) ([][]byte, error)
+
 	// ClearDatabase will remove all information from the database.
 	ClearDatabase() error
 
@@ -23,6 +23,7 @@ const (
 	stateInd
 	bidValuesInd
 	outputKeyInd
+	evidenceInd
 	maxInd
 )
 
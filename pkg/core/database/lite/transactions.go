@@ -11,6 +11,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
 	"github.com/dusk-network/dusk-wallet/v2/block"
 	"github.com/dusk-network/dusk-wallet/v2/transactions"
 )
@@ -346,6 +347,34 @@ func (t *transaction) FetchBidValues() ([]byte, []byte, error) {
 	return values[0:32], values[32:], nil
 }
 
+// StoreEvidence stores the evidence of a provisioner equivocation. As the BLS
+// public key does not fit into a table key, its hash is used instead
+func (t *transaction) StoreEvidence(round uint64, step uint8, pubKeyBLS []byte, evidence []byte) error {
+	pubKeyHash, err := crypto.Sha3256(pubKeyBLS)
+	if err != nil {
+		return err
+	}
+
+	k := make([]byte, 9, 9+len(pubKeyHash))
+	binary.LittleEndian.PutUint64(k, round)
+	k[8] = step
+	k = append(k, pubKeyHash...)
+	t.batch[evidenceInd][toKey(k)] = evidence
+	return nil
+}
+
+// FetchEvidence retrieves all evidences stored for the given round
+func (t *transaction) FetchEvidence(round uint64) ([][]byte, error) {
+	evidences := make([][]byte, 0)
+	for k, v := range t.db.storage[evidenceInd] {
+		if binary.LittleEndian.Uint64(k[:8]) == round {
+			evidences = append(evidences, v)
+		}
+	}
+
+	return evidences, nil
+}
+
 // FetchBlockHeightSince uses binary search to find a block height
 // NB: Duplicates FetchBlockHeightSince heavy driver
 func (t transaction) FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (uint64, error) {
@@ -878,6 +878,48 @@ func TestStoreFetchBidValues(test *testing.T) {
 	}))
 }
 
+func TestStoreFetchEvidence(test *testing.T) {
+	test.Parallel()
+
+	pk1, _ := crypto.RandEntropy(129)
+	pk2, _ := crypto.RandEntropy(129)
+	e1, _ := crypto.RandEntropy(200)
+	e2, _ := crypto.RandEntropy(200)
+	e3, _ := crypto.RandEntropy(200)
+
+	assert.NoError(test, db.Update(func(t database.Transaction) error {
+		if err := t.StoreEvidence(5000, 1, pk1, e1); err != nil {
+			return err
+		}
+
+		if err := t.StoreEvidence(5000, 2, pk2, e2); err != nil {
+			return err
+		}
+
+		return t.StoreEvidence(5001, 1, pk1, e3)
+	}))
+
+	// Only the evidences of the requested round should be returned
+	assert.NoError(test, db.View(func(t database.Transaction) error {
+		evidences, err := t.FetchEvidence(5000)
+		if err != nil {
+			return err
+		}
+
+		assert.Equal(test, 2, len(evidences))
+		assert.Contains(test, evidences, e1)
+		assert.Contains(test, evidences, e2)
+
+		evidences, err = t.FetchEvidence(5002)
+		if err != nil {
+			return err
+		}
+
+		assert.Empty(test, evidences)
+		return nil
+	}))
+}
+
 // _TestPersistence tries to ensure if driver provides persistence storage.
 // The procedure is simply based on:
 // 1. Close the driver
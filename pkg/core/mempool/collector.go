@@ -7,19 +7,28 @@ import (
 	"github.com/dusk-network/dusk-wallet/v2/block"
 )
 
-type intermediateBlockCollector struct {
+type blockCollector struct {
 	blkChan chan<- block.Block
 }
 
 func initIntermediateBlockCollector(sub eventbus.Subscriber) chan block.Block {
+	return initBlockCollector(sub, topics.IntermediateBlock)
+}
+
+// initDisconnectedBlockCollector collects the blocks reverted by the chain
+func initDisconnectedBlockCollector(sub eventbus.Subscriber) chan block.Block {
+	return initBlockCollector(sub, topics.DisconnectedBlock)
+}
+
+func initBlockCollector(sub eventbus.Subscriber, topic topics.Topic) chan block.Block {
 	blkChan := make(chan block.Block, 1)
-	coll := &intermediateBlockCollector{blkChan}
+	coll := &blockCollector{blkChan}
 	l := eventbus.NewCallbackListener(coll.Collect)
-	sub.Subscribe(topics.IntermediateBlock, l)
+	sub.Subscribe(topic, l)
 	return blkChan
 }
 
-func (i *intermediateBlockCollector) Collect(blockMsg message.Message) error {
+func (i *blockCollector) Collect(blockMsg message.Message) error {
 	blk := blockMsg.Payload().(block.Block)
 	i.blkChan <- blk
 	return nil
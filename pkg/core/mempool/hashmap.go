@@ -89,6 +89,40 @@ func (m *HashMap) Put(t TxDesc) error {
 	return nil
 }
 
+// Delete removes the tx with the given txID, along with its key images
+func (m *HashMap) Delete(txID []byte) bool {
+	var k txHash
+	copy(k[:], txID)
+	t, ok := m.data[k]
+	if !ok {
+		return false
+	}
+
+	delete(m.data, k)
+	m.txsSize -= uint32(t.size)
+
+	// the entries with the same Fee are next to each other
+	fee := t.tx.StandardTx().Fee.BigInt().Uint64()
+	index := sort.Search(len(m.sorted), func(i int) bool {
+		return m.sorted[i].f <= fee
+	})
+
+	for i := index; i < len(m.sorted) && m.sorted[i].f == fee; i++ {
+		if m.sorted[i].k == k {
+			m.sorted = append(m.sorted[:i], m.sorted[i+1:]...)
+			break
+		}
+	}
+
+	for _, input := range t.tx.StandardTx().Inputs {
+		var ki keyImage
+		copy(ki[:], input.KeyImage.Bytes())
+		delete(m.spentkeyImages, ki)
+	}
+
+	return true
+}
+
 // Clone the entire pool
 func (m HashMap) Clone() []transactions.Transaction {
 
@@ -19,6 +19,10 @@ type TxDesc struct {
 	// the point in time, tx was accepted by this node
 	// accepted time.Time
 	size uint
+
+	// local is true if the tx was submitted by this node (e.g by the wallet)
+	// and not received from the P2P network
+	local bool
 }
 
 // Pool represents a transaction pool of the verified txs only.
@@ -27,6 +31,9 @@ type Pool interface {
 	// Put sets the value for the given key. It overwrites any previous value
 	// for that key;
 	Put(t TxDesc) error
+	// Delete removes the tx with the given txID, along with its key images.
+	// It returns false if the tx is not in the pool
+	Delete(txID []byte) bool
 	// Get retrieves a transaction for a given txID, if it exists.
 	Get(txID []byte) transactions.Transaction
 	// Contains returns true if the given key is in the pool.
@@ -49,6 +49,7 @@ type Mempool struct {
 	getMempoolTxsBySizeChan <-chan rpcbus.Request
 	getMempoolViewChan      <-chan rpcbus.Request
 	sendTxChan              <-chan rpcbus.Request
+	getRebroadcastTxsChan   <-chan rpcbus.Request
 
 	// transactions emitted by RPC and Peer subsystems
 	// pending to be verified before adding them to verified pool
@@ -57,12 +58,18 @@ type Mempool struct {
 	// verified txs to be included in next block
 	verified Pool
 
+	// locally-submitted txs scheduled for re-advertisement
+	rebroadcaster *rebroadcaster
+
 	// the collector to listen for new intermediate blocks
 	intermediateBlockChan <-chan block.Block
 	acceptedBlockChan     <-chan block.Block
+	disconnectedBlockChan <-chan block.Block
 
 	// used by tx verification procedure
 	latestBlockTimestamp int64
+	// used by rebroadcast scheduling
+	latestBlockHeight uint64
 
 	eventBus *eventbus.EventBus
 	db       database.DB
@@ -117,8 +124,14 @@ func NewMempool(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, verifyTx fun
 		log.Errorf("rpcbus.SendMempoolTx err=%v", err)
 	}
 
+	getRebroadcastTxsChan := make(chan rpcbus.Request, 1)
+	if err := rpcBus.Register(topics.GetRebroadcastTxs, getRebroadcastTxsChan); err != nil {
+		log.WithError(err).Errorf("error registering getRebroadcastTxs")
+	}
+
 	intermediateBlockChan := initIntermediateBlockCollector(eventBus)
 	acceptedBlockChan, _ := consensus.InitAcceptedBlockUpdate(eventBus)
+	disconnectedBlockChan := initDisconnectedBlockCollector(eventBus)
 
 	m := &Mempool{
 		eventBus:                eventBus,
@@ -126,10 +139,12 @@ func NewMempool(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, verifyTx fun
 		quitChan:                make(chan struct{}),
 		intermediateBlockChan:   intermediateBlockChan,
 		acceptedBlockChan:       acceptedBlockChan,
+		disconnectedBlockChan:   disconnectedBlockChan,
 		getMempoolTxsChan:       getMempoolTxsChan,
 		getMempoolTxsBySizeChan: getMempoolTxsBySizeChan,
 		getMempoolViewChan:      getMempoolViewChan,
 		sendTxChan:              sendTxChan,
+		getRebroadcastTxsChan:   getRebroadcastTxsChan,
 	}
 
 	if verifyTx != nil {
@@ -138,6 +153,9 @@ func NewMempool(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, verifyTx fun
 
 	m.verified = m.newPool()
 
+	cfg := config.Get().Mempool
+	m.rebroadcaster = newRebroadcaster(cfg.RebroadcastBlocks, cfg.MaxRebroadcastBlocks)
+
 	log.Infof("Running with pool type %s", config.Get().Mempool.PoolType)
 
 	// topics.Tx will be published by RPC subsystem or Peer subsystem (deserialized from gossip msg)
@@ -155,6 +173,17 @@ func NewMempool(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus, verifyTx fun
 // protection-by-mutex needed
 func (m *Mempool) Run() {
 	go func() {
+
+		// reload the txs persisted on the previous shutdown
+		m.restorePersisted()
+
+		var persistChan <-chan time.Time
+		if interval := config.Get().Mempool.PersistInterval; interval > 0 && len(config.Get().Mempool.PersistFile) > 0 {
+			ticker := time.NewTicker(time.Duration(interval) * time.Second)
+			defer ticker.Stop()
+			persistChan = ticker.C
+		}
+
 		for {
 			select {
 			//rpcbus methods
@@ -166,11 +195,15 @@ func (m *Mempool) Run() {
 				handleRequest(r, m.processGetMempoolTxsBySizeRequest, "GetMempoolTxsBySize")
 			case r := <-m.getMempoolViewChan:
 				handleRequest(r, m.processGetMempoolViewRequest, "GetMempoolView")
+			case r := <-m.getRebroadcastTxsChan:
+				handleRequest(r, m.processGetRebroadcastTxsRequest, "GetRebroadcastTxs")
 			// Mempool input channels
 			case b := <-m.intermediateBlockChan:
 				m.onBlock(b)
 			case b := <-m.acceptedBlockChan:
 				m.onBlock(b)
+			case b := <-m.disconnectedBlockChan:
+				m.onDisconnectedBlock(b)
 			case tx := <-m.pending:
 				// TODO: the m.pending channel looks a bit wasteful. Consider
 				// removing it and call onPendingTx directly within
@@ -178,9 +211,15 @@ func (m *Mempool) Run() {
 				_, _ = m.onPendingTx(tx)
 			case <-time.After(20 * time.Second):
 				m.onIdle()
+			case <-persistChan:
+				m.persistVerified()
 			// Mempool terminating
 			case <-m.quitChan:
 				//m.eventBus.Unsubscribe(topics.Tx, m.txSubscriberID)
+				m.persistVerified()
+				// acknowledge the termination so that Quit returns only
+				// once the verified txs are safely dumped
+				m.quitChan <- struct{}{}
 				return
 			}
 		}
@@ -209,6 +248,32 @@ func (m *Mempool) onPendingTx(t TxDesc) ([]byte, error) {
 // into the verified pool
 func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 
+	txid, err := m.acceptTx(t)
+	if err != nil {
+		return txid, err
+	}
+
+	// notify the subsystems interested in the new verified txs
+	msg := message.New(topics.VerifiedTx, t.tx)
+	m.eventBus.Publish(topics.VerifiedTx, msg)
+
+	// locally-submitted txs are re-advertised until they get accepted. That
+	// covers a failing advertisement too
+	if t.local {
+		m.rebroadcaster.add(txid, m.latestBlockHeight)
+	}
+
+	// advertise the hash of the verified tx to the P2P network
+	if err := m.advertiseTx(txid); err != nil {
+		return txid, fmt.Errorf("advertise: %v", err)
+	}
+
+	return txid, nil
+}
+
+// acceptTx verifies a tx and stores it into the verified pool
+func (m *Mempool) acceptTx(t TxDesc) ([]byte, error) {
+
 	txid, err := t.tx.CalculateHash()
 	if err != nil {
 		return txid, fmt.Errorf("hash err: %s", err.Error())
@@ -226,9 +291,13 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 		return txid, ErrAlreadyExists
 	}
 
-	// expect it is not already spent from mempool verified txs
+	// expect it is not already spent from mempool verified txs, unless it
+	// replaces the conflicting txs by paying a higher fee
+	var replaced []TxDesc
 	if err := m.checkTXDoubleSpent(t.tx); err != nil {
-		return txid, ErrDoubleSpending
+		if replaced, err = m.checkReplaceByFee(t); err != nil {
+			return txid, err
+		}
 	}
 
 	// execute tx verification procedure
@@ -239,23 +308,102 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 	// if consumer's verification passes, mark it as verified
 	t.verified = time.Now()
 
+	// the conflicting txs are only evicted along with storing the tx
+	if len(replaced) > 0 {
+		if err := m.replace(txid, t, replaced); err != nil {
+			return txid, fmt.Errorf("replace: %v", err)
+		}
+		return txid, nil
+	}
+
 	// we've got a valid transaction pushed
 	if err := m.verified.Put(t); err != nil {
 		return txid, fmt.Errorf("store: %v", err)
 	}
 
-	// advertise the hash of the verified tx to the P2P network
-	if err := m.advertiseTx(txid); err != nil {
-		// TODO: Perform re-advertise procedure
-		return txid, fmt.Errorf("advertise: %v", err)
+	return txid, nil
+}
+
+// restorePersisted reloads the txs dumped on the previous shutdown. Each tx is
+// reverified against the current chain state so that txs accepted or
+// invalidated in the meantime are dropped. Locally-originated txs that are
+// still unconfirmed are rebroadcast.
+func (m *Mempool) restorePersisted() {
+
+	path := config.Get().Mempool.PersistFile
+	if len(path) == 0 {
+		return
 	}
 
-	return txid, nil
+	txs, err := restore(path)
+	if err != nil {
+		log.WithError(err).Errorf("Failed to restore txs from %s", path)
+		return
+	}
+
+	var restored int
+	for _, t := range txs {
+		txid, err := m.acceptTx(t)
+		if err != nil {
+			log.Debugf("Dropped persisted txid=%s err='%v'", toHex(txid), err)
+			continue
+		}
+
+		restored++
+
+		if t.local {
+			m.rebroadcaster.add(txid, m.latestBlockHeight)
+			if err := m.advertiseTx(txid); err != nil {
+				log.WithError(err).Errorf("Failed to rebroadcast txid=%s", toHex(txid))
+			}
+		}
+	}
+
+	log.Infof("Restored %d of %d persisted txs", restored, len(txs))
+}
+
+// persistVerified dumps the verified pool, if persistence is enabled
+func (m *Mempool) persistVerified() {
+
+	path := config.Get().Mempool.PersistFile
+	if len(path) == 0 {
+		return
+	}
+
+	if err := m.persist(path); err != nil {
+		log.WithError(err).Errorf("Failed to persist txs into %s", path)
+		return
+	}
+
+	log.Tracef("Persisted %d txs into %s", m.verified.Len(), path)
 }
 
 func (m *Mempool) onBlock(b block.Block) {
 	m.latestBlockTimestamp = b.Header.Timestamp
+	if b.Header.Height > m.latestBlockHeight {
+		m.latestBlockHeight = b.Header.Height
+	}
+
 	m.removeAccepted(b)
+	m.rebroadcast()
+}
+
+// rebroadcast re-advertises the locally-submitted txs which have not been
+// accepted for too long
+func (m *Mempool) rebroadcast() {
+
+	if !m.rebroadcaster.enabled() {
+		return
+	}
+
+	m.rebroadcaster.prune(m.verified)
+
+	for _, txid := range m.rebroadcaster.due(m.latestBlockHeight) {
+		log.Infof("Rebroadcasting txid=%s", toHex(txid))
+		if err := m.advertiseTx(txid); err != nil {
+			log.WithError(err).Errorf("Failed to rebroadcast txid=%s", toHex(txid))
+		}
+	}
 }
 
 // removeAccepted to clean up all txs from the mempool that have been already
@@ -266,6 +414,9 @@ func (m *Mempool) onBlock(b block.Block) {
 //
 // The passed block is supposed to be the last one accepted. That said, it must
 // contain a valid TxRoot.
+//
+// Txs spending any of the key images spent in the block are removed as well as
+// they conflict with the chain state.
 func (m *Mempool) removeAccepted(b block.Block) {
 
 	blockHash := toHex(b.Header.Hash)
@@ -278,8 +429,15 @@ func (m *Mempool) removeAccepted(b block.Block) {
 	}
 
 	payloads := make([]merkletree.Payload, len(b.Txs))
+	spent := make(map[keyImage]bool)
 	for i, tx := range b.Txs {
 		payloads[i] = tx.(merkletree.Payload)
+
+		for _, input := range tx.StandardTx().Inputs {
+			var ki keyImage
+			copy(ki[:], input.KeyImage.Bytes())
+			spent[ki] = true
+		}
 	}
 
 	tree, err := merkletree.NewTree(payloads)
@@ -290,7 +448,7 @@ func (m *Mempool) removeAccepted(b block.Block) {
 		// Check if mempool verified tx is part of merkle tree of this block
 		// if not, then keep it in the mempool for the next block
 		err = m.verified.Range(func(k txHash, t TxDesc) error {
-			if r, _ := tree.VerifyContent(t.tx); !r {
+			if r, _ := tree.VerifyContent(t.tx); !r && !conflicts(t.tx, spent) {
 				if err := s.Put(t); err != nil {
 					return err
 				}
@@ -308,6 +466,50 @@ func (m *Mempool) removeAccepted(b block.Block) {
 	log.Infof("Processing block %s completed", toHex(b.Header.Hash))
 }
 
+// onDisconnectedBlock reinjects the txs of a block reverted by the chain. Each
+// tx is reverified so that txs conflicting with the new branch are dropped.
+func (m *Mempool) onDisconnectedBlock(b block.Block) {
+
+	log.Infof("Reinjecting txs of disconnected block %s", toHex(b.Header.Hash))
+
+	var reinjected int
+	for _, tx := range b.Txs {
+		if tx.Type() == transactions.CoinbaseType {
+			continue
+		}
+
+		buf := new(bytes.Buffer)
+		if err := message.MarshalTx(buf, tx); err != nil {
+			log.WithError(err).Errorln("could not marshal disconnected tx")
+			continue
+		}
+
+		txid, err := m.acceptTx(TxDesc{tx: tx, received: time.Now(), size: uint(buf.Len())})
+		if err != nil {
+			log.Debugf("Dropped disconnected txid=%s err='%v'", toHex(txid), err)
+			continue
+		}
+
+		reinjected++
+	}
+
+	log.Infof("Reinjected %d txs of disconnected block %s", reinjected, toHex(b.Header.Hash))
+}
+
+// conflicts returns true if any of the tx inputs spends a key image from the
+// spent set
+func conflicts(tx transactions.Transaction, spent map[keyImage]bool) bool {
+	for _, input := range tx.StandardTx().Inputs {
+		var ki keyImage
+		copy(ki[:], input.KeyImage.Bytes())
+		if spent[ki] {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (m *Mempool) onIdle() {
 
 	// stats to log
@@ -352,7 +554,9 @@ func (m *Mempool) newPool() Pool {
 	case "hashmap":
 		p = &HashMap{Capacity: preallocTxs}
 	case "syncpool":
-		log.Panic("syncpool not supported")
+		p = NewSyncPool(preallocTxs)
+	case "skiplist":
+		p = NewSkipList(preallocTxs)
 	default:
 		p = &HashMap{Capacity: preallocTxs}
 	}
@@ -442,6 +646,12 @@ func (m Mempool) processGetMempoolViewRequest(r rpcbus.Request) (interface{}, er
 	return resp, nil
 }
 
+// processGetRebroadcastTxsRequest returns the locally-submitted txs which are
+// pending rebroadcast, sorted by the height of the next attempt
+func (m Mempool) processGetRebroadcastTxsRequest(r rpcbus.Request) (interface{}, error) {
+	return m.rebroadcaster.pending(), nil
+}
+
 // processGetMempoolTxsBySizeRequest returns a subset of verified mempool txs which
 // 1. contains only highest fee txs
 // 2. has total txs size not bigger than maxTxsSize (request param)
@@ -487,7 +697,8 @@ func (m Mempool) processSendMempoolTxRequest(r rpcbus.Request) (interface{}, err
 		return nil, err
 	}
 
-	txDesc := TxDesc{tx: tx, received: time.Now(), size: uint(buf.Len())}
+	// txs submitted over rpcbus are originated by this node
+	txDesc := TxDesc{tx: tx, received: time.Now(), size: uint(buf.Len()), local: true}
 
 	// Process request
 	return m.onPendingTx(txDesc)
@@ -507,9 +718,11 @@ func (m *Mempool) checkTXDoubleSpent(tx transactions.Transaction) error {
 	return nil
 }
 
-// Quit makes mempool main loop to terminate
+// Quit makes mempool main loop to terminate. It blocks until the verified txs
+// are persisted
 func (m *Mempool) Quit() {
 	m.quitChan <- struct{}{}
+	<-m.quitChan
 }
 
 // Send Inventory message to all peers
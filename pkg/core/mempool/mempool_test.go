@@ -5,8 +5,11 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"math/big"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -295,6 +298,60 @@ func TestRemoveAccepted(t *testing.T) {
 	c.assert(t, false)
 }
 
+// TestRemoveConflicting ensures mempool drops txs spending key images that are
+// spent by the txs of an accepted block
+func TestRemoveConflicting(t *testing.T) {
+
+	c.reset()
+
+	txs := randomSliceOfTxs(t, 2)
+	for _, tx := range txs {
+		c.bus.Publish(topics.Tx, prepTx(tx))
+	}
+
+	c.wait()
+
+	// all txs but the first one are expected to stay in mempool
+	for _, tx := range txs[1:] {
+		c.addTx(tx)
+	}
+
+	// block contains a tx, not known to mempool, that spends the inputs of
+	// the first mempool tx
+	tx := helper.RandomStandardTx(t, false)
+	tx.Inputs = txs[0].StandardTx().Inputs
+
+	b := helper.RandomBlock(t, 200, 0)
+	b.Txs = make([]transactions.Transaction, 0)
+	b.AddTx(tx)
+
+	root, _ := b.CalculateRoot()
+	b.Header.TxRoot = root
+	c.bus.Publish(topics.IntermediateBlock, message.New(topics.IntermediateBlock, *b))
+
+	c.assert(t, false)
+}
+
+// TestReinjectDisconnected ensures mempool reinserts the non-coinbase txs of a
+// reverted block
+func TestReinjectDisconnected(t *testing.T) {
+
+	c.reset()
+
+	b := helper.RandomBlock(t, 200, 0)
+	b.Txs = make([]transactions.Transaction, 0)
+	b.AddTx(helper.RandomCoinBaseTx(t, false))
+
+	for _, tx := range randomSliceOfTxs(t, 2) {
+		b.AddTx(tx)
+		c.addTx(tx)
+	}
+
+	c.bus.Publish(topics.DisconnectedBlock, message.New(topics.DisconnectedBlock, *b))
+
+	c.assert(t, false)
+}
+
 // TestDoubleSpent ensures mempool rejects txs with keyImages that have been
 // already spent from other transactions in the pool.
 func TestDoubleSpent(t *testing.T) {
@@ -332,6 +389,61 @@ func TestDoubleSpent(t *testing.T) {
 	c.assert(t, false)
 }
 
+// TestReplaceByFee ensures a conflicting tx replaces the verified one only if
+// it pays a sufficiently higher fee
+func TestReplaceByFee(t *testing.T) {
+
+	c.reset()
+
+	r := config.Get()
+	r.Mempool.ReplaceByFee = true
+	r.Mempool.MinFeeBump = 10
+	config.Mock(&r)
+
+	defer func() {
+		r.Mempool.ReplaceByFee = false
+		config.Mock(&r)
+	}()
+
+	replacedChan := make(chan message.Message, 1)
+	id := c.bus.Subscribe(topics.ReplacedTx, eventbus.NewChanListener(replacedChan))
+	defer c.bus.Unsubscribe(topics.ReplacedTx, id)
+
+	tx := helper.RandomStandardTx(t, false)
+	tx.Fee.SetBigInt(big.NewInt(100))
+	if _, err := c.rpcBus.Call(topics.SendMempoolTx, rpcbus.NewRequest(tx), 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fee bump lower than 10% is rejected
+	lowFeeTx := helper.RandomStandardTx(t, false)
+	lowFeeTx.Inputs = tx.Inputs
+	lowFeeTx.Fee.SetBigInt(big.NewInt(105))
+	_, err := c.rpcBus.Call(topics.SendMempoolTx, rpcbus.NewRequest(lowFeeTx), 5*time.Second)
+	assert.Equal(t, ErrReplacementFeeTooLow, err)
+
+	highFeeTx := helper.RandomStandardTx(t, false)
+	highFeeTx.Inputs = tx.Inputs
+	highFeeTx.Fee.SetBigInt(big.NewInt(200))
+	if _, err := c.rpcBus.Call(topics.SendMempoolTx, rpcbus.NewRequest(highFeeTx), 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	c.addTx(highFeeTx)
+
+	select {
+	case msg := <-replacedChan:
+		replacement := msg.Payload().(Replacement)
+		highFeeTxID, _ := highFeeTx.CalculateHash()
+		txID, _ := tx.CalculateHash()
+		assert.Equal(t, highFeeTxID, replacement.TxID)
+		assert.Equal(t, [][]byte{txID}, replacement.Replaced)
+	case <-time.After(1 * time.Second):
+		t.Fatal("replacement event not published")
+	}
+
+	c.assert(t, false)
+}
+
 func TestCoinbaseTxsNotAllowed(t *testing.T) {
 
 	c.reset()
@@ -438,6 +550,55 @@ func TestMempoolView(t *testing.T) {
 	assert.Equal(t, numTxs, len(s.Result))
 }
 
+// TestPersistence ensures verified txs are dumped on Quit and reloaded on the
+// next Run
+func TestPersistence(t *testing.T) {
+
+	c.reset()
+
+	dir, err := ioutil.TempDir("", "mempool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := config.Get()
+	r.Mempool.PersistFile = filepath.Join(dir, "mempool.dat")
+	config.Mock(&r)
+
+	defer func() {
+		r.Mempool.PersistFile = ""
+		config.Mock(&r)
+	}()
+
+	txs := randomSliceOfTxs(t, 2)
+	for _, tx := range txs {
+		c.addTx(tx)
+		if _, err := c.rpcBus.Call(topics.SendMempoolTx, rpcbus.NewRequest(tx), 5*time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Quit is expected to dump all verified txs
+	c.m.Quit()
+
+	persisted, err := restore(r.Mempool.PersistFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, len(txs), len(persisted))
+	for _, p := range persisted {
+		assert.True(t, p.local)
+	}
+
+	// Simulate a restart with an empty pool
+	c.m.verified = c.m.newPool()
+	c.m.Run()
+
+	c.assert(t, false)
+}
+
 // Only difference with helper.RandomSliceOfTxs is lack of appending a coinbase tx
 func randomSliceOfTxs(t *testing.T, txsBatchCount uint16) []transactions.Transaction {
 	var txs []transactions.Transaction
@@ -0,0 +1,107 @@
+package mempool
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+)
+
+// persist dumps all verified txs into the file configured by
+// Mempool.PersistFile. The dump is written into a temporary file first and
+// then renamed so that a crash in the middle of writing never corrupts the
+// previous dump.
+func (m *Mempool) persist(path string) error {
+
+	buf := new(bytes.Buffer)
+	if err := encoding.WriteVarInt(buf, uint64(m.verified.Len())); err != nil {
+		return err
+	}
+
+	err := m.verified.Range(func(k txHash, t TxDesc) error {
+		return marshalTxDesc(buf, t)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// restore loads the txs dumped by persist. Txs are returned as they were
+// stored. The caller is responsible to reverify them against the current
+// chain state.
+func restore(path string) ([]TxDesc, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(data)
+	count, err := encoding.ReadVarInt(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]TxDesc, 0, count)
+	for i := uint64(0); i < count; i++ {
+		t, err := unmarshalTxDesc(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, t)
+	}
+
+	return txs, nil
+}
+
+func marshalTxDesc(w *bytes.Buffer, t TxDesc) error {
+	if err := encoding.WriteBool(w, t.local); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteUint64LE(w, uint64(t.received.Unix())); err != nil {
+		return err
+	}
+
+	return message.MarshalTx(w, t.tx)
+}
+
+func unmarshalTxDesc(r *bytes.Buffer) (TxDesc, error) {
+	var t TxDesc
+	if err := encoding.ReadBool(r, &t.local); err != nil {
+		return t, err
+	}
+
+	var received uint64
+	if err := encoding.ReadUint64LE(r, &received); err != nil {
+		return t, err
+	}
+	t.received = time.Unix(int64(received), 0)
+
+	// the size of the marshaled tx is the difference of the unread bytes
+	// before and after the unmarshaling
+	l := r.Len()
+	tx, err := message.UnmarshalTx(r)
+	if err != nil {
+		return t, err
+	}
+
+	t.tx = tx
+	t.size = uint(l - r.Len())
+	return t, nil
+}
@@ -0,0 +1,285 @@
+package mempool
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	"github.com/dusk-network/dusk-wallet/v2/transactions"
+	"github.com/stretchr/testify/assert"
+)
+
+// poolFactories lists all Pool implementations. Each one must pass the
+// conformance suite below
+var poolFactories = map[string]func(capacity uint32) Pool{
+	"hashmap":  func(capacity uint32) Pool { return &HashMap{Capacity: capacity} },
+	"syncpool": func(capacity uint32) Pool { return NewSyncPool(capacity) },
+	"skiplist": func(capacity uint32) Pool { return NewSkipList(capacity) },
+}
+
+var conformanceTests = map[string]func(t *testing.T, newPool func(uint32) Pool){
+	"PutGet":          testPoolPutGet,
+	"KeyImages":       testPoolKeyImages,
+	"SizeLen":         testPoolSizeLen,
+	"CloneFilter":     testPoolCloneFilter,
+	"Range":           testPoolRange,
+	"RangeSort":       testPoolRangeSort,
+	"RangeSortStable": testPoolRangeSortStable,
+	"RangeSortDone":   testPoolRangeSortDone,
+	"Delete":          testPoolDelete,
+}
+
+func TestPoolConformance(t *testing.T) {
+	for poolName, newPool := range poolFactories {
+		for testName, test := range conformanceTests {
+			newPool, test := newPool, test
+			t.Run(poolName+"/"+testName, func(t *testing.T) {
+				test(t, newPool)
+			})
+		}
+	}
+}
+
+func putTxs(t *testing.T, p Pool, txs []transactions.Transaction) {
+	for i, tx := range txs {
+		td := TxDesc{tx: tx, received: time.Now(), size: uint(i + 1)}
+		if err := p.Put(td); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func randomFeeTxs(t *testing.T, count int) []transactions.Transaction {
+	txs := make([]transactions.Transaction, count)
+	for i := range txs {
+		tx := helper.RandomStandardTx(t, false)
+		tx.Fee.SetBigInt(big.NewInt(0).SetUint64(uint64(rand.Intn(10000))))
+		txs[i] = tx
+	}
+	return txs
+}
+
+func testPoolPutGet(t *testing.T, newPool func(uint32) Pool) {
+	p := newPool(10)
+	txs := randomSliceOfTxs(t, 2)
+	putTxs(t, p, txs)
+
+	for _, tx := range txs {
+		txID, _ := tx.CalculateHash()
+		assert.True(t, p.Contains(txID))
+		assert.NotNil(t, p.Get(txID))
+		assert.True(t, p.Get(txID).Equals(tx))
+	}
+
+	hash, _ := crypto.RandEntropy(32)
+	assert.False(t, p.Contains(hash))
+	assert.Nil(t, p.Get(hash))
+}
+
+func testPoolKeyImages(t *testing.T, newPool func(uint32) Pool) {
+	p := newPool(10)
+	txs := randomFeeTxs(t, 5)
+	putTxs(t, p, txs)
+
+	for _, tx := range txs {
+		for _, input := range tx.StandardTx().Inputs {
+			assert.True(t, p.ContainsKeyImage(input.KeyImage.Bytes()))
+		}
+	}
+
+	ki, _ := crypto.RandEntropy(32)
+	assert.False(t, p.ContainsKeyImage(ki))
+}
+
+func testPoolSizeLen(t *testing.T, newPool func(uint32) Pool) {
+	p := newPool(10)
+	assert.Equal(t, 0, p.Len())
+	assert.Equal(t, uint32(0), p.Size())
+
+	txs := randomFeeTxs(t, 5)
+	putTxs(t, p, txs)
+
+	// sizes are 1, 2, 3, 4, 5
+	assert.Equal(t, 5, p.Len())
+	assert.Equal(t, uint32(15), p.Size())
+}
+
+func testPoolCloneFilter(t *testing.T, newPool func(uint32) Pool) {
+	p := newPool(10)
+	txs := randomSliceOfTxs(t, 3)
+	putTxs(t, p, txs)
+
+	assert.Equal(t, len(txs), len(p.Clone()))
+
+	// randomSliceOfTxs generates a tx of each type per batch
+	assert.Equal(t, 3, len(p.FilterByType(transactions.StandardType)))
+	assert.Equal(t, 3, len(p.FilterByType(transactions.StakeType)))
+	assert.Equal(t, 0, len(p.FilterByType(transactions.CoinbaseType)))
+}
+
+func testPoolRange(t *testing.T, newPool func(uint32) Pool) {
+	p := newPool(10)
+	txs := randomFeeTxs(t, 10)
+	putTxs(t, p, txs)
+
+	seen := make(map[txHash]bool)
+	err := p.Range(func(k txHash, t TxDesc) error {
+		seen[k] = true
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, len(txs), len(seen))
+
+	// Range must stop on the first error
+	var calls int
+	errStop := errors.New("stop")
+	err = p.Range(func(k txHash, t TxDesc) error {
+		calls++
+		return errStop
+	})
+
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 1, calls)
+}
+
+func testPoolRangeSort(t *testing.T, newPool func(uint32) Pool) {
+	p := newPool(100)
+	txs := randomFeeTxs(t, 100)
+	putTxs(t, p, txs)
+
+	var count int
+	prevVal := uint64(math.MaxUint64)
+	err := p.RangeSort(func(k txHash, t TxDesc) (bool, error) {
+		val := t.tx.StandardTx().Fee.BigInt().Uint64()
+		if prevVal < val {
+			return false, errors.New("keys not in a descending order")
+		}
+
+		count++
+		prevVal = val
+		return false, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, len(txs), count)
+}
+
+func testPoolRangeSortStable(t *testing.T, newPool func(uint32) Pool) {
+	p := newPool(100)
+	for i := 0; i < 100; i++ {
+		tx := helper.RandomStandardTx(t, false)
+		tx.Fee.SetBigInt(big.NewInt(20))
+
+		if err := p.Put(TxDesc{tx: tx, received: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// order of receiving is kept when tx has same fee
+	var prevReceived time.Time
+	err := p.RangeSort(func(k txHash, t TxDesc) (bool, error) {
+		if prevReceived.After(t.received) {
+			return false, errors.New("order of receiving should be kept")
+		}
+
+		prevReceived = t.received
+		return false, nil
+	})
+
+	assert.NoError(t, err)
+}
+
+func testPoolRangeSortDone(t *testing.T, newPool func(uint32) Pool) {
+	p := newPool(10)
+	putTxs(t, p, randomFeeTxs(t, 10))
+
+	var calls int
+	err := p.RangeSort(func(k txHash, t TxDesc) (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func testPoolDelete(t *testing.T, newPool func(uint32) Pool) {
+	p := newPool(50)
+
+	// few distinct fees, so that txs with equal Fee get deleted
+	txs := make([]transactions.Transaction, 50)
+	for i := range txs {
+		tx := helper.RandomStandardTx(t, false)
+		tx.Fee.SetBigInt(big.NewInt(int64(rand.Intn(5))))
+		txs[i] = tx
+	}
+	putTxs(t, p, txs)
+
+	size := p.Size()
+	for i := 0; i < len(txs); i += 2 {
+		txID, _ := txs[i].CalculateHash()
+		assert.True(t, p.Delete(txID))
+		assert.False(t, p.Delete(txID))
+		assert.False(t, p.Contains(txID))
+		size -= uint32(i + 1)
+
+		for _, input := range txs[i].StandardTx().Inputs {
+			assert.False(t, p.ContainsKeyImage(input.KeyImage.Bytes()))
+		}
+	}
+
+	assert.Equal(t, 25, p.Len())
+	assert.Equal(t, size, p.Size())
+
+	// the remaining txs are still sorted
+	prevFee := uint64(math.MaxUint64)
+	count := 0
+	err := p.RangeSort(func(k txHash, d TxDesc) (bool, error) {
+		fee := d.tx.StandardTx().Fee.BigInt().Uint64()
+		if fee > prevFee {
+			return true, errors.New("pool is not sorted")
+		}
+		prevFee = fee
+		count++
+		return false, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 25, count)
+}
+
+func BenchmarkPoolRangeSortTop(b *testing.B) {
+
+	txs := dummyTransactionsSet(10000)
+
+	for poolName, newPool := range poolFactories {
+		p := newPool(uint32(len(txs)))
+		for i := 0; i < len(txs); i++ {
+			td := TxDesc{tx: txs[i], received: time.Now(), size: uint(i)}
+			if err := p.Put(td); err != nil {
+				b.Fatalf(err.Error())
+			}
+		}
+
+		// fetch the 100 highest-fee txs as the block generator would do
+		b.Run(poolName, func(b *testing.B) {
+			for tN := 0; tN < b.N; tN++ {
+				var count int
+				err := p.RangeSort(func(k txHash, t TxDesc) (bool, error) {
+					count++
+					return count == 100, nil
+				})
+
+				if err != nil {
+					b.Fatalf(err.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package mempool
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+)
+
+// ErrReplacementFeeTooLow a conflicting tx does not pay enough to replace the
+// mempool txs spending the same key images
+var ErrReplacementFeeTooLow = errors.New("replacement fee too low")
+
+// ErrTooManyReplacements a conflicting tx spends the key images of too many
+// mempool txs to replace them
+var ErrTooManyReplacements = errors.New("too many replaced txs")
+
+// maxReplacedTxs bounds the txs a single tx can replace, and thus the work a
+// replacement costs
+const maxReplacedTxs = 100
+
+// Replacement is published on topics.ReplacedTx when a verified tx is evicted
+// in favour of a conflicting tx paying a higher fee
+type Replacement struct {
+	// TxID of the replacing tx
+	TxID []byte
+	// Replaced are the ids of the evicted txs
+	Replaced [][]byte
+}
+
+// checkReplaceByFee returns the verified txs that the given tx is allowed to
+// replace according to the replace-by-fee policy. The tx must pay both a higher
+// absolute fee than all the conflicting txs together and a higher fee rate than
+// any of them. Both are bumped by Mempool.MinFeeBump percents. The fees are
+// compared as integers, the fee rates as the products of the fees and the
+// sizes.
+func (m *Mempool) checkReplaceByFee(t TxDesc) ([]TxDesc, error) {
+
+	cfg := config.Get().Mempool
+	if !cfg.ReplaceByFee {
+		return nil, ErrDoubleSpending
+	}
+
+	spent := make(map[keyImage]bool)
+	for _, input := range t.tx.StandardTx().Inputs {
+		var ki keyImage
+		copy(ki[:], input.KeyImage.Bytes())
+		spent[ki] = true
+	}
+
+	replaced := make([]TxDesc, 0)
+	err := m.verified.Range(func(k txHash, d TxDesc) error {
+		if conflicts(d.tx, spent) {
+			if len(replaced) == maxReplacedTxs {
+				return ErrTooManyReplacements
+			}
+			replaced = append(replaced, d)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	hundred := big.NewInt(100)
+	bump := new(big.Int).SetUint64(100 + uint64(cfg.MinFeeBump))
+
+	totalFee := new(big.Int)
+	for _, d := range replaced {
+		totalFee.Add(totalFee, fee(d))
+	}
+
+	// newFee > totalFee and newFee * 100 >= totalFee * (100 + bump)
+	newFee := fee(t)
+	if newFee.Cmp(totalFee) <= 0 || mul(newFee, hundred).Cmp(mul(totalFee, bump)) < 0 {
+		return nil, ErrReplacementFeeTooLow
+	}
+
+	// newFee / newSize * 100 >= fee / size * (100 + bump)
+	for _, d := range replaced {
+		lhs := mul(mul(newFee, size(d)), hundred)
+		rhs := mul(mul(fee(d), size(t)), bump)
+		if lhs.Cmp(rhs) < 0 {
+			return nil, ErrReplacementFeeTooLow
+		}
+	}
+
+	return replaced, nil
+}
+
+// replace stores the tx in the verified pool in place of the conflicting txs,
+// and notifies the subscribers of the replacement. The pool is left as it was
+// if the tx cannot be stored
+func (m *Mempool) replace(txid []byte, t TxDesc, replaced []TxDesc) error {
+
+	ids := make([][]byte, 0, len(replaced))
+	for _, d := range replaced {
+		id, err := d.tx.CalculateHash()
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+
+	// the conflicting txs are deleted first, as they share key images with
+	// the tx
+	for _, id := range ids {
+		m.verified.Delete(id)
+	}
+
+	if err := m.verified.Put(t); err != nil {
+		m.verified.Delete(txid)
+		for _, d := range replaced {
+			_ = m.verified.Put(d)
+		}
+		return err
+	}
+
+	for _, id := range ids {
+		var k txHash
+		copy(k[:], id)
+		m.rebroadcaster.remove(k)
+		log.Infof("Replaced txid=%s by txid=%s", toHex(id), toHex(txid))
+	}
+
+	msg := message.New(topics.ReplacedTx, Replacement{TxID: txid, Replaced: ids})
+	m.eventBus.Publish(topics.ReplacedTx, msg)
+	return nil
+}
+
+func fee(t TxDesc) *big.Int {
+	return t.tx.StandardTx().Fee.BigInt()
+}
+
+// size is the size of the marshaled tx, at least 1 byte
+func size(t TxDesc) *big.Int {
+	if t.size == 0 {
+		return big.NewInt(1)
+	}
+	return new(big.Int).SetUint64(uint64(t.size))
+}
+
+func mul(x, y *big.Int) *big.Int {
+	return new(big.Int).Mul(x, y)
+}
@@ -0,0 +1,126 @@
+package mempool
+
+import (
+	"sort"
+)
+
+// maxBackoffShift limits the exponent of the back-off so that the interval
+// computation never overflows
+const maxBackoffShift = 32
+
+// RebroadcastEntry describes a locally-submitted tx which is scheduled to be
+// re-advertised until it gets included in a block
+type RebroadcastEntry struct {
+	TxID []byte
+	// Attempts is the number of re-advertisements done so far
+	Attempts uint
+	// NextHeight is the block height at which the tx is re-advertised next
+	NextHeight uint64
+}
+
+// rebroadcaster keeps track of the locally-submitted txs pending inclusion.
+// A tx is re-advertised each time it was not included for a number of
+// blocks. The number of blocks is doubled on each attempt, up to a limit.
+//
+// It is used only from within the mempool main loop so no protection-by-mutex
+// is needed
+type rebroadcaster struct {
+	entries map[txHash]*RebroadcastEntry
+
+	// interval is the number of blocks to wait before the first attempt
+	interval uint64
+	// maxInterval caps the exponential back-off
+	maxInterval uint64
+}
+
+func newRebroadcaster(interval, maxInterval uint64) *rebroadcaster {
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+
+	return &rebroadcaster{
+		entries:     make(map[txHash]*RebroadcastEntry),
+		interval:    interval,
+		maxInterval: maxInterval,
+	}
+}
+
+// enabled returns false if rebroadcasting is turned off by config
+func (r *rebroadcaster) enabled() bool {
+	return r.interval > 0
+}
+
+// add schedules a tx, advertised at the given height, for rebroadcasting
+func (r *rebroadcaster) add(txID []byte, height uint64) {
+	if !r.enabled() {
+		return
+	}
+
+	var k txHash
+	copy(k[:], txID)
+	r.entries[k] = &RebroadcastEntry{
+		TxID:       k[:],
+		NextHeight: height + r.interval,
+	}
+}
+
+// remove unschedules a tx
+func (r *rebroadcaster) remove(k txHash) {
+	delete(r.entries, k)
+}
+
+// prune unschedules all txs which are no longer in the pool, either because
+// they were accepted or because they were dropped
+func (r *rebroadcaster) prune(p Pool) {
+	for k := range r.entries {
+		if !p.Contains(k[:]) {
+			r.remove(k)
+		}
+	}
+}
+
+// due returns the txs to be re-advertised at the given height and schedules
+// their next attempt
+func (r *rebroadcaster) due(height uint64) [][]byte {
+	txIDs := make([][]byte, 0)
+	for _, e := range r.entries {
+		if height < e.NextHeight {
+			continue
+		}
+
+		e.Attempts++
+		e.NextHeight = height + r.backoff(e.Attempts)
+		txIDs = append(txIDs, e.TxID)
+	}
+
+	return txIDs
+}
+
+// backoff returns the number of blocks to wait after the given number of
+// attempts
+func (r *rebroadcaster) backoff(attempts uint) uint64 {
+	if attempts > maxBackoffShift {
+		attempts = maxBackoffShift
+	}
+
+	blocks := r.interval << attempts
+	if blocks > r.maxInterval || blocks < r.interval {
+		return r.maxInterval
+	}
+
+	return blocks
+}
+
+// pending returns all scheduled txs sorted by the height of the next attempt
+func (r *rebroadcaster) pending() []RebroadcastEntry {
+	list := make([]RebroadcastEntry, 0, len(r.entries))
+	for _, e := range r.entries {
+		list = append(list, *e)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].NextHeight < list[j].NextHeight
+	})
+
+	return list
+}
@@ -0,0 +1,60 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebroadcastBackoff(t *testing.T) {
+
+	r := newRebroadcaster(2, 10)
+
+	txid := make([]byte, 32)
+	txid[0] = 1
+	r.add(txid, 100)
+
+	// Not due before interval elapses
+	assert.Empty(t, r.due(101))
+
+	// First attempt at 102, next one is expected after 4 blocks
+	assert.Equal(t, 1, len(r.due(102)))
+	assert.Empty(t, r.due(105))
+	assert.Equal(t, 1, len(r.due(106)))
+
+	// Back-off is doubled (8 blocks) and then capped (10 blocks)
+	assert.Equal(t, uint64(114), r.pending()[0].NextHeight)
+	assert.Equal(t, 1, len(r.due(114)))
+	assert.Equal(t, uint64(124), r.pending()[0].NextHeight)
+	assert.Equal(t, uint(3), r.pending()[0].Attempts)
+}
+
+func TestRebroadcastPrune(t *testing.T) {
+
+	r := newRebroadcaster(1, 10)
+	p := &HashMap{Capacity: 1}
+
+	tx := helper.RandomStandardTx(t, false)
+	txid, _ := tx.CalculateHash()
+	if err := p.Put(TxDesc{tx: tx}); err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := make([]byte, 32)
+	r.add(txid, 0)
+	r.add(accepted, 0)
+
+	// Only txs still in the pool are kept scheduled
+	r.prune(p)
+	pending := r.pending()
+	assert.Equal(t, 1, len(pending))
+	assert.Equal(t, txid, pending[0].TxID)
+}
+
+func TestRebroadcastDisabled(t *testing.T) {
+
+	r := newRebroadcaster(0, 0)
+	r.add(make([]byte, 32), 0)
+	assert.Empty(t, r.pending())
+}
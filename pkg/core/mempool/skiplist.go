@@ -0,0 +1,269 @@
+package mempool
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/dusk-network/dusk-wallet/v2/transactions"
+)
+
+const (
+	// maxSkipLevel allows efficient indexing of up to 2^maxSkipLevel txs
+	maxSkipLevel = 24
+	// skipProbability is the probability of a node to be promoted to the
+	// next level
+	skipProbability = 0.25
+)
+
+type (
+	skipNode struct {
+		k    txHash
+		fee  uint64
+		t    TxDesc
+		next []*skipNode
+	}
+
+	// SkipList represents a pool implementation which indexes txs by Fee in a
+	// skip list. Both inserting a tx and deleting it are O(log n), while
+	// RangeSort iterating through the k highest-fee txs is O(k) as the list
+	// is always kept sorted.
+	SkipList struct {
+		data map[txHash]*skipNode
+
+		// head is a sentinel node. The list is sorted by Fee in a descending
+		// order. Txs with equal Fee are kept in order of insertion.
+		head  *skipNode
+		level int
+
+		// spent key images from the transactions in the pool
+		spentkeyImages map[keyImage]bool
+		txsSize        uint32
+
+		rnd *rand.Rand
+	}
+)
+
+// NewSkipList instantiates an empty SkipList with preallocated space for
+// capacity txs
+func NewSkipList(capacity uint32) *SkipList {
+	return &SkipList{
+		data:           make(map[txHash]*skipNode, capacity),
+		head:           &skipNode{next: make([]*skipNode, maxSkipLevel)},
+		level:          1,
+		spentkeyImages: make(map[keyImage]bool),
+		rnd:            rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+// Put sets the value for the given key. It overwrites any previous value
+// for that key;
+func (s *SkipList) Put(t TxDesc) error {
+
+	txID, err := t.tx.CalculateHash()
+	if err != nil {
+		return err
+	}
+
+	var k txHash
+	copy(k[:], txID)
+
+	// the txID commits to the Fee, so an existing entry keeps its position
+	if n, ok := s.data[k]; ok {
+		s.txsSize -= uint32(n.t.size)
+		s.txsSize += uint32(t.size)
+		n.t = t
+		return nil
+	}
+
+	// store all tx key images, if provided
+	for i, input := range t.tx.StandardTx().Inputs {
+		if len(input.KeyImage.Bytes()) != keyImageSize {
+			return fmt.Errorf("invalid key image found at index %d", i)
+		}
+	}
+
+	for _, input := range t.tx.StandardTx().Inputs {
+		var ki keyImage
+		copy(ki[:], input.KeyImage.Bytes())
+		s.spentkeyImages[ki] = true
+	}
+
+	fee := t.tx.StandardTx().Fee.BigInt().Uint64()
+	n := &skipNode{k: k, fee: fee, t: t, next: make([]*skipNode, s.randomLevel())}
+
+	// find the last node on each level with Fee not lower than the new one.
+	// That keeps the order of insertion for equal fees
+	update := make([]*skipNode, len(n.next))
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].fee >= fee {
+			x = x.next[i]
+		}
+
+		if i < len(update) {
+			update[i] = x
+		}
+	}
+
+	for i := s.level; i < len(n.next); i++ {
+		update[i] = s.head
+	}
+
+	if len(n.next) > s.level {
+		s.level = len(n.next)
+	}
+
+	for i := range n.next {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+
+	s.data[k] = n
+	s.txsSize += uint32(t.size)
+	return nil
+}
+
+// Delete removes the tx with the given txID, along with its key images
+func (s *SkipList) Delete(txID []byte) bool {
+	var k txHash
+	copy(k[:], txID)
+	n, ok := s.data[k]
+	if !ok {
+		return false
+	}
+
+	// find the last node before n on each level. The nodes with a higher Fee
+	// are skipped, then the ones with the same Fee are walked until n
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].fee > n.fee {
+			x = x.next[i]
+		}
+
+		if i >= len(n.next) {
+			continue
+		}
+
+		y := x
+		for y.next[i] != nil && y.next[i] != n && y.next[i].fee == n.fee {
+			y = y.next[i]
+		}
+
+		if y.next[i] == n {
+			y.next[i] = n.next[i]
+		}
+	}
+
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+
+	delete(s.data, k)
+	s.txsSize -= uint32(n.t.size)
+
+	for _, input := range n.t.tx.StandardTx().Inputs {
+		var ki keyImage
+		copy(ki[:], input.KeyImage.Bytes())
+		delete(s.spentkeyImages, ki)
+	}
+
+	return true
+}
+
+func (s *SkipList) randomLevel() int {
+	level := 1
+	for level < maxSkipLevel && s.rnd.Float64() < skipProbability {
+		level++
+	}
+	return level
+}
+
+// Clone the entire pool
+func (s *SkipList) Clone() []transactions.Transaction {
+
+	r := make([]transactions.Transaction, 0, len(s.data))
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		r = append(r, n.t.tx)
+	}
+
+	return r
+}
+
+// FilterByType returns all transactions for a specific type that are
+// currently in the SkipList.
+func (s *SkipList) FilterByType(filterType transactions.TxType) []transactions.Transaction {
+	txs := make([]transactions.Transaction, 0)
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		if n.t.tx.Type() == filterType {
+			txs = append(txs, n.t.tx)
+		}
+	}
+
+	return txs
+}
+
+// Contains returns true if the given key is in the pool.
+func (s *SkipList) Contains(txID []byte) bool {
+	var k txHash
+	copy(k[:], txID)
+	_, ok := s.data[k]
+	return ok
+}
+
+// Get returns a tx for a given txID if it exists.
+func (s *SkipList) Get(txID []byte) transactions.Transaction {
+	var k txHash
+	copy(k[:], txID)
+	n, ok := s.data[k]
+	if !ok {
+		return nil
+	}
+	return n.t.tx
+}
+
+// Size of the txs
+func (s *SkipList) Size() uint32 {
+	return s.txsSize
+}
+
+// Len returns the number of tx entries
+func (s *SkipList) Len() int {
+	return len(s.data)
+}
+
+// Range iterates through all tx entries
+func (s *SkipList) Range(fn func(k txHash, t TxDesc) error) error {
+
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		if err := fn(n.k, n.t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RangeSort iterates through all tx entries sorted by Fee
+// in a descending order
+func (s *SkipList) RangeSort(fn func(k txHash, t TxDesc) (bool, error)) error {
+
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		done, err := fn(n.k, n.t)
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+	}
+	return nil
+}
+
+// ContainsKeyImage returns true if txpool includes a input that contains
+// this keyImage
+func (s *SkipList) ContainsKeyImage(txInputKeyImage []byte) bool {
+	var ki keyImage
+	copy(ki[:], txInputKeyImage)
+	_, ok := s.spentkeyImages[ki]
+	return ok
+}
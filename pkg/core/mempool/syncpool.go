@@ -0,0 +1,258 @@
+package mempool
+
+import (
+	"container/heap"
+	"sync"
+
+	"github.com/dusk-network/dusk-wallet/v2/transactions"
+)
+
+// shardsNum is the number of HashMap shards of a SyncPool
+const shardsNum = 16
+
+type (
+	poolShard struct {
+		mu sync.RWMutex
+		HashMap
+	}
+
+	// SyncPool represents a pool implementation safe for concurrent use. Txs
+	// are distributed over a number of HashMap shards, by txID, each one
+	// protected by its own mutex. That reduces lock contention when the pool
+	// is accessed out of the mempool main loop.
+	//
+	// Range and RangeSort hold a read lock on all shards while iterating so
+	// the callback must not modify the pool.
+	SyncPool struct {
+		shards [shardsNum]*poolShard
+	}
+)
+
+// NewSyncPool instantiates an empty SyncPool with preallocated space for
+// capacity txs
+func NewSyncPool(capacity uint32) *SyncPool {
+	p := &SyncPool{}
+	for i := range p.shards {
+		p.shards[i] = &poolShard{HashMap: HashMap{Capacity: capacity/shardsNum + 1}}
+	}
+	return p
+}
+
+func (p *SyncPool) shard(txID []byte) *poolShard {
+	return p.shards[txID[0]%shardsNum]
+}
+
+func (p *SyncPool) rLockAll() {
+	for _, s := range p.shards {
+		s.mu.RLock()
+	}
+}
+
+func (p *SyncPool) rUnlockAll() {
+	for _, s := range p.shards {
+		s.mu.RUnlock()
+	}
+}
+
+// Put sets the value for the given key. It overwrites any previous value
+// for that key;
+func (p *SyncPool) Put(t TxDesc) error {
+	txID, err := t.tx.CalculateHash()
+	if err != nil {
+		return err
+	}
+
+	s := p.shard(txID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Put(t)
+}
+
+// Delete removes the tx with the given txID, along with its key images
+func (p *SyncPool) Delete(txID []byte) bool {
+	if len(txID) == 0 {
+		return false
+	}
+
+	s := p.shard(txID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Delete(txID)
+}
+
+// Clone the entire pool
+func (p *SyncPool) Clone() []transactions.Transaction {
+	r := make([]transactions.Transaction, 0)
+	for _, s := range p.shards {
+		s.mu.RLock()
+		r = append(r, s.Clone()...)
+		s.mu.RUnlock()
+	}
+
+	return r
+}
+
+// FilterByType returns all transactions for a specific type that are
+// currently in the SyncPool.
+func (p *SyncPool) FilterByType(filterType transactions.TxType) []transactions.Transaction {
+	txs := make([]transactions.Transaction, 0)
+	for _, s := range p.shards {
+		s.mu.RLock()
+		txs = append(txs, s.FilterByType(filterType)...)
+		s.mu.RUnlock()
+	}
+
+	return txs
+}
+
+// Contains returns true if the given key is in the pool.
+func (p *SyncPool) Contains(txID []byte) bool {
+	if len(txID) == 0 {
+		return false
+	}
+
+	s := p.shard(txID)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Contains(txID)
+}
+
+// Get returns a tx for a given txID if it exists.
+func (p *SyncPool) Get(txID []byte) transactions.Transaction {
+	if len(txID) == 0 {
+		return nil
+	}
+
+	s := p.shard(txID)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Get(txID)
+}
+
+// Size of the txs
+func (p *SyncPool) Size() uint32 {
+	var size uint32
+	for _, s := range p.shards {
+		s.mu.RLock()
+		size += s.Size()
+		s.mu.RUnlock()
+	}
+	return size
+}
+
+// Len returns the number of tx entries
+func (p *SyncPool) Len() int {
+	var l int
+	for _, s := range p.shards {
+		s.mu.RLock()
+		l += s.Len()
+		s.mu.RUnlock()
+	}
+	return l
+}
+
+// Range iterates through all tx entries
+func (p *SyncPool) Range(fn func(k txHash, t TxDesc) error) error {
+	p.rLockAll()
+	defer p.rUnlockAll()
+
+	for _, s := range p.shards {
+		if err := s.Range(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RangeSort iterates through all tx entries sorted by Fee
+// in a descending order. Shards are already sorted so they are merged on the
+// fly. Txs with equal Fee are ordered by the time of receiving.
+func (p *SyncPool) RangeSort(fn func(k txHash, t TxDesc) (bool, error)) error {
+	p.rLockAll()
+	defer p.rUnlockAll()
+
+	h := make(shardHeap, 0, shardsNum)
+	for _, s := range p.shards {
+		if len(s.sorted) > 0 {
+			h = append(h, &shardCursor{shard: s})
+		}
+	}
+	heap.Init(&h)
+
+	for h.Len() > 0 {
+		c := h[0]
+		k := c.shard.sorted[c.pos].k
+
+		done, err := fn(k, c.shard.data[k])
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		c.pos++
+		if c.pos < len(c.shard.sorted) {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+
+	return nil
+}
+
+// ContainsKeyImage returns true if txpool includes a input that contains
+// this keyImage
+func (p *SyncPool) ContainsKeyImage(txInputKeyImage []byte) bool {
+	for _, s := range p.shards {
+		s.mu.RLock()
+		ok := s.ContainsKeyImage(txInputKeyImage)
+		s.mu.RUnlock()
+
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// shardCursor points at the next entry of a shard to be merged by RangeSort
+type shardCursor struct {
+	shard *poolShard
+	pos   int
+}
+
+func (c *shardCursor) head() (uint64, TxDesc) {
+	kf := c.shard.sorted[c.pos]
+	return kf.f, c.shard.data[kf.k]
+}
+
+// shardHeap implements heap.Interface as a max-heap of shard cursors by Fee
+type shardHeap []*shardCursor
+
+func (h shardHeap) Len() int { return len(h) }
+
+func (h shardHeap) Less(i, j int) bool {
+	fi, ti := h[i].head()
+	fj, tj := h[j].head()
+	if fi != fj {
+		return fi > fj
+	}
+	return ti.received.Before(tj.received)
+}
+
+func (h shardHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *shardHeap) Push(x interface{}) {
+	*h = append(*h, x.(*shardCursor))
+}
+
+func (h *shardHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	c := old[n-1]
+	*h = old[:n-1]
+	return c
+}
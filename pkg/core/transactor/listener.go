@@ -21,7 +21,9 @@ import (
 var (
 	log = logger.WithFields(logger.Fields{"prefix": "transactor"})
 
-	errWalletNotLoaded     = errors.New("wallet is not loaded yet")
+	// ErrWalletNotLoaded is returned by the wallet requests received before
+	// a wallet is loaded
+	ErrWalletNotLoaded     = errors.New("wallet is not loaded yet")
 	errWalletAlreadyLoaded = errors.New("wallet is already loaded")
 )
 
@@ -108,7 +110,7 @@ func (t *Transactor) handleCreateWallet(r rpcbus.Request) error {
 
 func (t *Transactor) handleAddress(r rpcbus.Request) error {
 	if t.w == nil {
-		return errWalletNotLoaded
+		return ErrWalletNotLoaded
 	}
 
 	addr, err := t.w.PublicAddress()
@@ -123,7 +125,7 @@ func (t *Transactor) handleAddress(r rpcbus.Request) error {
 
 func (t *Transactor) handleGetTxHistory(r rpcbus.Request) error {
 	if t.w == nil {
-		return errWalletNotLoaded
+		return ErrWalletNotLoaded
 	}
 
 	records, err := t.w.FetchTxHistory()
@@ -195,7 +197,7 @@ func (t *Transactor) handleCreateFromSeed(r rpcbus.Request) error {
 
 func (t *Transactor) handleSendBidTx(r rpcbus.Request) error {
 	if t.w == nil {
-		return errWalletNotLoaded
+		return ErrWalletNotLoaded
 	}
 
 	req := r.Params.(*node.ConsensusTxRequest)
@@ -225,7 +227,7 @@ func (t *Transactor) handleSendBidTx(r rpcbus.Request) error {
 func (t *Transactor) handleSendStakeTx(r rpcbus.Request) error {
 
 	if t.w == nil {
-		return errWalletNotLoaded
+		return ErrWalletNotLoaded
 	}
 
 	req := r.Params.(*node.ConsensusTxRequest)
@@ -251,7 +253,7 @@ func (t *Transactor) handleSendStakeTx(r rpcbus.Request) error {
 func (t *Transactor) handleSendStandardTx(r rpcbus.Request) error {
 
 	if t.w == nil {
-		return errWalletNotLoaded
+		return ErrWalletNotLoaded
 	}
 
 	req := r.Params.(*node.TransferRequest)
@@ -277,7 +279,7 @@ func (t *Transactor) handleSendStandardTx(r rpcbus.Request) error {
 func (t *Transactor) handleBalance(r rpcbus.Request) error {
 
 	if t.w == nil {
-		return errWalletNotLoaded
+		return ErrWalletNotLoaded
 	}
 
 	unlockedBalance, lockedBalance, err := t.Balance()
@@ -293,7 +295,7 @@ func (t *Transactor) handleBalance(r rpcbus.Request) error {
 
 func (t *Transactor) handleUnconfirmedBalance(r rpcbus.Request) error {
 	if t.w == nil {
-		return errWalletNotLoaded
+		return ErrWalletNotLoaded
 	}
 
 	// Retrieve mempool txs
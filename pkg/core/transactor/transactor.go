@@ -154,7 +154,7 @@ func (t *Transactor) registerMethods() error {
 
 func (t *Transactor) Wallet() (*wallet.Wallet, error) {
 	if t.w == nil {
-		return nil, errWalletNotLoaded
+		return nil, ErrWalletNotLoaded
 	}
 
 	return t.w, nil
@@ -162,7 +162,7 @@ func (t *Transactor) Wallet() (*wallet.Wallet, error) {
 
 func (t *Transactor) launchMaintainer() error {
 	if t.w == nil {
-		return errWalletNotLoaded
+		return ErrWalletNotLoaded
 	}
 
 	if t.maintainerStarted {
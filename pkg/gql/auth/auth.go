@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+)
+
+// Scopes of the API keys
+const (
+	// ScopeChain grants reading the chain, mempool and node data
+	ScopeChain Scope = "chain"
+	// ScopeMempool grants submitting transactions
+	ScopeMempool Scope = "mempool"
+	// ScopeWallet grants sending wallet transactions
+	ScopeWallet Scope = "wallet"
+)
+
+// rpcIdentity is the name of the identity of the basic auth credentials of
+// the RPC service
+const rpcIdentity = "rpc"
+
+var (
+	// ErrKeyRequired is returned for requests without an API key, when keys
+	// are required
+	ErrKeyRequired = errors.New("API key required")
+	// ErrInvalidKey is returned for requests with unknown credentials
+	ErrInvalidKey = errors.New("invalid API key")
+)
+
+type (
+	// Scope is a set of operations an API key is allowed to do
+	Scope string
+
+	// Scopes is a set of scopes
+	Scopes map[Scope]bool
+
+	// Identity is the result of the authentication of a request
+	Identity struct {
+		// Name is the name of the API key. It is empty for the anonymous
+		// requests
+		Name   string
+		Scopes Scopes
+		// MaxRequestLimit is the maximum requests per second of the API key.
+		// 0 falls back to the limit of the server
+		MaxRequestLimit uint
+	}
+
+	// Authenticator authenticates the requests with their API key, or with
+	// the basic auth credentials of the RPC service
+	Authenticator struct {
+		keys       []key
+		requireKey bool
+		anonymous  Scopes
+
+		user, pass string
+	}
+
+	key struct {
+		Identity
+		secret string
+	}
+)
+
+// NewScopes creates a set of scopes
+func NewScopes(scopes ...Scope) Scopes {
+	s := make(Scopes, len(scopes))
+	for _, scope := range scopes {
+		s[scope] = true
+	}
+
+	return s
+}
+
+// AllScopes returns the set of all scopes
+func AllScopes() Scopes {
+	return NewScopes(ScopeChain, ScopeMempool, ScopeWallet)
+}
+
+// ParseScopes parses the scopes of the configuration
+func ParseScopes(names []string) (Scopes, error) {
+	s := make(Scopes, len(names))
+	for _, name := range names {
+		scope := Scope(strings.ToLower(strings.TrimSpace(name)))
+		if !AllScopes()[scope] {
+			return nil, fmt.Errorf("unknown scope %q", name)
+		}
+
+		s[scope] = true
+	}
+
+	return s, nil
+}
+
+// Has checks whether a scope is in the set
+func (s Scopes) Has(scope Scope) bool {
+	return s[scope]
+}
+
+// NewAuthenticator creates the Authenticator of the configured API keys
+func NewAuthenticator() (*Authenticator, error) {
+	conf := cfg.Get().Gql.Auth
+	rpc := cfg.Get().RPC
+
+	a := &Authenticator{
+		requireKey: conf.RequireKey,
+		anonymous:  NewScopes(ScopeChain, ScopeMempool),
+		user:       rpc.User,
+		pass:       rpc.Pass,
+	}
+
+	if len(conf.AnonymousScopes) > 0 {
+		scopes, err := ParseScopes(conf.AnonymousScopes)
+		if err != nil {
+			return nil, err
+		}
+		a.anonymous = scopes
+	}
+
+	names := make(map[string]bool)
+	for _, k := range conf.Keys {
+		if len(k.Name) == 0 || len(k.Key) == 0 {
+			return nil, errors.New("API keys need a name and a key")
+		}
+
+		if names[k.Name] || k.Name == rpcIdentity {
+			return nil, fmt.Errorf("duplicated API key name %q", k.Name)
+		}
+		names[k.Name] = true
+
+		scopes, err := ParseScopes(k.Scopes)
+		if err != nil {
+			return nil, fmt.Errorf("API key %q: %v", k.Name, err)
+		}
+
+		a.keys = append(a.keys, key{
+			Identity: Identity{Name: k.Name, Scopes: scopes, MaxRequestLimit: k.MaxRequestLimit},
+			secret:   k.Key,
+		})
+	}
+
+	return a, nil
+}
+
+// Identities returns the identities of the configured API keys
+func (a *Authenticator) Identities() []Identity {
+	identities := make([]Identity, len(a.keys))
+	for i, k := range a.keys {
+		identities[i] = k.Identity
+	}
+
+	return identities
+}
+
+// Authenticate returns the identity of a request. The API key is read from
+// the Authorization bearer token, the X-API-Key header or the apikey URL
+// parameter, the latter being the only one available to browser websockets
+func (a *Authenticator) Authenticate(r *http.Request) (Identity, error) {
+	if secret := apiKey(r); len(secret) > 0 {
+		for _, k := range a.keys {
+			if subtle.ConstantTimeCompare([]byte(secret), []byte(k.secret)) == 1 {
+				return k.Identity, nil
+			}
+		}
+
+		return Identity{}, ErrInvalidKey
+	}
+
+	// The wallet RPC credentials grant all scopes, and never if no
+	// credentials are configured
+	if user, pass, ok := r.BasicAuth(); ok {
+		if len(a.user) == 0 || len(a.pass) == 0 {
+			return Identity{}, ErrInvalidKey
+		}
+
+		userOK := subtle.ConstantTimeCompare([]byte(user), []byte(a.user)) == 1
+		passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(a.pass)) == 1
+		if !userOK || !passOK {
+			return Identity{}, ErrInvalidKey
+		}
+
+		return Identity{Name: rpcIdentity, Scopes: AllScopes()}, nil
+	}
+
+	if a.requireKey {
+		return Identity{}, ErrKeyRequired
+	}
+
+	return Identity{Scopes: a.anonymous}, nil
+}
+
+func apiKey(r *http.Request) string {
+	if bearer := r.Header.Get("Authorization"); strings.HasPrefix(bearer, "Bearer ") {
+		return strings.TrimPrefix(bearer, "Bearer ")
+	}
+
+	if key := r.Header.Get("X-API-Key"); len(key) > 0 {
+		return key
+	}
+
+	return r.URL.Query().Get("apikey")
+}
+
+// WithIdentity stores the identity of a request in a context
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, "identity", id)
+}
+
+// FromContext returns the identity stored in a context. A context without
+// identity has no scopes
+func FromContext(ctx context.Context) Identity {
+	id, _ := ctx.Value("identity").(Identity)
+	return id
+}
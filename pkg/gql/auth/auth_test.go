@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockAuthenticator(t *testing.T, requireKey bool) *Authenticator {
+	r := config.Registry{}
+	r.RPC.User = "user"
+	r.RPC.Pass = "pass"
+	r.Gql.Auth.RequireKey = requireKey
+	config.Mock(&r)
+
+	a, err := NewAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.keys = append(a.keys, key{
+		Identity: Identity{Name: "explorer", Scopes: NewScopes(ScopeChain), MaxRequestLimit: 50},
+		secret:   "secret",
+	})
+	return a
+}
+
+func TestAuthenticate(t *testing.T) {
+	a := mockAuthenticator(t, false)
+
+	// API key in the headers or in the URL
+	bearer := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	bearer.Header.Set("Authorization", "Bearer secret")
+	header := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	header.Header.Set("X-API-Key", "secret")
+	param := httptest.NewRequest(http.MethodGet, "/ws?apikey=secret", nil)
+
+	for _, r := range []*http.Request{bearer, header, param} {
+		id, err := a.Authenticate(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "explorer", id.Name)
+		assert.Equal(t, uint(50), id.MaxRequestLimit)
+		assert.True(t, id.Scopes.Has(ScopeChain))
+		assert.False(t, id.Scopes.Has(ScopeMempool))
+	}
+
+	// Unknown keys are rejected
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	r.Header.Set("Authorization", "Bearer unknown")
+	_, err := a.Authenticate(r)
+	assert.Equal(t, ErrInvalidKey, err)
+
+	// The RPC credentials grant all scopes
+	r = httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	r.SetBasicAuth("user", "pass")
+	id, err := a.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, AllScopes(), id.Scopes)
+
+	r.SetBasicAuth("user", "wrong")
+	_, err = a.Authenticate(r)
+	assert.Equal(t, ErrInvalidKey, err)
+
+	// Anonymous requests get the default scopes
+	id, err = a.Authenticate(httptest.NewRequest(http.MethodPost, "/graphql", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, NewScopes(ScopeChain, ScopeMempool), id.Scopes)
+
+	a = mockAuthenticator(t, true)
+	_, err = a.Authenticate(httptest.NewRequest(http.MethodPost, "/graphql", nil))
+	assert.Equal(t, ErrKeyRequired, err)
+}
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes([]string{"chain", " Wallet"})
+	assert.NoError(t, err)
+	assert.Equal(t, NewScopes(ScopeChain, ScopeWallet), scopes)
+
+	_, err = ParseScopes([]string{"admin"})
+	assert.Error(t, err)
+}
+
+func TestOrigins(t *testing.T) {
+	o := NewOrigins([]string{"https://explorer.dusk.network/"})
+	handler := o.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(method, origin string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "http://node:9001/graphql", nil)
+		if len(origin) > 0 {
+			r.Header.Set("Origin", origin)
+		}
+		if method == http.MethodOptions {
+			r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(http.MethodPost, "https://explorer.dusk.network")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://explorer.dusk.network", w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = serve(http.MethodOptions, "https://explorer.dusk.network")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "https://evil.com").Code)
+
+	// Same-origin and non-browser requests
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "http://node:9001").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "").Code)
+
+	handler = NewOrigins([]string{"*"}).CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "https://evil.com").Code)
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Origins is the policy of the cross-origin requests
+type Origins struct {
+	all     bool
+	allowed map[string]bool
+}
+
+// NewOrigins creates the policy allowing the given origins. "*" allows all
+// origins
+func NewOrigins(origins []string) Origins {
+	o := Origins{allowed: make(map[string]bool)}
+	for _, origin := range origins {
+		if origin == "*" {
+			o.all = true
+			continue
+		}
+
+		o.allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+
+	return o
+}
+
+// Allow checks the origin of a request. Requests without an origin do not come
+// from browsers, and same-origin requests are always allowed
+func (o Origins) Allow(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 || o.all {
+		return true
+	}
+
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	return o.allowed[strings.ToLower(origin)]
+}
+
+// CORS sets the CORS headers of the allowed cross-origin requests, and
+// answers their preflight requests
+func (o Origins) CORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if o.Check(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// Check applies the policy to a request, as CORS does. It returns false if the
+// request is answered already
+func (o Origins) Check(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if len(origin) > 0 {
+		if !o.Allow(r) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return false
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	}
+
+	if r.Method == http.MethodOptions && len(r.Header.Get("Access-Control-Request-Method")) > 0 {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-API-Key")
+		w.WriteHeader(http.StatusNoContent)
+		return false
+	}
+
+	return true
+}
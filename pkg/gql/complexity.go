@@ -0,0 +1,230 @@
+package gql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// defaultListSize is the assumed size of a list field, when it cannot be
+	// told from the arguments of the field
+	defaultListSize = 20
+	// maxCost caps the complexity, so that huge ranges do not overflow it
+	maxCost = math.MaxInt32
+)
+
+// queryCost is the result of the static analysis of a query
+type queryCost struct {
+	// Depth is the maximum nesting of the fields
+	Depth int
+	// Complexity is the amount of fields resolved. Each field costs 1 for
+	// every item it is resolved for
+	Complexity int
+}
+
+// costAnalyzer walks through the selections of an operation, following the
+// types of the schema to find out which fields are lists
+type costAnalyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// analyzeQuery computes the cost of the operation of a validated document.
+// The size of the lists is taken from the arguments of the fields, and assumed
+// to be defaultListSize if missing
+func analyzeQuery(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) (queryCost, error) {
+	a := costAnalyzer{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+
+	for _, def := range doc.Definitions {
+		if d, ok := def.(*ast.FragmentDefinition); ok {
+			a.fragments[d.Name.Value] = d
+		}
+	}
+
+	op := operation(doc, operationName)
+	if op == nil {
+		return queryCost{}, fmt.Errorf("unknown operation %q", operationName)
+	}
+
+	var root *graphql.Object
+	switch op.Operation {
+	case ast.OperationTypeQuery:
+		root = schema.QueryType()
+	case ast.OperationTypeMutation:
+		root = schema.MutationType()
+	case ast.OperationTypeSubscription:
+		root = schema.SubscriptionType()
+	}
+
+	if root == nil {
+		return queryCost{}, fmt.Errorf("%s operations are not supported", op.Operation)
+	}
+
+	return a.selectionSet(root, op.SelectionSet, 0, nil), nil
+}
+
+// selectionSet computes the cost of a selection set on a type, resolved once.
+// The page size is the size of the next list down the selections, if set by
+// the arguments of a field which is not a list, as with the connections
+func (a costAnalyzer) selectionSet(parent graphql.Type, set *ast.SelectionSet, pageSize int, visited map[string]bool) queryCost {
+	var cost queryCost
+	if set == nil {
+		return cost
+	}
+
+	for _, selection := range set.Selections {
+		var c queryCost
+		switch s := selection.(type) {
+		case *ast.Field:
+			c = a.field(parent, s, pageSize, visited)
+		case *ast.InlineFragment:
+			c = a.selectionSet(parent, s.SelectionSet, pageSize, visited)
+		case *ast.FragmentSpread:
+			// validation rejects fragment cycles, this is only a safeguard
+			name := s.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || visited[name] {
+				continue
+			}
+
+			v := map[string]bool{name: true}
+			for k := range visited {
+				v[k] = true
+			}
+			c = a.selectionSet(parent, fragment.SelectionSet, pageSize, v)
+		}
+
+		cost.Complexity = saturate(int64(cost.Complexity) + int64(c.Complexity))
+		if c.Depth > cost.Depth {
+			cost.Depth = c.Depth
+		}
+	}
+
+	return cost
+}
+
+func (a costAnalyzer) field(parent graphql.Type, f *ast.Field, pageSize int, visited map[string]bool) queryCost {
+	// the introspection is not resolved against the node
+	if strings.HasPrefix(f.Name.Value, "__") {
+		return queryCost{}
+	}
+
+	object, ok := parent.(*graphql.Object)
+	if !ok {
+		return queryCost{Depth: 1, Complexity: 1}
+	}
+
+	def, ok := object.Fields()[f.Name.Value]
+	if !ok {
+		return queryCost{Depth: 1, Complexity: 1}
+	}
+
+	size, hasSize := a.listSize(f)
+	fieldType := def.Type
+	if nonNull, ok := fieldType.(*graphql.NonNull); ok {
+		fieldType = nonNull.OfType
+	}
+
+	multiplier := 1
+	if _, isList := fieldType.(*graphql.List); isList {
+		switch {
+		case hasSize:
+			multiplier = size
+		case pageSize > 0:
+			multiplier = pageSize
+		default:
+			multiplier = defaultListSize
+		}
+		pageSize = 0
+	} else if hasSize {
+		pageSize = size
+	}
+
+	c := a.selectionSet(graphql.GetNamed(def.Type), f.SelectionSet, pageSize, visited)
+	return queryCost{
+		Depth:      c.Depth + 1,
+		Complexity: saturate(int64(multiplier) * int64(1+c.Complexity)),
+	}
+}
+
+// listSize reads the size of the list resolved by a field from its arguments
+func (a costAnalyzer) listSize(f *ast.Field) (int, bool) {
+	for _, arg := range f.Arguments {
+		switch arg.Name.Value {
+		case "first", "last":
+			if n, ok := toInt(a.value(arg.Value)); ok && n > 0 {
+				return n, true
+			}
+		case "range":
+			bounds, ok := a.value(arg.Value).([]interface{})
+			if !ok || len(bounds) != 2 {
+				continue
+			}
+
+			from, okFrom := toInt(bounds[0])
+			to, okTo := toInt(bounds[1])
+			if okFrom && okTo {
+				if from > to {
+					from, to = to, from
+				}
+				return to - from + 1, true
+			}
+		case "height", "hash", "txid":
+			// lists of a single item
+			return 1, true
+		case "hashes", "txids":
+			if items, ok := a.value(arg.Value).([]interface{}); ok && len(items) > 0 {
+				return len(items), true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// value returns the value of an argument, looking up the variables
+func (a costAnalyzer) value(v ast.Value) interface{} {
+	switch v := v.(type) {
+	case *ast.Variable:
+		return a.variables[v.Name.Value]
+	case *ast.ListValue:
+		values := make([]interface{}, len(v.Values))
+		for i, item := range v.Values {
+			values[i] = a.value(item)
+		}
+		return values
+	default:
+		return v.GetValue()
+	}
+}
+
+func saturate(cost int64) int {
+	if cost > maxCost {
+		return maxCost
+	}
+
+	return int(cost)
+}
+
+func toInt(v interface{}) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case float64:
+		// numbers of the JSON variables
+		return int(v), true
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+
+	return 0, false
+}
@@ -0,0 +1,84 @@
+package gql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/query"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeQuery(t *testing.T) {
+	root := query.NewRoot(nil)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: root.Query})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query     string
+		variables map[string]interface{}
+		cost      queryCost
+	}{
+		// the size of the transactions of a block is not known
+		{`{ blocks(last: 10) { header { height } transactions { txid } } }`, nil, queryCost{Depth: 3, Complexity: 430}},
+		// the page size of a connection applies to its edges
+		{`{ blocksConnection(first: 5) { edges { node { header { height } } } totalCount } }`, nil, queryCost{Depth: 5, Complexity: 22}},
+		{`query($n: Int) { blocks(last: $n) { header { height } } }`, map[string]interface{}{"n": float64(100)}, queryCost{Depth: 3, Complexity: 300}},
+		{`{ blocks(range: [0, 99]) { ...h } } fragment h on Block { header { height } }`, nil, queryCost{Depth: 3, Complexity: 300}},
+		{`{ __schema { types { name fields { name } } } }`, nil, queryCost{}},
+	}
+
+	for _, tt := range tests {
+		doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		cost, err := analyzeQuery(&schema, doc, "", tt.variables)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.cost, cost, tt.query)
+	}
+}
+
+func TestPersistedQueries(t *testing.T) {
+	q := `{ blocks(last: 1) { header { height } } }`
+	hash := hashQuery(q)
+
+	_, err := newPersistedQueries(map[string]string{hash: "{ mempool { txid } }"}, false)
+	assert.Error(t, err)
+
+	p, err := newPersistedQueries(map[string]string{hash: q}, true)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	persisted, err := p.lookup("", extensions{PersistedQuery: &persistedQuery{Version: 1, Sha256Hash: hash}})
+	assert.NoError(t, err)
+	assert.Equal(t, q, persisted)
+
+	_, err = p.lookup("", extensions{PersistedQuery: &persistedQuery{Version: 1, Sha256Hash: hashQuery("{}")}})
+	assert.Equal(t, errPersistedQueryNotFound, err)
+
+	// Only persisted queries can be sent along
+	persisted, err = p.lookup(q, extensions{})
+	assert.NoError(t, err)
+	assert.Equal(t, q, persisted)
+
+	_, err = p.lookup("{ mempool { txid } }", extensions{})
+	assert.Equal(t, errQueryNotPersisted, err)
+}
+
+func TestDeadlineDB(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	db := deadlineDB{ctx: ctx}
+	err := db.View(func(t database.Transaction) error {
+		return nil
+	})
+	assert.Equal(t, errQueryTimeout, err)
+}
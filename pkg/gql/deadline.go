@@ -0,0 +1,85 @@
+package gql
+
+import (
+	"context"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-wallet/v2/block"
+	"github.com/dusk-network/dusk-wallet/v2/transactions"
+)
+
+type (
+	// deadlineDB binds the DB views of a query to the context of the query,
+	// so that the resolvers scanning the chain give up once the query timed
+	// out, instead of keeping the DB busy for a discarded result
+	deadlineDB struct {
+		database.DB
+		ctx context.Context
+	}
+
+	// deadlineTransaction checks the context of the query before each fetch
+	// of chain data
+	deadlineTransaction struct {
+		database.Transaction
+		ctx context.Context
+	}
+)
+
+func (d deadlineDB) View(fn func(t database.Transaction) error) error {
+	if err := checkDeadline(d.ctx); err != nil {
+		return err
+	}
+
+	return d.DB.View(func(t database.Transaction) error {
+		return fn(deadlineTransaction{Transaction: t, ctx: d.ctx})
+	})
+}
+
+// checkDeadline returns errQueryTimeout once the query is done
+func checkDeadline(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errQueryTimeout
+	}
+
+	return nil
+}
+
+func (t deadlineTransaction) FetchBlockHeader(hash []byte) (*block.Header, error) {
+	if err := checkDeadline(t.ctx); err != nil {
+		return nil, err
+	}
+
+	return t.Transaction.FetchBlockHeader(hash)
+}
+
+func (t deadlineTransaction) FetchBlockTxs(hash []byte) ([]transactions.Transaction, error) {
+	if err := checkDeadline(t.ctx); err != nil {
+		return nil, err
+	}
+
+	return t.Transaction.FetchBlockTxs(hash)
+}
+
+func (t deadlineTransaction) FetchBlockTxByHash(txID []byte) (transactions.Transaction, uint32, []byte, error) {
+	if err := checkDeadline(t.ctx); err != nil {
+		return nil, 0, nil, err
+	}
+
+	return t.Transaction.FetchBlockTxByHash(txID)
+}
+
+func (t deadlineTransaction) FetchBlockHashByHeight(height uint64) ([]byte, error) {
+	if err := checkDeadline(t.ctx); err != nil {
+		return nil, err
+	}
+
+	return t.Transaction.FetchBlockHashByHeight(height)
+}
+
+func (t deadlineTransaction) FetchBlock(hash []byte) (*block.Block, error) {
+	if err := checkDeadline(t.ctx); err != nil {
+		return nil, err
+	}
+
+	return t.Transaction.FetchBlock(hash)
+}
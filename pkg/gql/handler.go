@@ -3,23 +3,89 @@ package gql
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/auth"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/query"
 	"github.com/go-chi/render"
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
 )
 
 type data struct {
-	Query     string                 `json:"query"`
-	Operation string                 `json:"operationName,omitempty"`
-	Variables map[string]interface{} `json:"variables,omitempty"`
+	Query      string                 `json:"query"`
+	Operation  string                 `json:"operationName,omitempty"`
+	Variables  map[string]interface{} `json:"variables,omitempty"`
+	Extensions extensions             `json:"extensions,omitempty"`
+}
+
+var (
+	errQueryTimeout = errors.New("query timed out")
+	errForbidden    = errors.New("the API key is not allowed to read the chain")
+)
+
+// queryLimits protects the node against the queries too expensive to resolve.
+// Zero values disable the limits
+type queryLimits struct {
+	maxDepth      int
+	maxComplexity int
+	timeout       time.Duration
+	// persisted is nil if persisted queries are disabled
+	persisted *persistedQueries
+}
+
+// newQueryLimits reads the limits of the queries from the configuration
+func newQueryLimits() (queryLimits, error) {
+	conf := cfg.Get().Gql
+	l := queryLimits{
+		maxDepth:      int(conf.MaxQueryDepth),
+		maxComplexity: int(conf.MaxQueryComplexity),
+		timeout:       time.Duration(conf.QueryTimeout) * time.Second,
+	}
+
+	if len(conf.PersistedQueries) > 0 {
+		p, err := loadPersistedQueries(conf.PersistedQueries, conf.OnlyPersistedQueries)
+		if err != nil {
+			return l, err
+		}
+		l.persisted = p
+	}
+
+	return l, nil
+}
+
+// check runs the static cost analysis of a validated query against the limits
+func (l queryLimits) check(schema *graphql.Schema, doc *ast.Document, operation string, variables map[string]interface{}) error {
+	if l.maxDepth == 0 && l.maxComplexity == 0 {
+		return nil
+	}
+
+	cost, err := analyzeQuery(schema, doc, operation, variables)
+	if err != nil {
+		return err
+	}
+
+	if l.maxDepth > 0 && cost.Depth > l.maxDepth {
+		return fmt.Errorf("query depth %d exceeds the maximum of %d", cost.Depth, l.maxDepth)
+	}
+
+	if l.maxComplexity > 0 && cost.Complexity > l.maxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the maximum of %d", cost.Complexity, l.maxComplexity)
+	}
+
+	return nil
 }
 
 // handleQuery to process graphQL query
-func handleQuery(schema *graphql.Schema, w http.ResponseWriter, r *http.Request, db database.DB) {
+func handleQuery(schema *graphql.Schema, limits queryLimits, w http.ResponseWriter, r *http.Request, db database.DB, cache *query.BlockCache, id auth.Identity) {
 
 	if r.Body == nil {
 		http.Error(w, "Must provide graphql query in request body", 400)
@@ -48,14 +114,17 @@ func handleQuery(schema *graphql.Schema, w http.ResponseWriter, r *http.Request,
 	log.Tracef("Variables: %s", req.Variables)
 	log.Tracef("Operation: %s", req.Operation)
 
+	// Resolve the persisted queries
+	if req.Query, err = limits.persisted.lookup(req.Query, req.Extensions); err != nil {
+		render.JSON(w, r, errorResult(err))
+		return
+	}
+
+	ctx, cancel := queryContext(limits, db, cache, id)
+	defer cancel()
+
 	// Execute graphql query
-	result := graphql.Do(graphql.Params{
-		Schema:         *schema,
-		RequestString:  req.Query,
-		VariableValues: req.Variables,
-		OperationName:  req.Operation,
-		Context:        context.WithValue(context.Background(), "database", db),
-	})
+	result := executeQuery(ctx, schema, limits, req)
 
 	// Error check
 	if len(result.Errors) > 0 {
@@ -64,3 +133,93 @@ func handleQuery(schema *graphql.Schema, w http.ResponseWriter, r *http.Request,
 
 	render.JSON(w, r, result)
 }
+
+// queryContext creates the context of the resolvers of a request. The DB
+// views of the resolvers give up once the query timed out
+func queryContext(limits queryLimits, db database.DB, cache *query.BlockCache, id auth.Identity) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if limits.timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), limits.timeout)
+	}
+
+	ctx = auth.WithIdentity(ctx, id)
+	ddb := deadlineDB{DB: db, ctx: ctx}
+	ctx = context.WithValue(ctx, "database", ddb)
+	// The chain lookups of the resolvers are batched over the request
+	ctx = context.WithValue(ctx, "loader", query.NewLoader(ddb, cache))
+	return ctx, cancel
+}
+
+// executeQuery parses, validates and checks the query against the limits
+// before executing it. The execution is abandoned once the context is done
+func executeQuery(ctx context.Context, schema *graphql.Schema, limits queryLimits, req data) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return errorResult(err)
+	}
+
+	if result := graphql.ValidateDocument(schema, doc, nil); !result.IsValid {
+		return &graphql.Result{Errors: result.Errors}
+	}
+
+	if err := authorize(ctx, doc, req.Operation); err != nil {
+		return errorResult(err)
+	}
+
+	if err := limits.check(schema, doc, req.Operation, req.Variables); err != nil {
+		return errorResult(err)
+	}
+
+	resultChan := make(chan *graphql.Result, 1)
+	go func() {
+		resultChan <- graphql.Execute(graphql.ExecuteParams{
+			Schema:        *schema,
+			AST:           doc,
+			OperationName: req.Operation,
+			Args:          req.Variables,
+			Context:       ctx,
+		})
+	}()
+
+	select {
+	case result := <-resultChan:
+		return result
+	case <-ctx.Done():
+		return errorResult(errQueryTimeout)
+	}
+}
+
+func errorResult(err error) *graphql.Result {
+	return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+}
+
+// authorize checks the scopes of a request against its operation. Reading
+// the chain needs the chain scope, while the mutations check the scopes they
+// need themselves
+func authorize(ctx context.Context, doc *ast.Document, operationName string) error {
+	op := operation(doc, operationName)
+	if op == nil || op.Operation == ast.OperationTypeMutation {
+		return nil
+	}
+
+	if !auth.FromContext(ctx).Scopes.Has(auth.ScopeChain) {
+		return errForbidden
+	}
+
+	return nil
+}
+
+// operation returns the operation of a document with the given name, or the
+// last one if no name is given
+func operation(doc *ast.Document, operationName string) *ast.OperationDefinition {
+	var op *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		if d, ok := def.(*ast.OperationDefinition); ok {
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				op = d
+			}
+		}
+	}
+
+	return op
+}
@@ -1,16 +1,24 @@
 package gql
 
 import (
+	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/didip/tollbooth"
+	tberrors "github.com/didip/tollbooth/errors"
 	"github.com/didip/tollbooth/limiter"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/auth"
 	"github.com/dusk-network/dusk-blockchain/pkg/gql/notifications"
 	"github.com/dusk-network/dusk-blockchain/pkg/gql/query"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/gorilla/websocket"
@@ -26,20 +34,87 @@ var log *logger.Entry = logger.WithFields(logger.Fields{"prefix": "gql"})
 const (
 	endpointWS  = "/ws"
 	endpointGQL = "/graphql"
+
+	// blockCacheSize is the amount of recently accepted blocks kept in memory
+	blockCacheSize = 256
+
+	// shutdownTimeout is the time given to the in-flight queries and to the
+	// websocket clients to terminate on Stop
+	shutdownTimeout = 10 * time.Second
 )
 
+// settings are the parts of the server built from the #live# configuration,
+// replaced as a whole on Reload
+type settings struct {
+	limits queryLimits
+
+	// Authentication and rate limiting. The requests with an API key are
+	// limited by key, the others by remote IP
+	auth    *auth.Authenticator
+	origins auth.Origins
+	lmt     *limiter.Limiter
+	keyLmts map[string]*limiter.Limiter
+}
+
+// newSettings reads the settings from the configuration
+func newSettings() (*settings, error) {
+
+	max := float64(cfg.Get().Gql.MaxRequestLimit)
+
+	limits, err := newQueryLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator, err := auth.NewAuthenticator()
+	if err != nil {
+		return nil, err
+	}
+
+	st := &settings{
+		limits:  limits,
+		auth:    authenticator,
+		origins: auth.NewOrigins(cfg.Get().Gql.AllowedOrigins),
+		lmt:     tollbooth.NewLimiter(max, nil),
+		keyLmts: make(map[string]*limiter.Limiter),
+	}
+
+	for _, id := range authenticator.Identities() {
+		keyMax := max
+		if id.MaxRequestLimit > 0 {
+			keyMax = float64(id.MaxRequestLimit)
+		}
+		st.keyLmts[id.Name] = tollbooth.NewLimiter(keyMax, nil)
+	}
+
+	return st, nil
+}
+
 // Server defines the HTTP server of the GraphQL service node.
 type Server struct {
-	started bool // Indicates whether or not server has started
+	started int32 // Indicates whether or not server has started
+
+	listener   net.Listener
+	httpServer *http.Server
+	// reloadID is the subscription to the reloads of the configuration
+	reloadID uint32
 
-	listener net.Listener
-	lmt      *limiter.Limiter
+	// lock guards the parts of the server replaced on Reload
+	lock     sync.RWMutex
+	settings *settings
+	// cert is the TLS certificate, nil if TLS is disabled
+	cert *tls.Certificate
 
 	// Graphql utility
 	schema *graphql.Schema
+	cache  *query.BlockCache
+	// cacheID is the subscription of the cache to the accepted blocks
+	cacheID uint32
 
-	// Websocket connections pool
-	pool *notifications.BrokerPool
+	// Websocket connections pool. It is nil if notifications are disabled
+	pool       *notifications.BrokerPool
+	brokersNum uint
+	poolOpts   notifications.Options
 
 	// Node components
 	eventBus *eventbus.EventBus
@@ -50,12 +125,15 @@ type Server struct {
 // NewHTTPServer instantiates a new NewHTTPServer to handle GraphQL queries.
 func NewHTTPServer(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus) (*Server, error) {
 
-	max := float64(cfg.Get().Gql.MaxRequestLimit)
+	st, err := newSettings()
+	if err != nil {
+		return nil, err
+	}
 
 	srv := Server{
 		eventBus: eventBus,
 		rpcBus:   rpcBus,
-		lmt:      tollbooth.NewLimiter(max, nil),
+		settings: st,
 	}
 
 	return &srv, nil
@@ -64,20 +142,30 @@ func NewHTTPServer(eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCBus) (*Server,
 // Start the GraphQL HTTP Server and begin listening on specified port.
 func (s *Server) Start() error {
 	mux := http.NewServeMux()
-	httpServer := &http.Server{
+	s.httpServer = &http.Server{
 		Handler:     mux,
 		ReadTimeout: time.Second * 10,
 	}
 
 	conf := cfg.Get().Gql
+	if conf.EnableTLS {
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return err
+		}
+
+		// The certificate is looked up on each handshake, so that it can be
+		// renewed on Reload
+		s.cert = &cert
+		s.httpServer.TLSConfig = &tls.Config{GetCertificate: s.certificate}
+	}
+
 	if err := s.EnableGraphQL(mux); err != nil {
 		return err
 	}
 
-	if conf.Notification.BrokersNum > 0 {
-		if err := s.EnableNotifications(mux); err != nil {
-			return err
-		}
+	if err := s.EnableNotifications(mux); err != nil {
+		return err
 	}
 
 	// Set up HTTP Server over TCP
@@ -87,25 +175,27 @@ func (s *Server) Start() error {
 	}
 
 	s.listener = l
-	go s.listenOnHTTPServer(httpServer)
+	s.reloadID = s.eventBus.Subscribe(topics.ConfigReloaded, eventbus.NewCallbackListener(s.onConfigReloaded))
+	go s.listenOnHTTPServer(s.httpServer, conf.EnableTLS)
 
-	s.started = true
+	atomic.StoreInt32(&s.started, 1)
 
 	return nil
 }
 
 // Listen on the http server.
-func (s *Server) listenOnHTTPServer(httpServer *http.Server) {
+func (s *Server) listenOnHTTPServer(httpServer *http.Server, enableTLS bool) {
 
 	conf := cfg.Get().Gql
 
 	log.WithField("net", conf.Network).
 		WithField("addr", conf.Address).
-		WithField("tls", conf.EnableTLS).Infof("GraphQL HTTP server listening")
+		WithField("tls", enableTLS).Infof("GraphQL HTTP server listening")
 
 	var err error
-	if conf.EnableTLS {
-		err = httpServer.ServeTLS(s.listener, conf.CertFile, conf.KeyFile)
+	if enableTLS {
+		// The certificate is served by the TLS config
+		err = httpServer.ServeTLS(s.listener, "", "")
 	} else {
 		err = httpServer.Serve(s.listener)
 	}
@@ -117,12 +207,30 @@ func (s *Server) listenOnHTTPServer(httpServer *http.Server) {
 	}
 }
 
+func (s *Server) certificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.cert, nil
+}
+
+// isStarted tells whether the server is serving the requests
+func (s *Server) isStarted() bool {
+	return atomic.LoadInt32(&s.started) > 0
+}
+
+// current returns the settings to serve a request with
+func (s *Server) current() *settings {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.settings
+}
+
 func (s *Server) EnableGraphQL(serverMux *http.ServeMux) error {
 
 	// GraphQL service
-	gqlHandler := func(w http.ResponseWriter, r *http.Request) {
+	gqlHandler := func(w http.ResponseWriter, r *http.Request, st *settings, id auth.Identity) {
 
-		if !s.started {
+		if !s.isStarted() {
 			return
 		}
 
@@ -130,15 +238,30 @@ func (s *Server) EnableGraphQL(serverMux *http.ServeMux) error {
 		w.Header().Set("Content-Type", "application/json")
 		r.Close = true
 
-		handleQuery(s.schema, w, r, s.db)
+		handleQuery(s.schema, st.limits, w, r, s.db, s.cache, id)
 	}
 
-	middleware := tollbooth.LimitFuncHandler(s.lmt, gqlHandler)
-	serverMux.Handle(endpointGQL, middleware)
+	serverMux.Handle(endpointGQL, s.guard(gqlHandler))
+
+	// REST service, sharing the rate limits of the GraphQL service
+	restHandler := func(w http.ResponseWriter, r *http.Request, st *settings, id auth.Identity) {
+
+		if !s.isStarted() {
+			return
+		}
+
+		handleREST(s.schema, st.limits, w, r, s.db, s.cache, id)
+	}
+
+	serverMux.Handle(endpointREST, s.guard(restHandler))
 
 	//  Setup graphQL
 	rootQuery := query.NewRoot(s.rpcBus)
-	sconf := graphql.SchemaConfig{Query: rootQuery.Query}
+	sconf := graphql.SchemaConfig{
+		Query:        rootQuery.Query,
+		Mutation:     rootQuery.Mutation,
+		Subscription: rootQuery.Subscription,
+	}
 
 	sc, err := graphql.NewSchema(sconf)
 	if err != nil {
@@ -148,32 +271,53 @@ func (s *Server) EnableGraphQL(serverMux *http.ServeMux) error {
 	s.schema = &sc
 	_, s.db = heavy.CreateDBConnection()
 
+	// Cache the recently accepted blocks, as the most queried ones
+	s.cache = query.NewBlockCache(blockCacheSize)
+	s.cacheID = s.cache.Listen(s.eventBus)
+
 	return nil
 }
 
+// EnableNotifications serves the websocket clients. The brokers are only run
+// if gql.notification.brokersNum is not 0, but they can be enabled on Reload
 func (s *Server) EnableNotifications(serverMux *http.ServeMux) error {
 
-	nc := cfg.Get().Gql.Notification
-
 	upgrader := &websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
+		// Clients requesting graphql-ws are served GraphQL subscriptions
+		Subprotocols: []string{notifications.GraphQLWS},
+		CheckOrigin: func(r *http.Request) bool {
+			return s.current().origins.Allow(r)
+		},
 	}
 
-	upgrader.CheckOrigin = func(r *http.Request) bool {
-		return true
+	brokersNum, opts, err := poolOptions()
+	if err != nil {
+		return err
 	}
 
-	var clientsPerBroker uint = 100
-	if nc.ClientsPerBroker > 0 {
-		clientsPerBroker = nc.ClientsPerBroker
-	}
+	s.brokersNum, s.poolOpts = brokersNum, opts
+	s.pool = s.newPool(brokersNum, opts)
 
-	s.pool = notifications.NewPool(s.eventBus, nc.BrokersNum, clientsPerBroker)
+	wsHandler := func(w http.ResponseWriter, r *http.Request, _ *settings, id auth.Identity) {
+
+		if !s.isStarted() {
+			return
+		}
+
+		// Notifications and subscriptions are read-only
+		if !id.Scopes.Has(auth.ScopeChain) {
+			http.Error(w, errForbidden.Error(), http.StatusForbidden)
+			return
+		}
 
-	wsHandler := func(w http.ResponseWriter, r *http.Request) {
+		s.lock.RLock()
+		pool := s.pool
+		s.lock.RUnlock()
 
-		if !s.started {
+		if pool == nil {
+			http.Error(w, "notifications are disabled", http.StatusServiceUnavailable)
 			return
 		}
 
@@ -182,21 +326,196 @@ func (s *Server) EnableNotifications(serverMux *http.ServeMux) error {
 			log.Errorf("Failed to set websocket upgrade: %v", err)
 			return
 		}
-		s.pool.PushConn(conn)
+
+		// A pool replaced in the meantime asks the client to reconnect
+		pool.PushConn(conn)
 	}
 
-	middleware := tollbooth.LimitFuncHandler(s.lmt, wsHandler)
-	serverMux.Handle(endpointWS, middleware)
+	serverMux.Handle(endpointWS, s.guard(wsHandler))
 
 	return nil
 }
 
-// Stop the server
+// poolOptions reads the number of brokers and their options from the
+// configuration
+func poolOptions() (uint, notifications.Options, error) {
+	nc := cfg.Get().Gql.Notification
+
+	policy, err := notifications.ParsePolicy(nc.SlowConsumerPolicy)
+	if err != nil {
+		return 0, notifications.Options{}, err
+	}
+
+	opts := notifications.Options{
+		ClientsPerBroker: nc.ClientsPerBroker,
+		QueueSize:        nc.QueueSize,
+		SlowConsumer:     policy,
+		MaxReplay:        nc.MaxReplayBlocks,
+	}
+
+	return nc.BrokersNum, opts, nil
+}
+
+func (s *Server) newPool(brokersNum uint, opts notifications.Options) *notifications.BrokerPool {
+	if brokersNum == 0 {
+		return nil
+	}
+
+	// The missed blocks are replayed from the DB of the queries
+	return notifications.NewPool(s.eventBus, s.schema, s.db, brokersNum, opts)
+}
+
+// guard applies the origin policy, the authentication and the rate limits to
+// the requests of a handler. The handler is passed the settings the request
+// was checked with
+func (s *Server) guard(handler func(w http.ResponseWriter, r *http.Request, st *settings, id auth.Identity)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		st := s.current()
+		if !st.origins.Check(w, r) {
+			return
+		}
+
+		id, err := st.auth.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="dusk"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		var httpErr *tberrors.HTTPError
+		if lmt, ok := st.keyLmts[id.Name]; ok {
+			httpErr = tollbooth.LimitByKeys(lmt, []string{id.Name})
+		} else {
+			httpErr = tollbooth.LimitByRequest(st.lmt, w, r)
+		}
+
+		if httpErr != nil {
+			http.Error(w, httpErr.Message, httpErr.StatusCode)
+			return
+		}
+
+		handler(w, r, st, id)
+	})
+}
+
+func (s *Server) onConfigReloaded(message.Message) error {
+	if err := s.Reload(); err != nil {
+		log.WithError(err).Errorln("could not reload the GraphQL server")
+	}
+
+	return nil
+}
+
+// Reload applies the #live# configuration to the running server: the TLS
+// certificate, the query limits, the API keys, the allowed origins, the rate
+// limits and the settings of the brokers. The in-flight requests complete with the
+// previous settings. On error, the server keeps its current settings
+func (s *Server) Reload() error {
+
+	st, err := newSettings()
+	if err != nil {
+		return err
+	}
+
+	brokersNum, opts, err := poolOptions()
+	if err != nil {
+		return err
+	}
+
+	conf := cfg.Get().Gql
+
+	// TLS can not be enabled or disabled without restarting the node, but
+	// its certificate can be renewed
+	s.lock.RLock()
+	tlsEnabled := s.cert != nil
+	s.lock.RUnlock()
+
+	var cert *tls.Certificate
+	if tlsEnabled {
+		c, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return err
+		}
+		cert = &c
+	}
+
+	s.lock.Lock()
+
+	s.settings = st
+	if cert != nil {
+		s.cert = cert
+	}
+
+	// The pool is replaced if the broker counts or options changed, as the
+	// brokers share their connections chan
+	var old *notifications.BrokerPool
+	if s.schema != nil && (brokersNum != s.brokersNum || opts != s.poolOpts) {
+		old = s.pool
+		s.pool = s.newPool(brokersNum, opts)
+		s.brokersNum, s.poolOpts = brokersNum, opts
+	}
+
+	s.lock.Unlock()
+
+	// The clients of the previous pool are told to reconnect
+	if old != nil {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := old.Restart(ctx); err != nil {
+				log.WithError(err).Warnln("could not restart the notification brokers")
+			}
+		}()
+	}
+
+	log.WithField("brokers", brokersNum).Info("GraphQL server reloaded")
+	return nil
+}
+
+// Shutdown stops listening and waits for the in-flight queries to complete and
+// for the websocket clients to be sent a close frame, or for the context to be
+// done. The remaining connections are then closed
+func (s *Server) Shutdown(ctx context.Context) error {
+
+	if s.httpServer == nil {
+		return nil
+	}
+
+	s.eventBus.Unsubscribe(topics.ConfigReloaded, s.reloadID)
+	if s.cache != nil {
+		s.eventBus.Unsubscribe(topics.AcceptedBlock, s.cacheID)
+	}
+
+	// The hijacked websocket connections are left to the brokers
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		_ = s.httpServer.Close()
+	}
+
+	s.lock.Lock()
+	pool := s.pool
+	s.pool = nil
+	s.lock.Unlock()
+
+	if pool != nil {
+		if poolErr := pool.Shutdown(ctx); err == nil {
+			err = poolErr
+		}
+	}
+
+	atomic.StoreInt32(&s.started, 0)
+	return err
+}
+
+// Stop the server, giving the in-flight queries and the websocket clients
+// shutdownTimeout to terminate
 func (s *Server) Stop() error {
 
-	s.started = false
-	s.pool.Close()
-	if err := s.listener.Close(); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := s.Shutdown(ctx); err != nil {
 		log.Errorf("error shutting down, %v\n", err)
 		return err
 	}
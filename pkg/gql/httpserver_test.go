@@ -1,7 +1,9 @@
 package gql
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"net/url"
 	"testing"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestWebsocketEndpoint(t *testing.T) {
@@ -67,7 +70,14 @@ func TestWebsocketEndpoint(t *testing.T) {
 
 	t.Logf("Message size %d", len(message))
 
-	expMsg, err := notifications.MarshalBlockMsg(*blk)
+	// The first notification of the client
+	blkMsg, err := notifications.NewBlockMsg(*blk)
+	if err != nil {
+		t.Errorf("marshalling failed")
+	}
+	blkMsg.Seq = 1
+
+	expMsg, err := json.Marshal(blkMsg)
 	if err != nil {
 		t.Errorf("marshalling failed")
 	}
@@ -76,14 +86,145 @@ func TestWebsocketEndpoint(t *testing.T) {
 		t.Fatalf("no response received")
 	}
 
-	if expMsg != message {
+	if string(expMsg) != message {
 		t.Errorf("malformed message received")
 	}
 }
 
-func setupServer(t *testing.T, addr string) (*Server, *eventbus.EventBus, error) {
-	// Set up HTTP server with notifications enabled
-	// config
+func TestGraphQLSubscription(t *testing.T) {
+
+	s, eb, err := setupServer(t, "127.0.0.1:22223")
+	if err != nil {
+		t.Error(err)
+	}
+	defer s.Stop()
+
+	dialCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Set up a graphql-ws client
+	dialer := websocket.Dialer{Subprotocols: []string{notifications.GraphQLWS}}
+	u := url.URL{Scheme: "ws", Host: "127.0.0.1:22223", Path: "/ws"}
+	c, _, err := dialer.DialContext(dialCtx, u.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	type operation struct {
+		ID      string          `json:"id,omitempty"`
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload,omitempty"`
+	}
+
+	read := func() operation {
+		_ = c.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var op operation
+		if err := c.ReadJSON(&op); err != nil {
+			t.Fatal(err)
+		}
+		return op
+	}
+
+	if err := c.WriteJSON(operation{Type: "connection_init"}); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "connection_ack", read().Type)
+	assert.Equal(t, "ka", read().Type)
+
+	start := operation{
+		ID:      "1",
+		Type:    "start",
+		Payload: json.RawMessage(`{"query":"subscription { newBlock(minheight: 2) { header { height } } }"}`),
+	}
+	if err := c.WriteJSON(start); err != nil {
+		t.Fatal(err)
+	}
+
+	// Leaving the time for the subscription to start
+	time.Sleep(time.Second)
+
+	// Only the second block matches the subscription
+	for _, height := range []uint64{1, 2} {
+		blk := helper.RandomBlock(t, height, 2)
+		msg := message.New(topics.AcceptedBlock, *blk)
+		eb.Publish(topics.AcceptedBlock, msg)
+	}
+
+	op := read()
+	assert.Equal(t, "data", op.Type)
+	assert.Equal(t, "1", op.ID)
+	assert.JSONEq(t, `{"data":{"newBlock":{"header":{"height":2}}}}`, string(op.Payload))
+}
+
+func TestReloadAndShutdown(t *testing.T) {
+
+	s, eb, err := setupServer(t, "127.0.0.1:22224")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	dial := func() *websocket.Conn {
+		dialCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		u := url.URL{Scheme: "ws", Host: "127.0.0.1:22224", Path: "/ws"}
+		c, _, err := websocket.DefaultDialer.DialContext(dialCtx, u.String(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	closeError := func(c *websocket.Conn) *websocket.CloseError {
+		_ = c.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _, err := c.ReadMessage()
+		closeErr, ok := err.(*websocket.CloseError)
+		if !ok {
+			t.Fatalf("expected a close frame, got %v", err)
+		}
+		return closeErr
+	}
+
+	c := dial()
+	defer c.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	// Changing the broker counts restarts the brokers
+	r := mockConfig("127.0.0.1:22224")
+	r.Gql.Notification.BrokersNum = 2
+	r.Gql.MaxRequestLimit = 50
+	config.Mock(&r)
+	eb.Publish(topics.ConfigReloaded, message.New(topics.ConfigReloaded, bytes.Buffer{}))
+
+	closeErr := closeError(c)
+	assert.Equal(t, websocket.CloseGoingAway, closeErr.Code)
+	assert.Equal(t, "server restarting", closeErr.Text)
+	assert.Equal(t, float64(50), s.current().lmt.GetMax())
+
+	// The clients reconnect to the new brokers, and are told about the
+	// shutdown
+	c = dial()
+	defer c.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	assert.NoError(t, s.Stop())
+	closeErr = closeError(c)
+	assert.Equal(t, websocket.CloseGoingAway, closeErr.Code)
+	assert.Equal(t, "server shutting down", closeErr.Text)
+
+	// The stopped server does not cache the accepted blocks anymore
+	blk := helper.RandomBlock(t, 1, 1)
+	eb.Publish(topics.AcceptedBlock, message.New(topics.AcceptedBlock, *blk))
+	_, cached := s.cache.ByHeight(1)
+	assert.False(t, cached)
+}
+
+// mockConfig creates the configuration of a HTTP server with notifications
+// enabled
+func mockConfig(addr string) config.Registry {
 	r := config.Registry{}
 	r.Gql.Network = "tcp"
 	r.Gql.Address = addr
@@ -92,6 +233,11 @@ func setupServer(t *testing.T, addr string) (*Server, *eventbus.EventBus, error)
 	r.Gql.Notification.BrokersNum = 1
 	r.Database.Driver = lite.DriverName
 	r.General.Network = "testnet"
+	return r
+}
+
+func setupServer(t *testing.T, addr string) (*Server, *eventbus.EventBus, error) {
+	r := mockConfig(addr)
 	config.Mock(&r)
 
 	eb := eventbus.New()
@@ -1,14 +1,23 @@
 package notifications
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"container/list"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-wallet/v2/block"
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
 	logger "github.com/sirupsen/logrus"
 )
 
@@ -18,8 +27,25 @@ const (
 	writeDeadline = 3 * time.Second
 
 	maxTxsPerMsg = 15
+
+	// drainTimeout is how long a terminated broker waits for its clients to
+	// be sent their pending messages and a close frame
+	drainTimeout = 5 * time.Second
+
+	reasonShutdown = "server shutting down"
+	reasonRestart  = "server restarting"
+	reasonTooMany  = "too many connections"
+	reasonSlow     = "slow consumer"
+
+	// replayPollInterval is how often a replay checks whether the queue of its
+	// client has room for the next blocks
+	replayPollInterval = 100 * time.Millisecond
 )
 
+// subscriptionTopics are the topics triggering GraphQL subscriptions, besides
+// the accepted blocks
+var subscriptionTopics = []topics.Topic{topics.VerifiedTx, topics.RoundUpdate, topics.SyncProgress}
+
 var log = logger.WithField("process", "broker")
 
 // Broker is a pub/sub broker that keeps updated all subscribers (websocket
@@ -37,6 +63,7 @@ type Broker struct {
 	clients *list.List
 	// max number of clients per a broker instance
 	maxClientsCount uint
+	opts            Options
 
 	// ConnectionChan is a shared queue to buffer incoming websocket connections
 	// closing connChan will terminate the broker
@@ -46,17 +73,60 @@ type Broker struct {
 	eventBus          eventbus.Broker
 	acceptedBlockChan chan block.Block
 	acceptedBlockId   uint32
+
+	// GraphQL subscriptions. A nil schema disables them
+	schema    *graphql.Schema
+	opChan    chan clientOperation
+	eventChan chan message.Message
+	eventIds  map[topics.Topic]uint32
+
+	// Chain DB the missed blocks are replayed from. A nil DB disables the
+	// replays
+	db         database.DB
+	replayChan chan replay
+	// tip is the height of the last accepted block
+	tip uint64
+
+	// closeReason is sent to the clients once the broker is terminated. It
+	// is set before closing ConnectionChan
+	closeReason string
+}
+
+// replay is a batch of blocks read from the DB for a resuming client
+type replay struct {
+	client *wsClient
+	blocks []block.Block
+	// more is set if the blocks did not reach the tip
+	more bool
+	err  error
 }
 
-func NewBroker(id uint, eventBus eventbus.Broker, maxClientsCount uint, connChan chan wsConn) *Broker {
+func NewBroker(id uint, eventBus eventbus.Broker, schema *graphql.Schema, db database.DB, opts Options, connChan chan wsConn) *Broker {
+
+	opts = opts.withDefaults()
 
 	b := new(Broker)
 	b.eventBus = eventBus
 	b.ConnectionChan = connChan
 	b.acceptedBlockChan, b.acceptedBlockId = consensus.InitAcceptedBlockUpdate(eventBus)
 	b.clients = list.New()
-	b.maxClientsCount = maxClientsCount
+	b.maxClientsCount = opts.ClientsPerBroker
+	b.opts = opts
 	b.id = id
+	b.db = db
+	b.replayChan = make(chan replay, 10)
+
+	b.schema = schema
+	b.opChan = make(chan clientOperation, 100)
+	b.eventChan = make(chan message.Message, 100)
+	b.eventIds = make(map[topics.Topic]uint32)
+	b.closeReason = reasonShutdown
+	if schema != nil {
+		for _, topic := range subscriptionTopics {
+			b.eventIds[topic] = eventBus.Subscribe(topic, eventbus.NewChanListener(b.eventChan))
+		}
+	}
+
 	return b
 }
 
@@ -71,11 +141,30 @@ func (b *Broker) Run() {
 
 		// Unsubscribe from all eventBus events
 		b.eventBus.Unsubscribe(topics.AcceptedBlock, b.acceptedBlockId)
+		for topic, id := range b.eventIds {
+			b.eventBus.Unsubscribe(topic, id)
+		}
 
-		// Terminate all clients goroutines
+		// Terminate all clients goroutines, letting them know the server is
+		// going away
 		for e := b.clients.Front(); e != nil; e = e.Next() {
 			c := e.Value.(*wsClient)
-			close(c.msgChan)
+			if !c.terminated {
+				c.closeCode, c.closeReason = websocket.CloseGoingAway, b.closeReason
+			}
+			b.terminate(c)
+		}
+
+		// Drain the clients, so that they are sent their close frame
+		deadline := time.After(drainTimeout)
+	drain:
+		for e := b.clients.Front(); e != nil; e = e.Next() {
+			select {
+			case <-e.Value.(*wsClient).done:
+			case <-deadline:
+				log.Warnf("Broker %d could not drain all clients", b.id)
+				break drain
+			}
 		}
 
 		// reset clients list
@@ -97,6 +186,15 @@ func (b *Broker) Run() {
 		// new accepted block from node
 		case blk := <-b.acceptedBlockChan:
 			b.handleBlock(blk)
+		// other events triggering subscriptions
+		case m := <-b.eventChan:
+			b.handleEvent(m.Category(), m.Payload())
+		// operation from a client
+		case op := <-b.opChan:
+			b.handleOperation(op)
+		// blocks missed by a resuming client
+		case r := <-b.replayChan:
+			b.handleReplay(r)
 		case <-time.After(30 * time.Second):
 			b.handleIdle()
 		}
@@ -114,13 +212,64 @@ func (b *Broker) handleBlock(blk block.Block) {
 	}()
 
 	b.reap()
+	b.tip = blk.Header.Height
 
-	msg, err := MarshalBlockMsg(blk)
+	msg, err := NewBlockMsg(blk)
 	if err != nil {
 		log.Errorf("encoding err: %v", err)
 	}
 
-	b.broadcastMessage(msg)
+	for e := b.clients.Front(); e != nil; e = e.Next() {
+		c := e.Value.(*wsClient)
+
+		// The resuming clients are notified of the block once their missed
+		// blocks are replayed
+		if c.terminated || c.replaying || (c.resumed && blk.Header.Height <= c.height) {
+			continue
+		}
+
+		b.notifyBlock(c, blk, msg)
+	}
+}
+
+// notifyBlock notifies a client of an accepted block, with a BlockMsg or
+// through its subscriptions
+func (b *Broker) notifyBlock(c *wsClient, blk block.Block, msg *BlockMsg) {
+	c.height = blk.Header.Height
+
+	if c.gqlws {
+		b.notifyClient(c, topics.AcceptedBlock, blk)
+		return
+	}
+
+	if msg == nil {
+		return
+	}
+
+	c.seq++
+	m := *msg
+	m.Seq = c.seq
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		log.Errorf("encoding err: %v", err)
+		return
+	}
+
+	b.push(c, data)
+}
+
+// handleEvent handles the events triggering the GraphQL subscriptions
+func (b *Broker) handleEvent(topic topics.Topic, payload interface{}) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("handleEvent recovered from err: %v", r)
+		}
+	}()
+
+	b.reap()
+	b.notify(topic, payload)
 }
 
 // handleConn handles a new websocket conn pushed from webserver layer It stores
@@ -143,14 +292,19 @@ func (b *Broker) handleConn(conn wsConn) {
 		// Free a slot by removing the oldest connection
 		e := b.clients.Front()
 		c := e.Value.(*wsClient)
-		close(c.msgChan)
+		c.closeCode, c.closeReason = websocket.CloseTryAgainLater, reasonTooMany
+		b.terminate(c)
 		b.clients.Remove(e)
 	}
 
 	c := &wsClient{
-		conn:    conn,
-		msgChan: make(chan []byte, 100),
-		id:      conn.RemoteAddr().String(),
+		conn:          conn,
+		msgChan:       make(chan []byte, b.opts.QueueSize),
+		id:            conn.RemoteAddr().String(),
+		gqlws:         b.schema != nil && conn.Subprotocol() == GraphQLWS,
+		ops:           b.opChan,
+		subscriptions: make(map[string]*subscription),
+		done:          make(chan struct{}),
 	}
 
 	_ = b.clients.PushBack(c)
@@ -169,21 +323,13 @@ func (b *Broker) handleConn(conn wsConn) {
 
 func (b *Broker) handleIdle() {
 	log.Infof("Broker %d, active conn: %d", b.id, b.clients.Len())
-}
-
-// broadcastMessage propagates data to all active clients
-func (b *Broker) broadcastMessage(data string) {
-
-	if len(data) == 0 || b.clients.Len() == 0 {
-		return
-	}
-
-	log.Tracef("Broadcast message to %d clients", b.clients.Len())
-	log.Tracef("Message: %s ", data)
 
+	// keep the graphql-ws connections alive
 	for e := b.clients.Front(); e != nil; e = e.Next() {
 		c := e.Value.(*wsClient)
-		c.msgChan <- []byte(data)
+		if c.gqlws && c.initialized {
+			b.send(c, "", gqlConnectionKeepAlive, nil)
+		}
 	}
 }
 
@@ -203,3 +349,349 @@ func (b *Broker) reap() {
 		}
 	}
 }
+
+// handleOperation handles a graphql-ws operation message sent by a client
+func (b *Broker) handleOperation(op clientOperation) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("handleOperation recovered from err: %v", r)
+		}
+	}()
+
+	c, msg := op.client, op.msg
+	if c.terminated {
+		// the client was terminated already
+		return
+	}
+
+	// resume is the only message of the clients not speaking graphql-ws
+	if msg.Type == msgResume {
+		if err := b.resume(c, msg.Payload); err != nil {
+			b.send(c, "", gqlError, []gqlerrors.FormattedError{gqlerrors.FormatError(err)})
+		}
+		return
+	}
+
+	if !c.gqlws {
+		return
+	}
+
+	switch msg.Type {
+	case gqlConnectionInit:
+		c.initialized = true
+		b.send(c, "", gqlConnectionAck, nil)
+		b.send(c, "", gqlConnectionKeepAlive, nil)
+	case gqlStart:
+		if err := b.start(c, msg); err != nil {
+			b.send(c, msg.ID, gqlError, []gqlerrors.FormattedError{gqlerrors.FormatError(err)})
+		}
+	case gqlStop:
+		if _, ok := c.subscriptions[msg.ID]; ok {
+			delete(c.subscriptions, msg.ID)
+			b.send(c, msg.ID, gqlComplete, nil)
+		}
+	case gqlConnectionTerminate:
+		b.terminate(c)
+	default:
+		b.send(c, msg.ID, gqlConnectionError, gqlerrors.FormatError(errors.New("unknown message type: "+msg.Type)))
+	}
+}
+
+// start a subscription of a client
+func (b *Broker) start(c *wsClient, msg operationMessage) error {
+	if !c.initialized {
+		return errors.New("connection not initialized")
+	}
+
+	if msg.ID == "" {
+		return errors.New("missing subscription id")
+	}
+
+	if _, dup := c.subscriptions[msg.ID]; dup {
+		return errors.New("duplicated subscription id: " + msg.ID)
+	}
+
+	if len(c.subscriptions) >= maxSubscriptions {
+		return errors.New("too many subscriptions")
+	}
+
+	sub, err := newSubscription(b.schema, msg.ID, msg.Payload)
+	if err != nil {
+		return err
+	}
+
+	c.subscriptions[msg.ID] = sub
+	return nil
+}
+
+// notify the subscriptions triggered by a topic, with the result of their
+// execution against the payload of the event
+func (b *Broker) notify(topic topics.Topic, payload interface{}) {
+
+	for e := b.clients.Front(); e != nil; e = e.Next() {
+		c := e.Value.(*wsClient)
+		if !c.gqlws || c.terminated || c.IsClosed() {
+			continue
+		}
+
+		b.notifyClient(c, topic, payload)
+	}
+}
+
+// notifyClient notifies the subscriptions of a client triggered by a topic
+func (b *Broker) notifyClient(c *wsClient, topic topics.Topic, payload interface{}) {
+
+	for id, sub := range c.subscriptions {
+		if sub.trigger.Topic != topic {
+			continue
+		}
+
+		result := sub.execute(b.schema, payload)
+		if result == nil {
+			continue
+		}
+
+		c.seq++
+		b.sendOperation(c, operationMessage{ID: id, Type: gqlData, Seq: c.seq}, result)
+
+		if sub.trigger.Once && len(result.Errors) == 0 {
+			delete(c.subscriptions, id)
+			b.send(c, id, gqlComplete, nil)
+		}
+	}
+}
+
+// send a graphql-ws message to a client
+func (b *Broker) send(c *wsClient, id, msgType string, payload interface{}) {
+	b.sendOperation(c, operationMessage{ID: id, Type: msgType}, payload)
+}
+
+func (b *Broker) sendOperation(c *wsClient, op operationMessage, payload interface{}) {
+	if c.terminated {
+		return
+	}
+
+	msg, err := marshalOperation(op, payload)
+	if err != nil {
+		log.Errorf("encoding err: %v", err)
+		return
+	}
+
+	b.push(c, msg)
+}
+
+// push queues a message to a client. If the queue of the client is full, the
+// slow consumer policy is applied, so that the broker is never blocked by a
+// client
+func (b *Broker) push(c *wsClient, msg []byte) {
+	if c.terminated {
+		return
+	}
+
+	select {
+	case c.msgChan <- msg:
+		return
+	default:
+	}
+
+	switch b.opts.SlowConsumer {
+	case DropNewest:
+		log.Tracef("Queue of client %s is full. Dropping the newest message", c.id)
+	case DropOldest:
+		log.Tracef("Queue of client %s is full. Dropping the oldest message", c.id)
+
+		// The broker being the only sender, the queue has room once a
+		// message is taken out, either here or by the writer
+		select {
+		case <-c.msgChan:
+		default:
+		}
+		c.msgChan <- msg
+	default:
+		log.Warnf("Queue of client %s is full. Disconnecting it", c.id)
+		c.closeCode, c.closeReason = websocket.ClosePolicyViolation, reasonSlow
+		b.terminate(c)
+	}
+}
+
+// resume replays to a client the blocks accepted after the height of its
+// resume message. The blocks are read from the DB in the background, while the
+// live notifications of the accepted blocks are held back
+func (b *Broker) resume(c *wsClient, payload json.RawMessage) error {
+	if b.db == nil {
+		return errors.New("resume not supported")
+	}
+
+	if c.replaying {
+		return errors.New("already resuming")
+	}
+
+	// unknown fields are refused, so that a client resuming from a sequence
+	// number of a previous connection is told it is not supported
+	var p resumePayload
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return fmt.Errorf("invalid resume payload: %v", err)
+	}
+
+	c.resumed, c.replaying = true, true
+	c.height = p.Height
+
+	go b.readBlocks(c, p.Height+1, 0)
+	return nil
+}
+
+// readBlocks reads the blocks of a replay from a height, at most MaxReplay
+// blocks behind the tip, and hands them over to the broker. It waits for the
+// queue of the client to be half empty, and reads as many blocks as half the
+// queue can hold. The read is delayed by the given duration
+func (b *Broker) readBlocks(c *wsClient, from uint64, delay time.Duration) {
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-c.done:
+			return
+		}
+	}
+
+	for len(c.msgChan) > cap(c.msgChan)/2 {
+		select {
+		case <-time.After(replayPollInterval):
+		case <-c.done:
+			return
+		}
+	}
+
+	max := uint64(cap(c.msgChan) / 2)
+	if max == 0 {
+		max = 1
+	}
+
+	r := replay{client: c}
+	r.err = b.db.View(func(t database.Transaction) error {
+		tip, err := t.FetchCurrentHeight()
+		if err != nil {
+			return err
+		}
+
+		if maxReplay := uint64(b.opts.MaxReplay); tip >= from+maxReplay {
+			from = tip + 1 - maxReplay
+		}
+
+		to := tip
+		if to >= from+max {
+			to = from + max - 1
+			r.more = true
+		}
+
+		for height := from; height <= to; height++ {
+			hash, err := t.FetchBlockHashByHeight(height)
+			if err != nil {
+				return err
+			}
+
+			blk, err := t.FetchBlock(hash)
+			if err != nil {
+				return err
+			}
+
+			r.blocks = append(r.blocks, *blk)
+		}
+
+		return nil
+	})
+
+	// The client is done if it was terminated, or if the broker is
+	select {
+	case b.replayChan <- r:
+	case <-c.done:
+	}
+}
+
+// handleReplay notifies a resuming client of the blocks it missed. The blocks
+// which do not fit in the queue of the client, and the ones accepted in the
+// meantime, are read in another round
+func (b *Broker) handleReplay(r replay) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("handleReplay recovered from err: %v", r)
+		}
+	}()
+
+	c := r.client
+	if c.terminated {
+		return
+	}
+
+	if r.err != nil {
+		c.replaying = false
+		b.send(c, "", gqlError, []gqlerrors.FormattedError{gqlerrors.FormatError(fmt.Errorf("could not replay the missed blocks: %v", r.err))})
+		return
+	}
+
+	// A block is notified to each of the subscriptions of a client, each
+	// notification possibly completing its subscription
+	perBlock := 1
+	if c.gqlws {
+		perBlock = 2 * len(c.subscriptions)
+	}
+
+	// The blocks read from the DB were accepted, even if the broker was not
+	// notified of them yet
+	if n := len(r.blocks); n > 0 && r.blocks[n-1].Header.Height > b.tip {
+		b.tip = r.blocks[n-1].Header.Height
+	}
+
+	height := c.height
+	for _, blk := range r.blocks {
+		if blk.Header.Height <= c.height {
+			continue
+		}
+
+		if len(c.msgChan) > 0 && cap(c.msgChan)-len(c.msgChan) < perBlock {
+			break
+		}
+
+		var msg *BlockMsg
+		if !c.gqlws {
+			m, err := NewBlockMsg(blk)
+			if err != nil {
+				log.Errorf("encoding err: %v", err)
+			}
+			msg = m
+		}
+
+		b.notifyBlock(c, blk, msg)
+	}
+
+	// The live notifications of the blocks accepted during the replay were
+	// held back, so the client keeps replaying until it reaches the tip. A
+	// read not making any progress is retried later, as the DB may not have
+	// stored the accepted blocks yet
+	if r.more || c.height < b.tip {
+		var delay time.Duration
+		if c.height == height {
+			delay = replayPollInterval
+		}
+
+		go b.readBlocks(c, c.height+1, delay)
+		return
+	}
+
+	c.replaying = false
+}
+
+// terminate a client, by closing its message channel. The client is then
+// reaped once its connection is closed
+func (b *Broker) terminate(c *wsClient) {
+	if c.terminated {
+		return
+	}
+
+	close(c.msgChan)
+	c.terminated = true
+}
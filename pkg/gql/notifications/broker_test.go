@@ -2,8 +2,15 @@ package notifications
 
 import (
 	"container/list"
+	"encoding/json"
 	"strconv"
 	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestReapClients(t *testing.T) {
@@ -40,3 +47,123 @@ func TestReapClients(t *testing.T) {
 		t.Fatalf("Not all closed")
 	}
 }
+
+func TestSlowConsumer(t *testing.T) {
+
+	queued := func(c *wsClient) []string {
+		msgs := make([]string, 0)
+		for len(c.msgChan) > 0 {
+			msgs = append(msgs, string(<-c.msgChan))
+		}
+		return msgs
+	}
+
+	expected := map[Policy][]string{
+		DropNewest: {"1", "2"},
+		DropOldest: {"2", "3"},
+	}
+
+	for policy, msgs := range expected {
+		b := Broker{opts: Options{SlowConsumer: policy}}
+		c := &wsClient{msgChan: make(chan []byte, 2)}
+		for _, msg := range []string{"1", "2", "3"} {
+			b.push(c, []byte(msg))
+		}
+
+		assert.False(t, c.terminated)
+		assert.Equal(t, msgs, queued(c))
+	}
+
+	// The slow clients are disconnected by default
+	b := Broker{}
+	c := &wsClient{msgChan: make(chan []byte, 2)}
+	for _, msg := range []string{"1", "2", "3"} {
+		b.push(c, []byte(msg))
+	}
+
+	assert.True(t, c.terminated)
+	assert.Equal(t, websocket.ClosePolicyViolation, c.closeCode)
+	assert.Equal(t, []string{"1", "2"}, queued(c))
+}
+
+func TestBlockAcceptedDuringReplay(t *testing.T) {
+
+	_, db := lite.CreateDBConnection()
+	defer db.Close()
+	storeBlocks(t, db, 0, 10)
+
+	b := NewBroker(0, eventbus.New(), nil, db, Options{}, nil)
+	c := &wsClient{msgChan: make(chan []byte, 10), done: make(chan struct{})}
+	b.clients.PushBack(c)
+
+	// The client resumes at the tip, so that the DB read finds no blocks
+	c.resumed, c.replaying, c.height = true, true, 9
+	b.readBlocks(c, 10, 0)
+	r := <-b.replayChan
+	assert.Empty(t, r.blocks)
+
+	// A block is accepted before the replay is handled. Its live notification
+	// is held back
+	blk := storeBlocks(t, db, 10, 11)[0]
+	b.handleBlock(blk)
+	assert.Empty(t, c.msgChan)
+
+	// The empty replay does not end the resume, and the block is read again
+	b.handleReplay(r)
+	assert.True(t, c.replaying)
+
+	select {
+	case r = <-b.replayChan:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the accepted block was not replayed")
+	}
+
+	b.handleReplay(r)
+	assert.False(t, c.replaying)
+	assert.Len(t, c.msgChan, 1)
+
+	var msg BlockMsg
+	assert.NoError(t, json.Unmarshal(<-c.msgChan, &msg))
+	assert.Equal(t, uint64(10), msg.Height)
+	assert.Equal(t, uint64(1), msg.Seq)
+}
+
+// Resuming is by height only, as the sequence numbers restart on every
+// connection
+func TestResumePayload(t *testing.T) {
+
+	_, db := lite.CreateDBConnection()
+	defer db.Close()
+	storeBlocks(t, db, 0, 10)
+
+	b := NewBroker(0, eventbus.New(), nil, db, Options{}, nil)
+	c := &wsClient{msgChan: make(chan []byte, 10), done: make(chan struct{})}
+	b.clients.PushBack(c)
+
+	assert.Error(t, b.resume(c, json.RawMessage(`{"height":4,"seq":12}`)))
+	assert.False(t, c.resumed)
+
+	assert.NoError(t, b.resume(c, json.RawMessage(`{"height":4}`)))
+	assert.Equal(t, uint64(4), c.height)
+
+	r := <-b.replayChan
+	b.handleReplay(r)
+
+	var msg BlockMsg
+	assert.NoError(t, json.Unmarshal(<-c.msgChan, &msg))
+	assert.Equal(t, uint64(5), msg.Height)
+	assert.Equal(t, uint64(1), msg.Seq)
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("DropOldest")
+	assert.NoError(t, err)
+	assert.Equal(t, DropOldest, p)
+
+	p, err = ParsePolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, Disconnect, p)
+
+	_, err = ParsePolicy("block")
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package notifications
 
 import (
+	"encoding/json"
 	"sync/atomic"
 	"time"
 
@@ -14,7 +15,34 @@ type wsClient struct {
 	msgChan chan []byte
 	id      string
 
+	// gqlws is set for the clients speaking the graphql-ws protocol
+	gqlws bool
+	// ops is where the operations of graphql-ws clients are handed over to
+	// the broker
+	ops chan<- clientOperation
+	// terminated, initialized and subscriptions are only accessed by the
+	// broker
+	terminated    bool
+	initialized   bool
+	subscriptions map[string]*subscription
+
+	// seq is the sequence number of the last notification sent to the
+	// client. A gap tells the client some notifications were dropped
+	seq uint64
+	// height is the height of the last block notified to the client
+	height uint64
+	// resumed is set once the client sent a resume message, and replaying
+	// while the blocks it missed are replayed
+	resumed, replaying bool
+
+	// closeCode and closeReason are sent with the close frame. They are set
+	// by the broker before terminating the client
+	closeCode   int
+	closeReason string
+
 	closed int32
+	// done is closed once the close frame is sent
+	done chan struct{}
 }
 
 func (c *wsClient) writeLoop() {
@@ -27,9 +55,14 @@ func (c *wsClient) writeLoop() {
 		// handshake (FIN/ACK), on the basis that the TCP closing handshake is not
 		// always reliable end-to-end, especially in the presence of intercepting
 		// proxies and other intermediaries.
-		_ = c.conn.WriteControl(websocket.CloseMessage, []byte{}, time.Now().Add(time.Second))
+		code := c.closeCode
+		if code == 0 {
+			code = websocket.CloseNormalClosure
+		}
+		_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, c.closeReason), time.Now().Add(time.Second))
 
 		c.conn.Close()
+		close(c.done)
 
 		log.Tracef("Close websocket client %s", c.id)
 	}()
@@ -57,9 +90,28 @@ func (c *wsClient) writeLoop() {
 
 func (c *wsClient) readLoop() {
 	for {
-		if _, _, err := c.conn.NextReader(); err != nil {
+		_, r, err := c.conn.NextReader()
+		if err != nil {
 			break
 		}
+
+		var msg operationMessage
+		if err := json.NewDecoder(r).Decode(&msg); err != nil {
+			log.Tracef("client %s sent an invalid message: %v", c.id, err)
+			continue
+		}
+
+		// The clients not speaking graphql-ws can only resume
+		if !c.gqlws && msg.Type != msgResume {
+			continue
+		}
+
+		// The broker is never waited for, as it might be terminated already
+		select {
+		case c.ops <- clientOperation{client: c, msg: msg}:
+		default:
+			log.Errorf("Operations queue is full. Discarding %s operation from %s", msg.Type, c.id)
+		}
 	}
 }
 
@@ -7,6 +7,16 @@ import (
 	"github.com/dusk-network/dusk-wallet/v2/block"
 )
 
+// msgResume is the type of the message of a client asking for the blocks
+// accepted after the last height it has seen
+const msgResume = "resume"
+
+// resumePayload is the payload of a resume message. Resuming is by height
+// only, as the sequence numbers are per connection
+type resumePayload struct {
+	Height uint64 `json:"height"`
+}
+
 // BlockMsg represents the data need by Explorer UI on each new block accepted
 type BlockMsg struct {
 	Height    uint64
@@ -16,16 +26,36 @@ type BlockMsg struct {
 
 	// BlocksGeneratedCount is number of blocks generated last 24 hours
 	BlocksGeneratedCount uint
+
+	// Seq is the sequence number of the notification. A gap tells the client
+	// some notifications were dropped
+	Seq uint64 `json:",omitempty"`
 }
 
 // MarshalBlockMsg builds the JSON from a subset of block fields
 func MarshalBlockMsg(blk block.Block) (string, error) {
 
-	hash, err := blk.CalculateHash()
+	p, err := NewBlockMsg(blk)
+	if err != nil {
+		return "", err
+	}
+
+	msg, err := json.Marshal(p)
 	if err != nil {
 		return "", err
 	}
 
+	return string(msg), nil
+}
+
+// NewBlockMsg builds the BlockMsg of a block, without sequence number
+func NewBlockMsg(blk block.Block) (*BlockMsg, error) {
+
+	hash, err := blk.CalculateHash()
+	if err != nil {
+		return nil, err
+	}
+
 	var p BlockMsg
 	p.Height = blk.Header.Height
 	p.Timestamp = blk.Header.Timestamp
@@ -40,16 +70,11 @@ func MarshalBlockMsg(blk block.Block) (string, error) {
 
 		txid, err := tx.CalculateHash()
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		p.Txs = append(p.Txs, hex.EncodeToString(txid))
 	}
 
-	msg, err := json.Marshal(p)
-	if err != nil {
-		return "", err
-	}
-
-	return string(msg), nil
+	return &p, nil
 }
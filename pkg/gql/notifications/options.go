@@ -0,0 +1,79 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy is what a broker does with a client whose queue is full, because the
+// client consumes its notifications slower than they are published
+type Policy uint8
+
+// Slow consumer policies
+const (
+	// Disconnect the client, with a policy violation close frame
+	Disconnect Policy = iota
+	// DropOldest drops the oldest notification of the queue
+	DropOldest
+	// DropNewest drops the notification
+	DropNewest
+)
+
+var policyNames = map[string]Policy{
+	"disconnect": Disconnect,
+	"dropoldest": DropOldest,
+	"dropnewest": DropNewest,
+}
+
+// ParsePolicy parses the name of a slow consumer policy. Empty name defaults
+// to Disconnect
+func ParsePolicy(name string) (Policy, error) {
+	if len(name) == 0 {
+		return Disconnect, nil
+	}
+
+	p, ok := policyNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Disconnect, fmt.Errorf("unknown slow consumer policy %q", name)
+	}
+
+	return p, nil
+}
+
+// Options are the settings of the brokers of a pool. Zero values fall back to
+// DefaultOptions
+type Options struct {
+	// ClientsPerBroker is the maximum number of clients of a broker
+	ClientsPerBroker uint
+	// QueueSize is the number of notifications buffered for each client
+	QueueSize uint
+	// SlowConsumer is applied to the clients whose queue is full
+	SlowConsumer Policy
+	// MaxReplay is the maximum number of blocks replayed to a resuming
+	// client
+	MaxReplay uint
+}
+
+// DefaultOptions are the options used for the zero values
+var DefaultOptions = Options{
+	ClientsPerBroker: 100,
+	QueueSize:        100,
+	SlowConsumer:     Disconnect,
+	MaxReplay:        1000,
+}
+
+func (o Options) withDefaults() Options {
+	if o.ClientsPerBroker == 0 {
+		o.ClientsPerBroker = DefaultOptions.ClientsPerBroker
+	}
+
+	if o.QueueSize == 0 {
+		o.QueueSize = DefaultOptions.QueueSize
+	}
+
+	if o.MaxReplay == 0 {
+		o.MaxReplay = DefaultOptions.MaxReplay
+	}
+
+	return o
+}
@@ -1,12 +1,16 @@
 package notifications
 
 import (
+	"context"
 	"io"
 	"net"
+	"sync"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
 )
 
 // wsConn mimics the websocket.Conn from gorilla/websocket
@@ -17,6 +21,7 @@ type wsConn interface {
 	WriteControl(messageType int, data []byte, deadline time.Time) error
 	RemoteAddr() net.Addr
 	SetWriteDeadline(t time.Time) error
+	Subprotocol() string
 	Close() error
 }
 
@@ -26,9 +31,19 @@ type BrokerPool struct {
 	ConnectionsChan chan wsConn
 	QuitChan        chan bool
 	workers         []*Broker
+
+	// lock guards the closing of ConnectionsChan against PushConn
+	lock   sync.RWMutex
+	closed bool
+	// running is the group of the running brokers
+	running sync.WaitGroup
 }
 
-func NewPool(eventBus *eventbus.EventBus, brokersNum, clientsPerBroker uint) *BrokerPool {
+// NewPool creates and runs the brokers. The schema is used to resolve the
+// GraphQL subscriptions, which are disabled if it is nil. The DB is used to
+// replay the missed blocks to the resuming clients, which is disabled if it is
+// nil
+func NewPool(eventBus *eventbus.EventBus, schema *graphql.Schema, db database.DB, brokersNum uint, opts Options) *BrokerPool {
 
 	bp := new(BrokerPool)
 	bp.workers = make([]*Broker, 0)
@@ -36,13 +51,17 @@ func NewPool(eventBus *eventbus.EventBus, brokersNum, clientsPerBroker uint) *Br
 
 	// Instantiate all brokers
 	for i := uint(0); i < brokersNum; i++ {
-		br := NewBroker(i, eventBus, clientsPerBroker, bp.ConnectionsChan)
+		br := NewBroker(i, eventBus, schema, db, opts, bp.ConnectionsChan)
 		bp.workers = append(bp.workers, br)
 	}
 
 	// Run all brokers workers
 	for _, br := range bp.workers {
-		go br.Run()
+		bp.running.Add(1)
+		go func(br *Broker) {
+			defer bp.running.Done()
+			br.Run()
+		}(br)
 	}
 
 	return bp
@@ -54,6 +73,16 @@ func (bp *BrokerPool) PushConn(conn *websocket.Conn) {
 		return
 	}
 
+	bp.lock.RLock()
+	defer bp.lock.RUnlock()
+
+	// The pool is being replaced or shut down
+	if bp.closed {
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, reasonRestart), time.Now().Add(time.Second))
+		conn.Close()
+		return
+	}
+
 	select {
 	case bp.ConnectionsChan <- conn:
 	default:
@@ -61,9 +90,56 @@ func (bp *BrokerPool) PushConn(conn *websocket.Conn) {
 	}
 }
 
+// Close the pool, without waiting for the brokers to terminate. The clients
+// are sent a going away close frame
 func (bp *BrokerPool) Close() {
+	bp.close(reasonShutdown)
+}
+
+func (bp *BrokerPool) close(reason string) {
+
+	bp.lock.Lock()
+	defer bp.lock.Unlock()
+	if bp.closed {
+		return
+	}
+	bp.closed = true
+
+	for _, br := range bp.workers {
+		br.closeReason = reason
+	}
 
 	// Closing the shared chan will trigger a cascading teardown procedure for
 	// brokers and their clients.
 	close(bp.ConnectionsChan)
 }
+
+// Shutdown closes the pool and waits for the brokers to terminate, after their
+// clients have been sent a close frame, or for the context to be done
+func (bp *BrokerPool) Shutdown(ctx context.Context) error {
+	bp.Close()
+	return bp.wait(ctx)
+}
+
+// Restart closes the pool as Shutdown does, letting the clients know that they
+// can reconnect to the pool replacing it
+func (bp *BrokerPool) Restart(ctx context.Context) error {
+	bp.close(reasonRestart)
+	return bp.wait(ctx)
+}
+
+func (bp *BrokerPool) wait(ctx context.Context) error {
+
+	terminated := make(chan struct{})
+	go func() {
+		bp.running.Wait()
+		close(terminated)
+	}()
+
+	select {
+	case <-terminated:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
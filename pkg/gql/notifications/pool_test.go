@@ -1,6 +1,9 @@
 package notifications
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -10,15 +13,26 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-wallet/v2/block"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
 )
 
 type mockWebsocketConn struct {
 	mu     sync.RWMutex
 	msgBuf map[string]bool
+	// blocks are the block messages received, in order
+	blocks []BlockMsg
+	// closeFrame is the payload of the close frame sent to the conn
+	closeFrame []byte
+	// reads are the messages sent by the client. A nil chan fails the reads
+	reads chan []byte
 }
 
 func (c *mockWebsocketConn) WriteMessage(messageType int, data []byte) error {
@@ -28,12 +42,18 @@ func (c *mockWebsocketConn) WriteMessage(messageType int, data []byte) error {
 	if err := json.Unmarshal(data, &p); err == nil {
 		c.mu.Lock()
 		c.msgBuf[p.Hash] = true
+		c.blocks = append(c.blocks, p)
 		c.mu.Unlock()
 	}
 
 	return nil
 }
 func (c *mockWebsocketConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	if messageType == websocket.CloseMessage {
+		c.mu.Lock()
+		c.closeFrame = data
+		c.mu.Unlock()
+	}
 	return nil
 }
 func (c *mockWebsocketConn) RemoteAddr() net.Addr {
@@ -42,12 +62,31 @@ func (c *mockWebsocketConn) RemoteAddr() net.Addr {
 func (c *mockWebsocketConn) SetWriteDeadline(t time.Time) error {
 	return nil
 }
+func (c *mockWebsocketConn) Subprotocol() string {
+	return ""
+}
 func (c *mockWebsocketConn) Close() error {
 	return nil
 }
 
 func (c *mockWebsocketConn) NextReader() (messageType int, r io.Reader, err error) {
-	return 0, r, errors.New("no impl")
+	if c.reads == nil {
+		return 0, r, errors.New("no impl")
+	}
+
+	msg, ok := <-c.reads
+	if !ok {
+		return 0, r, io.EOF
+	}
+
+	return websocket.TextMessage, bytes.NewReader(msg), nil
+}
+
+// received returns the block messages received by the conn
+func (c *mockWebsocketConn) received() []BlockMsg {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]BlockMsg{}, c.blocks...)
 }
 
 type mockAddr struct {
@@ -64,7 +103,7 @@ func (m *mockAddr) String() string {
 func TestPoolBasicScenario(t *testing.T) {
 
 	eb := eventbus.New()
-	pool := NewPool(eb, 10, 51)
+	pool := NewPool(eb, nil, nil, 10, Options{ClientsPerBroker: 51})
 	defer pool.Close()
 
 	ctxActiveConn := make([]*mockWebsocketConn, 50)
@@ -115,3 +154,110 @@ func TestPoolBasicScenario(t *testing.T) {
 		t.Fatal("invalid test context")
 	}
 }
+
+func TestPoolShutdown(t *testing.T) {
+
+	eb := eventbus.New()
+	pool := NewPool(eb, nil, nil, 2, Options{ClientsPerBroker: 10})
+
+	conns := make([]*mockWebsocketConn, 4)
+	for i := range conns {
+		conns[i] = &mockWebsocketConn{msgBuf: make(map[string]bool)}
+		pool.ConnectionsChan <- conns[i]
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, pool.Restart(ctx))
+
+	// All clients are told the server is restarting
+	for _, conn := range conns {
+		conn.mu.RLock()
+		frame := conn.closeFrame
+		conn.mu.RUnlock()
+
+		if assert.True(t, len(frame) >= 2) {
+			assert.Equal(t, websocket.CloseGoingAway, int(binary.BigEndian.Uint16(frame)))
+			assert.Equal(t, reasonRestart, string(frame[2:]))
+		}
+	}
+
+	// Closing again is harmless
+	pool.Close()
+	assert.NoError(t, pool.Shutdown(ctx))
+}
+
+func TestPoolResume(t *testing.T) {
+
+	// The client missed the blocks 5 to 9
+	_, db := lite.CreateDBConnection()
+	defer db.Close()
+	storeBlocks(t, db, 0, 10)
+
+	// A small queue replays the blocks in several rounds
+	eb := eventbus.New()
+	pool := NewPool(eb, nil, db, 1, Options{QueueSize: 4})
+	defer pool.Close()
+
+	conn := &mockWebsocketConn{msgBuf: make(map[string]bool), reads: make(chan []byte, 1)}
+	defer close(conn.reads)
+	pool.ConnectionsChan <- conn
+
+	conn.reads <- []byte(`{"type":"resume","payload":{"height":4}}`)
+
+	waitFor := func(n int) []BlockMsg {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			if blocks := conn.received(); len(blocks) >= n {
+				return blocks
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		t.Fatalf("expected %d blocks, got %d", n, len(conn.received()))
+		return nil
+	}
+
+	blocks := waitFor(5)
+	for i, blk := range blocks {
+		assert.Equal(t, uint64(5+i), blk.Height)
+		assert.Equal(t, uint64(1+i), blk.Seq)
+	}
+
+	// The live notifications follow the replayed ones
+	blk := helper.RandomBlock(t, 10, 1)
+	eb.Publish(topics.AcceptedBlock, message.New(topics.AcceptedBlock, *blk))
+
+	blocks = waitFor(6)
+	assert.Len(t, blocks, 6)
+	assert.Equal(t, uint64(10), blocks[5].Height)
+	assert.Equal(t, uint64(6), blocks[5].Seq)
+}
+
+// storeBlocks stores random blocks in the DB, from a height up to another one
+// excluded, and returns them
+func storeBlocks(t *testing.T, db database.DB, from, to uint64) []block.Block {
+	blocks := make([]block.Block, 0, to-from)
+	err := db.Update(func(tr database.Transaction) error {
+		for height := from; height < to; height++ {
+			blk := helper.RandomBlock(t, height, 1)
+			hash, err := blk.CalculateHash()
+			if err != nil {
+				return err
+			}
+			blk.Header.Hash = hash
+
+			if err := tr.StoreBlock(blk); err != nil {
+				return err
+			}
+			blocks = append(blocks, *blk)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return blocks
+}
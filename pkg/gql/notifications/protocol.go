@@ -0,0 +1,171 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/query"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// GraphQLWS is the websocket subprotocol of the GraphQL subscriptions. Clients
+// which do not request it are notified of the accepted blocks with BlockMsg
+const GraphQLWS = "graphql-ws"
+
+// Message types of the graphql-ws protocol
+const (
+	gqlConnectionInit      = "connection_init"
+	gqlConnectionAck       = "connection_ack"
+	gqlConnectionError     = "connection_error"
+	gqlConnectionKeepAlive = "ka"
+	gqlConnectionTerminate = "connection_terminate"
+	gqlStart               = "start"
+	gqlStop                = "stop"
+	gqlData                = "data"
+	gqlError               = "error"
+	gqlComplete            = "complete"
+)
+
+// maxSubscriptions is the maximum number of active subscriptions per client
+const maxSubscriptions = 20
+
+type (
+	// operationMessage is the envelope of all graphql-ws messages. The data
+	// messages are numbered with the sequence of the notifications of the
+	// client
+	operationMessage struct {
+		ID      string          `json:"id,omitempty"`
+		Type    string          `json:"type"`
+		Seq     uint64          `json:"seq,omitempty"`
+		Payload json.RawMessage `json:"payload,omitempty"`
+	}
+
+	// startPayload is the payload of a start message
+	startPayload struct {
+		Query         string                 `json:"query"`
+		Variables     map[string]interface{} `json:"variables,omitempty"`
+		OperationName string                 `json:"operationName,omitempty"`
+	}
+
+	// clientOperation is an operation message read from a client, handed
+	// over to the broker of the client
+	clientOperation struct {
+		client *wsClient
+		msg    operationMessage
+	}
+
+	// subscription is a subscription operation started by a client
+	subscription struct {
+		id      string
+		trigger query.Trigger
+		// key is the response key of the subscribed field
+		key       string
+		doc       *ast.Document
+		operation string
+		variables map[string]interface{}
+	}
+)
+
+// newSubscription parses and validates the start payload of a subscription.
+// The operation must be a subscription with a single root field
+func newSubscription(schema *graphql.Schema, id string, payload json.RawMessage) (*subscription, error) {
+	var p startPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("invalid start payload: %v", err)
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: p.Query})
+	if err != nil {
+		return nil, err
+	}
+
+	if result := graphql.ValidateDocument(schema, doc, nil); !result.IsValid {
+		return nil, result.Errors[0]
+	}
+
+	var op *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		d, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if p.OperationName == "" || (d.Name != nil && d.Name.Value == p.OperationName) {
+			if op != nil {
+				return nil, errors.New("operation name required")
+			}
+			op = d
+		}
+	}
+
+	if op == nil {
+		return nil, errors.New("operation not found")
+	}
+
+	if op.Operation != ast.OperationTypeSubscription {
+		return nil, fmt.Errorf("expected a subscription, got a %s", op.Operation)
+	}
+
+	selections := op.SelectionSet.Selections
+	if len(selections) != 1 {
+		return nil, errors.New("a subscription must select exactly one field")
+	}
+
+	field, ok := selections[0].(*ast.Field)
+	if !ok {
+		return nil, errors.New("a subscription must select a field")
+	}
+
+	key := field.Name.Value
+	if field.Alias != nil {
+		key = field.Alias.Value
+	}
+
+	return &subscription{
+		id:        id,
+		trigger:   query.Triggers[field.Name.Value],
+		key:       key,
+		doc:       doc,
+		operation: p.OperationName,
+		variables: p.Variables,
+	}, nil
+}
+
+// execute resolves the subscription against the payload of an eventbus
+// message. It returns nil if the subscribed field resolved to null, meaning
+// that the message did not match the subscription arguments
+func (s *subscription) execute(schema *graphql.Schema, payload interface{}) *graphql.Result {
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        *schema,
+		Root:          payload,
+		AST:           s.doc,
+		OperationName: s.operation,
+		Args:          s.variables,
+	})
+
+	if len(result.Errors) > 0 {
+		return result
+	}
+
+	if data, ok := result.Data.(map[string]interface{}); ok && data[s.key] == nil {
+		return nil
+	}
+
+	return result
+}
+
+// marshalOperation builds a graphql-ws message
+func marshalOperation(msg operationMessage, payload interface{}) ([]byte, error) {
+	if payload != nil {
+		p, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		msg.Payload = p
+	}
+
+	return json.Marshal(msg)
+}
@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/query"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSchema(t *testing.T) *graphql.Schema {
+	root := query.NewRoot(nil)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: root.Query, Subscription: root.Subscription})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &schema
+}
+
+func startPayloadOf(t *testing.T, q string) json.RawMessage {
+	p, err := json.Marshal(startPayload{Query: q})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestNewSubscription(t *testing.T) {
+	schema := newSchema(t)
+
+	sub, err := newSubscription(schema, "1", startPayloadOf(t, "subscription { progress: syncProgress }"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "progress", sub.key)
+	assert.Equal(t, topics.SyncProgress, sub.trigger.Topic)
+
+	sub, err = newSubscription(schema, "2", startPayloadOf(t, `subscription { txConfirmed(txid: "aa") { txid } }`))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.True(t, sub.trigger.Once)
+
+	invalid := []string{
+		"{ mempool { txid } }",
+		"subscription { syncProgress roundUpdate { round } }",
+		"subscription { unknown }",
+		"subscription { txConfirmed { txid } }",
+	}
+
+	for _, q := range invalid {
+		_, err := newSubscription(schema, "3", startPayloadOf(t, q))
+		assert.Error(t, err, q)
+	}
+}
+
+func TestExecuteSubscription(t *testing.T) {
+	schema := newSchema(t)
+	sub, err := newSubscription(schema, "1", startPayloadOf(t, "subscription { syncProgress(synced: true) }"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// The subscription is only notified once synced
+	assert.Nil(t, sub.execute(schema, float32(50)))
+
+	result := sub.execute(schema, float32(100))
+	if !assert.NotNil(t, result) {
+		t.FailNow()
+	}
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{"syncProgress": float64(100)}, result.Data)
+}
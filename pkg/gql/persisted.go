@@ -0,0 +1,102 @@
+package gql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+var (
+	errPersistedQueryNotFound = errors.New("PersistedQueryNotFound")
+	errQueryNotPersisted      = errors.New("only persisted queries are allowed")
+)
+
+type (
+	// persistedQuery is the extension of a request referring to a persisted
+	// query by its hash, as with Apollo persisted queries
+	persistedQuery struct {
+		Version    int    `json:"version"`
+		Sha256Hash string `json:"sha256Hash"`
+	}
+
+	extensions struct {
+		PersistedQuery *persistedQuery `json:"persistedQuery,omitempty"`
+	}
+
+	// persistedQueries is the allow-list of queries, indexed by the
+	// hex-encoded SHA-256 hash of the query
+	persistedQueries struct {
+		queries map[string]string
+		// only rejects the queries which are not persisted
+		only bool
+	}
+)
+
+// loadPersistedQueries reads a JSON file mapping the hashes of the queries to
+// the queries. The hashes are checked against the queries
+func loadPersistedQueries(path string, only bool) (*persistedQueries, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries map[string]string
+	if err := json.Unmarshal(content, &queries); err != nil {
+		return nil, fmt.Errorf("invalid persisted queries file: %v", err)
+	}
+
+	return newPersistedQueries(queries, only)
+}
+
+func newPersistedQueries(queries map[string]string, only bool) (*persistedQueries, error) {
+	p := &persistedQueries{queries: make(map[string]string, len(queries)), only: only}
+	for hash, query := range queries {
+		hash = strings.ToLower(hash)
+		if hashQuery(query) != hash {
+			return nil, fmt.Errorf("hash %s does not match its persisted query", hash)
+		}
+
+		p.queries[hash] = query
+	}
+
+	return p, nil
+}
+
+// lookup returns the query of a request, which is either sent along or
+// referred to by its hash. A nil persistedQueries only accepts the queries sent
+// along
+func (p *persistedQueries) lookup(query string, ext extensions) (string, error) {
+	if p == nil {
+		if len(query) == 0 && ext.PersistedQuery != nil {
+			return "", errPersistedQueryNotFound
+		}
+
+		return query, nil
+	}
+
+	if ext.PersistedQuery != nil {
+		persisted, ok := p.queries[strings.ToLower(ext.PersistedQuery.Sha256Hash)]
+		if !ok {
+			return "", errPersistedQueryNotFound
+		}
+
+		return persisted, nil
+	}
+
+	if p.only {
+		if _, ok := p.queries[hashQuery(query)]; !ok {
+			return "", errQueryNotPersisted
+		}
+	}
+
+	return query, nil
+}
+
+func hashQuery(query string) string {
+	hash := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(hash[:])
+}
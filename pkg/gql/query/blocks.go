@@ -50,33 +50,73 @@ func (b blocks) getQuery() *graphql.Field {
 		Resolve: b.resolve,
 	}
 }
+
+// getConnectionQuery returns the blocks in increasing height order, as a
+// Relay-style connection
+func (b blocks) getConnectionQuery() *graphql.Field {
+	return &graphql.Field{
+		Type: BlockConnection,
+		Args: connectionArgs(graphql.FieldConfigArgument{
+			fromHeightArg: &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			toHeightArg: &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+		}),
+		Resolve: b.resolveConnection,
+	}
+}
+
 func (b blocks) resolve(p graphql.ResolveParams) (interface{}, error) {
 
-	// Retrieve DB conn from context
-	db, ok := p.Context.Value("database").(database.DB)
-	if !ok {
-		return nil, errors.New("context does not store database conn")
+	// Retrieve the loader of the request
+	l, err := loaderOf(p)
+	if err != nil {
+		return nil, err
+	}
+
+	blks, err := b.fetch(p, l)
+	if err != nil || blks == nil {
+		return nil, err
+	}
+
+	// Loading the txs of all blocks at once
+	if selects(p, "transactions") {
+		hashes := make([][]byte, len(blks))
+		for i, blk := range blks {
+			hashes[i] = blk.Header.Hash
+		}
+
+		if _, err := l.BlockTxs(hashes); err != nil {
+			return nil, err
+		}
 	}
 
+	return blks, nil
+}
+
+func (b blocks) fetch(p graphql.ResolveParams, l *Loader) ([]*block.Block, error) {
+
 	// resolve argument hash (single block)
 	hash, ok := p.Args[blockHashArg].(interface{})
 	if ok {
 		hashes := make([]interface{}, 0)
 		hashes = append(hashes, hash)
-		return b.fetchBlocksByHashes(db, hashes)
+		return b.fetchBlocksByHashes(l, hashes)
 	}
 
 	// resolve argument hashes (multiple blocks)
 	hashes, ok := p.Args[blockHashesArg].([]interface{})
 	if ok {
-		return b.fetchBlocksByHashes(db, hashes)
+		return b.fetchBlocksByHashes(l, hashes)
 	}
 
 	// resolve argument height (single block)
 	// Chain height type is uint64 whereas `resolve` can handle height values up to MaxUInt
 	height, ok := p.Args[blockHeightArg].(int)
 	if ok {
-		return b.fetchBlocksByHeights(db, int64(height), int64(height))
+		return b.fetchBlocksByHeights(l, int64(height), int64(height))
 	}
 
 	// resolve argument range (range of blocks)
@@ -92,7 +132,7 @@ func (b blocks) resolve(p graphql.ResolveParams) (interface{}, error) {
 			return nil, errors.New("range `to` value not int64")
 		}
 
-		return b.fetchBlocksByHeights(db, int64(from), int64(to))
+		return b.fetchBlocksByHeights(l, int64(from), int64(to))
 	}
 
 	offset, ok := p.Args[blockLastArg].(int)
@@ -100,12 +140,12 @@ func (b blocks) resolve(p graphql.ResolveParams) (interface{}, error) {
 		if offset <= 0 {
 			return nil, errors.New("invalid offset")
 		}
-		return b.fetchBlocksByHeights(db, int64(offset)*-1, -1)
+		return b.fetchBlocksByHeights(l, int64(offset)*-1, -1)
 	}
 
 	date, ok := p.Args[blockSinceArg].(time.Time)
 	if ok {
-		return b.fetchBlocksByDate(db, date)
+		return b.fetchBlocksByDate(l.db, date)
 	}
 
 	return nil, nil
@@ -117,121 +157,215 @@ func resolveTxs(p graphql.ResolveParams) (interface{}, error) {
 	b, ok := p.Source.(*block.Block)
 	if ok {
 
-		// Retrieve DB conn from context
-		db, ok := p.Context.Value("database").(database.DB)
-		if !ok {
-			return nil, errors.New("context does not store database conn")
-		}
-
-		err := db.View(func(t database.Transaction) error {
-			fetched, err := t.FetchBlockTxs(b.Header.Hash)
-			if err != nil {
-				return err
-			}
-
-			for _, tx := range fetched {
+		// Blocks notified to subscriptions come along with their txs
+		if len(b.Txs) > 0 {
+			for _, tx := range b.Txs {
 				d, err := newQueryTx(tx, b.Header.Hash)
 				if err == nil {
 					txs = append(txs, d)
 				}
 			}
-			return nil
-		})
+			return txs, nil
+		}
+
+		// Retrieve the loader of the request
+		l, err := loaderOf(p)
+		if err != nil {
+			return nil, err
+		}
+
+		fetched, err := l.BlockTxs([][]byte{b.Header.Hash})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tx := range fetched[0] {
+			d, err := newQueryTx(tx, b.Header.Hash)
+			if err == nil {
+				txs = append(txs, d)
+			}
+		}
 
-		return txs, err
+		return txs, nil
 	}
 	return nil, errors.New("invalid source block")
 }
 
-// Fetch block headers by a list of hashes
-func (b blocks) fetchBlocksByHashes(db database.DB, hashes []interface{}) ([]*block.Block, error) {
+func (b blocks) resolveConnection(p graphql.ResolveParams) (interface{}, error) {
 
-	blocks := make([]*block.Block, 0)
-	err := db.View(func(t database.Transaction) error {
-		for _, v := range hashes {
-			encodedHash, ok := v.(string)
-			if !ok {
-				continue
-			}
+	// Retrieve the loader of the request
+	l, err := loaderOf(p)
+	if err != nil {
+		return nil, err
+	}
 
-			hash, err := hex.DecodeString(encodedHash)
-			if err != nil {
-				continue
-			}
+	pg, err := newPage(p, "block", 1)
+	if err != nil {
+		return nil, err
+	}
 
-			header, err := t.FetchBlockHeader(hash)
-			if err != nil {
-				continue
-			}
+	tip, err := l.Tip()
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := heightRange(p, tip)
+	if err != nil {
+		return nil, err
+	}
+
+	var c queryConnection
+	err = func() error {
+
+		total := 0
+		if from <= to {
+			total = int(to - from + 1)
+		}
+		c.count = func() (int, error) {
+			return total, nil
+		}
 
-			// Reconstructing block with header only
-			b := &block.Block{
-				Header: header,
-				Txs:    nil,
+		// narrowing the range down to the page
+		hasNext, hasPrevious := false, false
+		if pg.backward {
+			if pg.cursor != nil && pg.cursor[0] <= to {
+				hasNext = true
+				if pg.cursor[0] == 0 {
+					// nothing comes before the genesis block
+					c.PageInfo = newPageInfo(nil, hasNext, hasPrevious)
+					return nil
+				}
+				to = pg.cursor[0] - 1
+			}
+			if to >= from && to-from >= uint64(pg.size) {
+				from = to - uint64(pg.size) + 1
+				hasPrevious = true
+			}
+		} else {
+			if pg.cursor != nil && pg.cursor[0] >= from {
+				from = pg.cursor[0] + 1
+				hasPrevious = true
+			}
+			if to >= from && to-from >= uint64(pg.size) {
+				to = from + uint64(pg.size) - 1
+				hasNext = true
 			}
+		}
 
-			blocks = append(blocks, b)
+		heights := make([]uint64, 0, pg.size)
+		for height := from; from <= to && height <= to; height++ {
+			heights = append(heights, height)
 		}
-		return nil
-	})
 
-	return blocks, err
-}
+		headers, err := l.HeadersByHeight(heights)
+		if err != nil {
+			return err
+		}
 
-// Fetch block headers by a range of heights
-func (b blocks) fetchBlocksByHeights(db database.DB, from, to int64) ([]*block.Block, error) {
+		hashes := make([][]byte, len(headers))
+		for i, header := range headers {
+			hashes[i] = header.Hash
+			c.Edges = append(c.Edges, queryEdge{
+				Cursor: encodeCursor("block", header.Height),
+				Node:   &block.Block{Header: header},
+			})
+		}
 
-	blocks := make([]*block.Block, 0)
-	err := db.View(func(t database.Transaction) error {
+		c.PageInfo = newPageInfo(c.Edges, hasNext, hasPrevious)
 
-		var tip uint64
-		if from < 0 || to < 0 {
-			var err error
-			tip, err = t.FetchCurrentHeight()
-			if err != nil {
-				return err
-			}
+		// Loading the txs of all blocks at once
+		if selects(p, "edges", "node", "transactions") {
+			_, err = l.BlockTxs(hashes)
 		}
+		return err
+	}()
+
+	return c, err
+}
 
-		if from == -1 {
-			from = int64(tip)
+// Fetch block headers by a list of hashes
+func (b blocks) fetchBlocksByHashes(l *Loader, hashes []interface{}) ([]*block.Block, error) {
+
+	decoded := make([][]byte, 0, len(hashes))
+	for _, v := range hashes {
+		encodedHash, ok := v.(string)
+		if !ok {
+			continue
 		}
 
-		// For cases where last N blocks are required
+		hash, err := hex.DecodeString(encodedHash)
+		if err != nil {
+			continue
+		}
+
+		decoded = append(decoded, hash)
+	}
+
+	headers, err := l.HeadersByHash(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return headersToBlocks(headers), nil
+}
+
+// Fetch block headers by a range of heights
+func (b blocks) fetchBlocksByHeights(l *Loader, from, to int64) ([]*block.Block, error) {
+
+	tip, err := l.Tip()
+	if err != nil {
+		return nil, err
+	}
+
+	if from == -1 {
+		from = int64(tip)
+	}
+
+	// For cases where last N blocks are required
+	if from < 0 {
+		from = int64(tip) + from + 1
 		if from < 0 {
-			from = int64(tip) + from + 1
-			if from < 0 {
-				from = 0
-			}
+			from = 0
 		}
+	}
+
+	if to == -1 {
+		to = int64(tip)
+	}
 
-		if to == -1 {
-			to = int64(tip)
+	// Heights above the tip fail to be fetched. Only the first one is looked
+	// up, rather than the whole range
+	if to > int64(tip) {
+		to = int64(tip) + 1
+		if from > to {
+			to = from
 		}
+	}
 
-		for height := from; height <= to; height++ {
-			hash, err := t.FetchBlockHashByHeight(uint64(height))
-			if err != nil {
-				return err
-			}
+	heights := make([]uint64, 0)
+	for height := from; height <= to; height++ {
+		heights = append(heights, uint64(height))
+	}
 
-			header, err := t.FetchBlockHeader(hash)
-			if err != nil {
-				continue
-			}
+	headers, err := l.HeadersByHeight(heights)
+	if err != nil {
+		return nil, err
+	}
 
-			// Reconstructing block with header only
-			b := &block.Block{
-				Header: header,
-				Txs:    nil,
-			}
+	return headersToBlocks(headers), nil
+}
 
-			blocks = append(blocks, b)
+// headersToBlocks reconstructs the blocks with their header only
+func headersToBlocks(headers []*block.Header) []*block.Block {
+	blocks := make([]*block.Block, len(headers))
+	for i, header := range headers {
+		blocks[i] = &block.Block{
+			Header: header,
+			Txs:    nil,
 		}
-		return nil
-	})
+	}
 
-	return blocks, err
+	return blocks
 }
 
 // Fetch block headers by a range of heights
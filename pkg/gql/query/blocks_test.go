@@ -281,3 +281,105 @@ func TestBlocksByDate(t *testing.T) {
 	`
 	assertQuery(t, query, response)
 }
+
+func TestBlocksConnection(t *testing.T) {
+	query := `
+		{
+		  first: blocksConnection(first: 2) {
+			edges {
+			  cursor
+			  node {
+				header {
+				  height
+				}
+			  }
+			}
+			pageInfo {
+			  hasNextPage
+			  hasPreviousPage
+			  endCursor
+			}
+			totalCount
+		  },
+		  next: blocksConnection(first: 2, after: "YmxvY2s6MQ==") {
+			edges {
+			  node {
+				header {
+				  height
+				}
+			  }
+			}
+			pageInfo {
+			  hasNextPage
+			  hasPreviousPage
+			}
+		  },
+		  previous: blocksConnection(last: 1, before: "YmxvY2s6Mg==", fromheight: 1) {
+			edges {
+			  node {
+				header {
+				  height
+				}
+			  }
+			}
+			pageInfo {
+			  hasNextPage
+			  hasPreviousPage
+			}
+			totalCount
+		  }
+		}
+		`
+	response := `
+		{
+		  "data": {
+			"first": {
+			  "edges": [
+				{"cursor": "YmxvY2s6MA==", "node": {"header": {"height": 0}}},
+				{"cursor": "YmxvY2s6MQ==", "node": {"header": {"height": 1}}}
+			  ],
+			  "pageInfo": {
+				"hasNextPage": true,
+				"hasPreviousPage": false,
+				"endCursor": "YmxvY2s6MQ=="
+			  },
+			  "totalCount": 3
+			},
+			"next": {
+			  "edges": [
+				{"node": {"header": {"height": 2}}}
+			  ],
+			  "pageInfo": {
+				"hasNextPage": false,
+				"hasPreviousPage": true
+			  }
+			},
+			"previous": {
+			  "edges": [
+				{"node": {"header": {"height": 1}}}
+			  ],
+			  "pageInfo": {
+				"hasNextPage": true,
+				"hasPreviousPage": false
+			  },
+			  "totalCount": 2
+			}
+		  }
+		}
+	`
+	assertQuery(t, query, response)
+}
+
+func TestBlocksConnectionInvalidArgs(t *testing.T) {
+	queries := []string{
+		`{ blocksConnection(first: 1, last: 1) { totalCount } }`,
+		`{ blocksConnection(first: 101) { totalCount } }`,
+		`{ blocksConnection(after: "dHg6MTow") { totalCount } }`,
+	}
+
+	for _, query := range queries {
+		if result := execute(query, sc, db); len(result.Errors) == 0 {
+			t.Errorf("expected an error for %s", query)
+		}
+	}
+}
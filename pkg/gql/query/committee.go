@@ -0,0 +1,143 @@
+package query
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	committeeRoundArg  = "round"
+	committeeStepArg   = "step"
+	committeePubKeyArg = "pubkey"
+
+	selectionRoundsArg   = "rounds"
+	selectionLastStepArg = "laststep"
+)
+
+// File purpose is to define all arguments and resolvers relevant to the
+// committee inspection queries
+
+type (
+	// queryCommittee wraps the committee of a round and step, along with the
+	// membership of the provisioner the query was about
+	queryCommittee struct {
+		Round       uint64
+		Step        uint8
+		Size        int
+		TotalWeight uint64
+		Members     []user.CommitteeMember
+		// IsMember and Votes are only set if a public key was queried
+		IsMember bool
+		Votes    int
+	}
+
+	committee struct {
+		rpcBus *rpcbus.RPCBus
+	}
+)
+
+// getQuery returns the committee of a round and step
+func (c committee) getQuery() *graphql.Field {
+	return &graphql.Field{
+		Type: Committee,
+		Args: graphql.FieldConfigArgument{
+			committeeRoundArg: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			committeeStepArg: &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 2,
+			},
+			committeePubKeyArg: &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+		},
+		Resolve: c.resolve,
+	}
+}
+
+// getNextSelectionQuery returns the next round and step in which the node is
+// extracted in a committee
+func (c committee) getNextSelectionQuery() *graphql.Field {
+	return &graphql.Field{
+		Type: Selection,
+		Args: graphql.FieldConfigArgument{
+			selectionRoundsArg: &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 100,
+			},
+			selectionLastStepArg: &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 3,
+			},
+		},
+		Resolve: c.resolveNextSelection,
+	}
+}
+
+func (c committee) resolve(p graphql.ResolveParams) (interface{}, error) {
+	round, ok := p.Args[committeeRoundArg].(int)
+	if !ok || round < 0 {
+		return nil, errors.New("invalid round")
+	}
+
+	step, ok := p.Args[committeeStepArg].(int)
+	if !ok || step < 0 || step > 255 {
+		return nil, errors.New("invalid step")
+	}
+
+	req := consensus.CommitteeRequest{Round: uint64(round), Step: uint8(step)}
+	resp, err := c.rpcBus.Call(topics.GetCommittee, rpcbus.NewRequest(req), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	info := resp.(user.CommitteeInfo)
+	qc := queryCommittee{
+		Round:       info.Round,
+		Step:        info.Step,
+		Size:        info.Size,
+		TotalWeight: info.TotalWeight,
+		Members:     info.Members,
+	}
+
+	if encoded, ok := p.Args[committeePubKeyArg].(string); ok {
+		pubKeyBLS, err := hex.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		qc.Votes = info.VotesOf(pubKeyBLS)
+		qc.IsMember = qc.Votes > 0
+	}
+
+	return qc, nil
+}
+
+func (c committee) resolveNextSelection(p graphql.ResolveParams) (interface{}, error) {
+	rounds, ok := p.Args[selectionRoundsArg].(int)
+	if !ok || rounds <= 0 || rounds > consensus.MaxSelectionRounds {
+		return nil, fmt.Errorf("the amount of rounds must be between 1 and %d", consensus.MaxSelectionRounds)
+	}
+
+	lastStep, ok := p.Args[selectionLastStepArg].(int)
+	if !ok || lastStep < 0 || lastStep > 255 {
+		return nil, errors.New("invalid step")
+	}
+
+	req := consensus.SelectionRequest{Rounds: uint64(rounds), LastStep: uint8(lastStep)}
+	resp, err := c.rpcBus.Call(topics.GetNextSelection, rpcbus.NewRequest(req), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(user.Selection), nil
+}
@@ -0,0 +1,21 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextSelectionRounds(t *testing.T) {
+	c := committee{rpcBus: rpcbus.New()}
+	for _, rounds := range []int{0, consensus.MaxSelectionRounds + 1} {
+		_, err := c.resolveNextSelection(graphql.ResolveParams{Args: map[string]interface{}{
+			selectionRoundsArg:   rounds,
+			selectionLastStepArg: 3,
+		}})
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,254 @@
+package query
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	firstArg  = "first"
+	afterArg  = "after"
+	lastArg   = "last"
+	beforeArg = "before"
+
+	fromHeightArg = "fromheight"
+	toHeightArg   = "toheight"
+
+	// defaultPageSize is the amount of edges of a page when neither first nor
+	// last are given
+	defaultPageSize = 20
+	// maxPageSize is the maximum amount of edges of a page
+	maxPageSize = 100
+)
+
+// File purpose is to define the Relay-style connections, which allow paging
+// through the chain with opaque cursors
+
+type (
+	queryEdge struct {
+		Cursor string
+		Node   interface{}
+	}
+
+	queryPageInfo struct {
+		HasNextPage     bool
+		HasPreviousPage bool
+		StartCursor     string
+		EndCursor       string
+	}
+
+	// queryConnection is a page of edges. The total count is only computed
+	// if requested, as it can take a scan of the chain
+	queryConnection struct {
+		Edges    []queryEdge
+		PageInfo queryPageInfo
+		count    func() (int, error)
+	}
+
+	// page is the paging request of a connection query. Either forward, with
+	// first and after, or backward with last and before
+	page struct {
+		size     int
+		backward bool
+		// cursor is the decoded after or before argument, if any
+		cursor []uint64
+	}
+)
+
+var PageInfo = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+			"hasPreviousPage": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+			"startCursor": &graphql.Field{
+				Type: graphql.String,
+			},
+			"endCursor": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
+// newConnection creates the connection type of a node type
+func newConnection(node *graphql.Object) *graphql.Object {
+	edge := graphql.NewObject(
+		graphql.ObjectConfig{
+			Name: node.Name() + "Edge",
+			Fields: graphql.Fields{
+				"cursor": &graphql.Field{
+					Type: graphql.String,
+				},
+				"node": &graphql.Field{
+					Type: node,
+				},
+			},
+		},
+	)
+
+	return graphql.NewObject(
+		graphql.ObjectConfig{
+			Name: node.Name() + "Connection",
+			Fields: graphql.Fields{
+				"edges": &graphql.Field{
+					Type: graphql.NewList(edge),
+				},
+				"pageInfo": &graphql.Field{
+					Type: PageInfo,
+				},
+				"totalCount": &graphql.Field{
+					Type:    graphql.Int,
+					Resolve: resolveTotalCount,
+				},
+			},
+		},
+	)
+}
+
+// connectionArgs returns the paging arguments, along with the given filters
+func connectionArgs(filters graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		firstArg: &graphql.ArgumentConfig{
+			Type: graphql.Int,
+		},
+		afterArg: &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+		lastArg: &graphql.ArgumentConfig{
+			Type: graphql.Int,
+		},
+		beforeArg: &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+	}
+
+	for name, arg := range filters {
+		args[name] = arg
+	}
+
+	return args
+}
+
+func resolveTotalCount(p graphql.ResolveParams) (interface{}, error) {
+	c, ok := p.Source.(queryConnection)
+	if !ok {
+		return nil, errors.New("invalid source connection")
+	}
+
+	return c.count()
+}
+
+// newPage reads the paging arguments. The cursors are expected to be made of
+// the given amount of integers
+func newPage(p graphql.ResolveParams, kind string, cursorLen int) (page, error) {
+	first, hasFirst := p.Args[firstArg].(int)
+	last, hasLast := p.Args[lastArg].(int)
+	after, hasAfter := p.Args[afterArg].(string)
+	before, hasBefore := p.Args[beforeArg].(string)
+
+	if (hasFirst || hasAfter) && (hasLast || hasBefore) {
+		return page{}, errors.New("first and after cannot be combined with last and before")
+	}
+
+	pg := page{size: defaultPageSize, backward: hasLast || hasBefore}
+	switch {
+	case hasFirst:
+		pg.size = first
+	case hasLast:
+		pg.size = last
+	}
+
+	if pg.size <= 0 || pg.size > maxPageSize {
+		return page{}, fmt.Errorf("page size must be between 1 and %d", maxPageSize)
+	}
+
+	cursor := after
+	if pg.backward {
+		cursor = before
+	}
+
+	if cursor != "" {
+		var err error
+		if pg.cursor, err = decodeCursor(cursor, kind, cursorLen); err != nil {
+			return page{}, err
+		}
+	}
+
+	return pg, nil
+}
+
+// heightRange reads the height range filter, capped by the chain tip
+func heightRange(p graphql.ResolveParams, tip uint64) (uint64, uint64, error) {
+	from, to := uint64(0), tip
+	if h, ok := p.Args[fromHeightArg].(int); ok {
+		if h < 0 {
+			return 0, 0, errors.New("invalid fromheight")
+		}
+		from = uint64(h)
+	}
+
+	if h, ok := p.Args[toHeightArg].(int); ok {
+		if h < 0 {
+			return 0, 0, errors.New("invalid toheight")
+		}
+		if uint64(h) < to {
+			to = uint64(h)
+		}
+	}
+
+	return from, to, nil
+}
+
+// encodeCursor builds an opaque cursor out of a kind of node and its position
+func encodeCursor(kind string, position ...uint64) string {
+	parts := make([]string, len(position)+1)
+	parts[0] = kind
+	for i, n := range position {
+		parts[i+1] = strconv.FormatUint(n, 10)
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(strings.Join(parts, ":")))
+}
+
+func decodeCursor(cursor, kind string, length int) ([]uint64, error) {
+	errInvalid := errors.New("invalid cursor")
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalid
+	}
+
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != length+1 || parts[0] != kind {
+		return nil, errInvalid
+	}
+
+	position := make([]uint64, length)
+	for i := range position {
+		if position[i], err = strconv.ParseUint(parts[i+1], 10, 64); err != nil {
+			return nil, errInvalid
+		}
+	}
+
+	return position, nil
+}
+
+// newPageInfo fills in the cursors of a page of edges
+func newPageInfo(edges []queryEdge, hasNext, hasPrevious bool) queryPageInfo {
+	info := queryPageInfo{HasNextPage: hasNext, HasPreviousPage: hasPrevious}
+	if len(edges) > 0 {
+		info.StartCursor = edges[0].Cursor
+		info.EndCursor = edges[len(edges)-1].Cursor
+	}
+
+	return info
+}
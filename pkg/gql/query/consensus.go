@@ -0,0 +1,92 @@
+package query
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	roundsLastArg = "last"
+)
+
+// File purpose is to define all arguments and resolvers relevant to the
+// consensus round traces queries
+
+type roundTraces struct {
+	rpcBus *rpcbus.RPCBus
+}
+
+func (r roundTraces) args() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		roundsLastArg: &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			DefaultValue: 10,
+		},
+	}
+}
+
+// getQuery returns the traces of the last rounds
+func (r roundTraces) getQuery() *graphql.Field {
+	return &graphql.Field{
+		Type:    graphql.NewList(RoundTrace),
+		Args:    r.args(),
+		Resolve: r.resolve,
+	}
+}
+
+// getTimelineQuery returns the traces of the last rounds in the Trace Event
+// Format, to be loaded in chrome://tracing or Perfetto
+func (r roundTraces) getTimelineQuery() *graphql.Field {
+	return &graphql.Field{
+		Type:    graphql.String,
+		Args:    r.args(),
+		Resolve: r.resolveTimeline,
+	}
+}
+
+func (r roundTraces) resolve(p graphql.ResolveParams) (interface{}, error) {
+	return r.fetch(p)
+}
+
+func (r roundTraces) resolveTimeline(p graphql.ResolveParams) (interface{}, error) {
+	traces, err := r.fetch(p)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := consensus.WriteTraceEvents(buf, traces); err != nil {
+		return nil, err
+	}
+
+	return buf.String(), nil
+}
+
+func (r roundTraces) fetch(p graphql.ResolveParams) ([]consensus.RoundTrace, error) {
+	last, ok := p.Args[roundsLastArg].(int)
+	if !ok || last <= 0 {
+		return nil, errors.New("invalid amount of rounds")
+	}
+
+	resp, err := r.rpcBus.Call(topics.GetRoundTraces, rpcbus.NewRequest(last), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.([]consensus.RoundTrace), nil
+}
+
+func resolveTraceKind(p graphql.ResolveParams) (interface{}, error) {
+	e, ok := p.Source.(consensus.TraceEvent)
+	if !ok {
+		return nil, errors.New("source is not a trace event")
+	}
+
+	return string(e.Kind), nil
+}
@@ -0,0 +1,397 @@
+package query
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-wallet/v2/block"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+
+	core "github.com/dusk-network/dusk-wallet/v2/transactions"
+)
+
+// File purpose is to define the loading of the chain data by the resolvers.
+// The lookups of a request are coalesced into single read transactions and
+// memoized, while the recently accepted blocks are kept in memory
+
+type (
+	// Loader loads the chain data of a single request. The lookups are done
+	// in a single read transaction per call, and memoized for the lifetime of
+	// the request. It is safe for concurrent use
+	Loader struct {
+		db    database.DB
+		cache *BlockCache
+
+		lock    sync.Mutex
+		tip     *uint64
+		headers map[string]*block.Header
+		hashes  map[uint64][]byte
+		txs     map[string][]core.Transaction
+	}
+
+	// BlockCache is a LRU cache of the recently accepted blocks, shared by
+	// the requests. It is safe for concurrent use
+	BlockCache struct {
+		lock    sync.Mutex
+		size    int
+		lru     *list.List
+		blocks  map[string]*list.Element
+		heights map[uint64]string
+	}
+)
+
+// NewLoader creates the Loader of a request. The cache can be nil
+func NewLoader(db database.DB, cache *BlockCache) *Loader {
+	return &Loader{
+		db:      db,
+		cache:   cache,
+		headers: make(map[string]*block.Header),
+		hashes:  make(map[uint64][]byte),
+		txs:     make(map[string][]core.Transaction),
+	}
+}
+
+// loaderOf returns the Loader of a request, or a Loader of the DB of the
+// request if none was set
+func loaderOf(p graphql.ResolveParams) (*Loader, error) {
+	if l, ok := p.Context.Value("loader").(*Loader); ok {
+		return l, nil
+	}
+
+	db, ok := p.Context.Value("database").(database.DB)
+	if !ok {
+		return nil, errors.New("context does not store database conn")
+	}
+
+	return NewLoader(db, nil), nil
+}
+
+// Tip returns the height of the chain tip. It is fetched only once per
+// request, so that all resolvers see the same chain
+func (l *Loader) Tip() (uint64, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.tip != nil {
+		return *l.tip, nil
+	}
+
+	var tip uint64
+	err := l.db.View(func(t database.Transaction) error {
+		var err error
+		tip, err = t.FetchCurrentHeight()
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	l.tip = &tip
+	return tip, nil
+}
+
+// HeadersByHash returns the headers of the blocks with the given hashes. The
+// unknown blocks are skipped
+func (l *Loader) HeadersByHash(hashes [][]byte) ([]*block.Header, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	missing := make([][]byte, 0)
+	for _, hash := range hashes {
+		if !l.lookup(hash) {
+			missing = append(missing, hash)
+		}
+	}
+
+	if len(missing) > 0 {
+		err := l.db.View(func(t database.Transaction) error {
+			for _, hash := range missing {
+				header, err := t.FetchBlockHeader(hash)
+				if err != nil {
+					continue
+				}
+
+				l.headers[string(hash)] = header
+			}
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	headers := make([]*block.Header, 0, len(hashes))
+	for _, hash := range hashes {
+		if header, ok := l.headers[string(hash)]; ok {
+			headers = append(headers, header)
+		}
+	}
+
+	return headers, nil
+}
+
+// HeadersByHeight returns the headers of the blocks at the given heights. The
+// blocks whose header cannot be fetched are skipped
+func (l *Loader) HeadersByHeight(heights []uint64) ([]*block.Header, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	missing := make([]uint64, 0)
+	for _, height := range heights {
+		if !l.lookupHeight(height) {
+			missing = append(missing, height)
+		}
+	}
+
+	if len(missing) > 0 {
+		err := l.db.View(func(t database.Transaction) error {
+			for _, height := range missing {
+				hash, err := t.FetchBlockHashByHeight(height)
+				if err != nil {
+					return err
+				}
+
+				header, err := t.FetchBlockHeader(hash)
+				if err != nil {
+					continue
+				}
+
+				l.hashes[height] = hash
+				l.headers[string(hash)] = header
+			}
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	headers := make([]*block.Header, 0, len(heights))
+	for _, height := range heights {
+		if hash, ok := l.hashes[height]; ok {
+			headers = append(headers, l.headers[string(hash)])
+		}
+	}
+
+	return headers, nil
+}
+
+// BlockTxs returns the txs of the blocks with the given hashes
+func (l *Loader) BlockTxs(hashes [][]byte) ([][]core.Transaction, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	missing := make([][]byte, 0)
+	for _, hash := range hashes {
+		if _, ok := l.txs[string(hash)]; ok {
+			continue
+		}
+
+		if blk, ok := l.cache.ByHash(hash); ok {
+			l.txs[string(hash)] = blk.Txs
+			continue
+		}
+
+		missing = append(missing, hash)
+	}
+
+	if len(missing) > 0 {
+		err := l.db.View(func(t database.Transaction) error {
+			for _, hash := range missing {
+				txs, err := t.FetchBlockTxs(hash)
+				if err != nil {
+					return err
+				}
+
+				l.txs[string(hash)] = txs
+			}
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	txs := make([][]core.Transaction, len(hashes))
+	for i, hash := range hashes {
+		txs[i] = l.txs[string(hash)]
+	}
+
+	return txs, nil
+}
+
+// lookup checks whether the header of a block is memoized or cached
+func (l *Loader) lookup(hash []byte) bool {
+	if _, ok := l.headers[string(hash)]; ok {
+		return true
+	}
+
+	if blk, ok := l.cache.ByHash(hash); ok {
+		l.headers[string(hash)] = blk.Header
+		l.txs[string(hash)] = blk.Txs
+		return true
+	}
+
+	return false
+}
+
+// lookupHeight checks whether the header of the block at a height is
+// memoized or cached
+func (l *Loader) lookupHeight(height uint64) bool {
+	if _, ok := l.hashes[height]; ok {
+		return true
+	}
+
+	if blk, ok := l.cache.ByHeight(height); ok {
+		l.hashes[height] = blk.Header.Hash
+		l.headers[string(blk.Header.Hash)] = blk.Header
+		l.txs[string(blk.Header.Hash)] = blk.Txs
+		return true
+	}
+
+	return false
+}
+
+// NewBlockCache creates a BlockCache holding up to size blocks
+func NewBlockCache(size int) *BlockCache {
+	return &BlockCache{
+		size:    size,
+		lru:     list.New(),
+		blocks:  make(map[string]*list.Element),
+		heights: make(map[uint64]string),
+	}
+}
+
+// Listen fills the cache with the accepted blocks. It returns the id of the
+// subscription
+func (c *BlockCache) Listen(subscriber eventbus.Subscriber) uint32 {
+	return subscriber.Subscribe(topics.AcceptedBlock, eventbus.NewCallbackListener(c.onAcceptedBlock))
+}
+
+func (c *BlockCache) onAcceptedBlock(m message.Message) error {
+	blk, ok := m.Payload().(block.Block)
+	if !ok {
+		return errors.New("invalid accepted block")
+	}
+
+	c.Add(&blk)
+	return nil
+}
+
+// Add caches an accepted block. The blocks cached at the same height or above
+// are dropped, as they do not belong to the chain anymore
+func (c *BlockCache) Add(blk *block.Block) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for height, hash := range c.heights {
+		if height >= blk.Header.Height {
+			c.remove(c.blocks[hash])
+		}
+	}
+
+	c.blocks[string(blk.Header.Hash)] = c.lru.PushFront(blk)
+	c.heights[blk.Header.Height] = string(blk.Header.Hash)
+
+	if c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// ByHash returns the cached block with the given hash
+func (c *BlockCache) ByHash(hash []byte) (*block.Block, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.get(string(hash))
+}
+
+// ByHeight returns the cached block at the given height
+func (c *BlockCache) ByHeight(height uint64) (*block.Block, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	hash, ok := c.heights[height]
+	if !ok {
+		return nil, false
+	}
+
+	return c.get(hash)
+}
+
+func (c *BlockCache) get(hash string) (*block.Block, bool) {
+	e, ok := c.blocks[hash]
+	if !ok {
+		return nil, false
+	}
+
+	c.lru.MoveToFront(e)
+	return e.Value.(*block.Block), true
+}
+
+func (c *BlockCache) remove(e *list.Element) {
+	blk := c.lru.Remove(e).(*block.Block)
+	delete(c.blocks, string(blk.Header.Hash))
+	delete(c.heights, blk.Header.Height)
+}
+
+// selects checks whether the field being resolved selects the given path of
+// sub-fields, so that the resolvers can load the data of their sub-fields in
+// a single batch
+func selects(p graphql.ResolveParams, path ...string) bool {
+	fields := p.Info.FieldASTs
+	for _, name := range path {
+		var next []*ast.Field
+		for _, f := range fields {
+			next = append(next, subFields(p.Info.Fragments, f.SelectionSet, name)...)
+		}
+
+		if len(next) == 0 {
+			return false
+		}
+		fields = next
+	}
+
+	return true
+}
+
+// subFields returns the fields of a selection set with the given name,
+// looking into the fragments
+func subFields(fragments map[string]ast.Definition, set *ast.SelectionSet, name string) []*ast.Field {
+	if set == nil {
+		return nil
+	}
+
+	var fields []*ast.Field
+	for _, selection := range set.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name.Value == name {
+				fields = append(fields, s)
+			}
+		case *ast.InlineFragment:
+			fields = append(fields, subFields(fragments, s.SelectionSet, name)...)
+		case *ast.FragmentSpread:
+			if fragment, ok := fragments[s.Name.Value].(*ast.FragmentDefinition); ok {
+				fields = append(fields, subFields(fragments, fragment.SelectionSet, name)...)
+			}
+		}
+	}
+
+	return fields
+}
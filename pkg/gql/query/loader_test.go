@@ -0,0 +1,134 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingDB counts the read transactions opened on the DB
+type countingDB struct {
+	database.DB
+	views int
+}
+
+func (c *countingDB) View(fn func(t database.Transaction) error) error {
+	c.views++
+	return c.DB.View(fn)
+}
+
+func TestLoaderBatching(t *testing.T) {
+	cdb := &countingDB{DB: db}
+	query := `
+		{
+		  blocks(range: [0, 2]) {
+			header {
+			   height
+			}
+			...txs
+		  }
+		}
+
+		fragment txs on Block {
+		  transactions {
+			txid
+		  }
+		}
+		`
+
+	result := graphql.Do(graphql.Params{
+		Schema:        sc,
+		RequestString: query,
+		Context:       context.WithValue(context.Background(), "loader", NewLoader(cdb, nil)),
+	})
+
+	if !assert.Empty(t, result.Errors) {
+		t.FailNow()
+	}
+
+	// The tip, the headers and the txs of the three blocks
+	assert.Equal(t, 3, cdb.views)
+	blocks := result.Data.(map[string]interface{})["blocks"].([]interface{})
+	assert.Len(t, blocks, 3)
+	for _, b := range blocks {
+		assert.Len(t, b.(map[string]interface{})["transactions"], 1)
+	}
+}
+
+func TestLoaderMemo(t *testing.T) {
+	cdb := &countingDB{DB: db}
+	l := NewLoader(cdb, nil)
+
+	headers, err := l.HeadersByHeight([]uint64{0, 1})
+	assert.NoError(t, err)
+	assert.Len(t, headers, 2)
+	assert.Equal(t, 1, cdb.views)
+
+	// Only the header at height 2 is fetched
+	headers, err = l.HeadersByHeight([]uint64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), headers[1].Height)
+	assert.Equal(t, 2, cdb.views)
+
+	// Headers fetched by height are known by hash
+	_, err = l.HeadersByHash([][]byte{headers[0].Hash, headers[1].Hash})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cdb.views)
+
+	// Heights above the tip fail
+	_, err = l.HeadersByHeight([]uint64{3})
+	assert.Error(t, err)
+}
+
+func TestLoaderCache(t *testing.T) {
+	cdb := &countingDB{DB: db}
+	cache := NewBlockCache(2)
+	blk := helper.RandomBlock(t, 5, 2)
+	cache.Add(blk)
+
+	l := NewLoader(cdb, cache)
+	headers, err := l.HeadersByHeight([]uint64{5})
+	assert.NoError(t, err)
+	assert.Equal(t, blk.Header, headers[0])
+
+	txs, err := l.BlockTxs([][]byte{blk.Header.Hash})
+	assert.NoError(t, err)
+	assert.Equal(t, blk.Txs, txs[0])
+	assert.Equal(t, 0, cdb.views)
+}
+
+func TestBlockCache(t *testing.T) {
+	cache := NewBlockCache(2)
+	b1 := helper.RandomBlock(t, 1, 1)
+	b2 := helper.RandomBlock(t, 2, 1)
+	b3 := helper.RandomBlock(t, 3, 1)
+
+	cache.Add(b1)
+	cache.Add(b2)
+
+	// Using b1 makes b2 the least recently used block
+	_, ok := cache.ByHeight(1)
+	assert.True(t, ok)
+
+	cache.Add(b3)
+	_, ok = cache.ByHash(b2.Header.Hash)
+	assert.False(t, ok)
+	_, ok = cache.ByHash(b1.Header.Hash)
+	assert.True(t, ok)
+
+	// A block accepted at a lower height replaces the blocks above it
+	fork := helper.RandomBlock(t, 1, 1)
+	cache.Add(fork)
+	_, ok = cache.ByHash(b3.Header.Hash)
+	assert.False(t, ok)
+	_, ok = cache.ByHash(b1.Header.Hash)
+	assert.False(t, ok)
+
+	cached, ok := cache.ByHeight(1)
+	assert.True(t, ok)
+	assert.Equal(t, fork, cached)
+}
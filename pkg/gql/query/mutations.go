@@ -0,0 +1,219 @@
+package query
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/transactor"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/auth"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-protobuf/autogen/go/node"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	rawTxArg    = "rawtx"
+	amountArg   = "amount"
+	addressArg  = "address"
+	lockTimeArg = "locktime"
+
+	// mutationTimeout is how long a mutation waits for the node to process it
+	mutationTimeout = 5 * time.Second
+)
+
+// Error codes of the mutations
+const (
+	CodeOK                 = "OK"
+	CodeInvalidArgument    = "INVALID_ARGUMENT"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeUnavailable        = "UNAVAILABLE"
+	CodeAlreadyExists      = "ALREADY_EXISTS"
+	CodeFeeTooLow          = "FEE_TOO_LOW"
+	CodeCoinbaseNotAllowed = "COINBASE_NOT_ALLOWED"
+	CodeWalletNotLoaded    = "WALLET_NOT_LOADED"
+	CodeRejected           = "REJECTED"
+)
+
+// File purpose is to define the fields of the Mutation root. Failures are not
+// reported as graphql errors but with the code and message of the result, so
+// that clients can tell the failures apart.
+
+type (
+	// mutationResult is the result of all mutations
+	mutationResult struct {
+		TxID    []byte
+		Code    string
+		Message string
+	}
+
+	mutations struct {
+		rpcBus *rpcbus.RPCBus
+	}
+)
+
+// getSubmitTransaction returns the mutation submitting a marshaled tx to the
+// mempool
+func (m mutations) getSubmitTransaction() *graphql.Field {
+	return &graphql.Field{
+		Type: MutationResult,
+		Args: graphql.FieldConfigArgument{
+			rawTxArg: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: m.resolveSubmitTransaction,
+	}
+}
+
+// getTransfer returns the wallet mutation sending DUSK to an address
+func (m mutations) getTransfer() *graphql.Field {
+	return &graphql.Field{
+		Type: MutationResult,
+		Args: graphql.FieldConfigArgument{
+			amountArg: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			addressArg: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: m.resolveTransfer,
+	}
+}
+
+// getConsensusTx returns the wallet mutation sending a bid or stake tx
+func (m mutations) getConsensusTx(topic topics.Topic) *graphql.Field {
+	return &graphql.Field{
+		Type: MutationResult,
+		Args: graphql.FieldConfigArgument{
+			amountArg: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			lockTimeArg: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return m.resolveConsensusTx(p, topic)
+		},
+	}
+}
+
+func (m mutations) resolveSubmitTransaction(p graphql.ResolveParams) (interface{}, error) {
+	if !authorized(p, auth.ScopeMempool) {
+		return failure(CodeUnauthorized, errors.New("submitting transactions requires the mempool scope")), nil
+	}
+
+	raw, err := hex.DecodeString(p.Args[rawTxArg].(string))
+	if err != nil {
+		return failure(CodeInvalidArgument, errors.New("invalid rawtx")), nil
+	}
+
+	tx, err := message.UnmarshalTx(bytes.NewBuffer(raw))
+	if err != nil {
+		return failure(CodeInvalidArgument, err), nil
+	}
+
+	resp, err := m.rpcBus.Call(topics.SendMempoolTx, rpcbus.NewRequest(tx), mutationTimeout)
+	if err != nil {
+		return failure(mempoolCode(err), err), nil
+	}
+
+	return mutationResult{TxID: resp.([]byte), Code: CodeOK}, nil
+}
+
+func (m mutations) resolveTransfer(p graphql.ResolveParams) (interface{}, error) {
+	if !authorized(p, auth.ScopeWallet) {
+		return failure(CodeUnauthorized, errors.New("wallet mutations require the wallet scope")), nil
+	}
+
+	amount, err := parseAmount(p)
+	if err != nil {
+		return failure(CodeInvalidArgument, err), nil
+	}
+
+	req := &node.TransferRequest{Amount: amount, Address: []byte(p.Args[addressArg].(string))}
+	return m.callWallet(topics.SendStandardTx, req), nil
+}
+
+func (m mutations) resolveConsensusTx(p graphql.ResolveParams, topic topics.Topic) (interface{}, error) {
+	if !authorized(p, auth.ScopeWallet) {
+		return failure(CodeUnauthorized, errors.New("wallet mutations require the wallet scope")), nil
+	}
+
+	amount, err := parseAmount(p)
+	if err != nil {
+		return failure(CodeInvalidArgument, err), nil
+	}
+
+	lockTime, ok := p.Args[lockTimeArg].(int)
+	if !ok || lockTime <= 0 {
+		return failure(CodeInvalidArgument, errors.New("invalid locktime")), nil
+	}
+
+	req := &node.ConsensusTxRequest{Amount: amount, LockTime: uint64(lockTime)}
+	return m.callWallet(topic, req), nil
+}
+
+// callWallet forwards a request to the transactor
+func (m mutations) callWallet(topic topics.Topic, req interface{}) mutationResult {
+	resp, err := m.rpcBus.Call(topic, rpcbus.NewRequest(req), mutationTimeout)
+	if err != nil {
+		return failure(walletCode(err), err)
+	}
+
+	return mutationResult{TxID: resp.(*node.TransferResponse).Hash, Code: CodeOK}
+}
+
+// authorized checks the scopes granted to the request by the http handler
+func authorized(p graphql.ResolveParams, scope auth.Scope) bool {
+	return auth.FromContext(p.Context).Scopes.Has(scope)
+}
+
+// parseAmount parses the amount argument, in atomic units
+func parseAmount(p graphql.ResolveParams) (uint64, error) {
+	amount, err := strconv.ParseUint(p.Args[amountArg].(string), 10, 64)
+	if err != nil || amount == 0 {
+		return 0, errors.New("invalid amount")
+	}
+
+	return amount, nil
+}
+
+func failure(code string, err error) mutationResult {
+	return mutationResult{Code: code, Message: err.Error()}
+}
+
+// mempoolCode returns the code of an error returned by the mempool
+func mempoolCode(err error) string {
+	switch err {
+	case rpcbus.ErrRequestTimeout, rpcbus.ErrMethodNotExists:
+		return CodeUnavailable
+	case mempool.ErrAlreadyExists:
+		return CodeAlreadyExists
+	case mempool.ErrReplacementFeeTooLow:
+		return CodeFeeTooLow
+	case mempool.ErrCoinbaseTxNotAllowed:
+		return CodeCoinbaseNotAllowed
+	default:
+		return CodeRejected
+	}
+}
+
+// walletCode returns the code of an error returned by the transactor
+func walletCode(err error) string {
+	switch err {
+	case rpcbus.ErrRequestTimeout, rpcbus.ErrMethodNotExists:
+		return CodeUnavailable
+	case transactor.ErrWalletNotLoaded:
+		return CodeWalletNotLoaded
+	default:
+		return mempoolCode(err)
+	}
+}
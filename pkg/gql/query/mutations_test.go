@@ -0,0 +1,116 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/transactor"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/auth"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveRPC answers all the requests of a rpcbus method with the same response
+func serveRPC(t *testing.T, rb *rpcbus.RPCBus, topic topics.Topic, resp interface{}, err error) {
+	reqChan := make(chan rpcbus.Request, 1)
+	if e := rb.Register(topic, reqChan); e != nil {
+		t.Fatal(e)
+	}
+
+	go func() {
+		for r := range reqChan {
+			r.RespChan <- rpcbus.Response{Resp: resp, Err: err}
+		}
+	}()
+}
+
+func execMutation(t *testing.T, rb *rpcbus.RPCBus, scopes auth.Scopes, mutation string) map[string]interface{} {
+	root := NewRoot(rb)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: root.Query, Mutation: root.Mutation})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: mutation,
+		Context:       auth.WithIdentity(context.Background(), auth.Identity{Scopes: scopes}),
+	})
+
+	if !assert.Empty(t, result.Errors) {
+		t.FailNow()
+	}
+
+	out, err := json.Marshal(result.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(out, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+var mempoolScope = auth.NewScopes(auth.ScopeMempool)
+
+func TestSubmitTransaction(t *testing.T) {
+	tx := helper.RandomStandardTx(t, false)
+	buf := new(bytes.Buffer)
+	if err := message.MarshalTx(buf, tx); err != nil {
+		t.Fatal(err)
+	}
+	rawTx := hex.EncodeToString(buf.Bytes())
+
+	rb := rpcbus.New()
+	serveRPC(t, rb, topics.SendMempoolTx, []byte{1, 2, 3}, nil)
+
+	data := execMutation(t, rb, mempoolScope, `mutation { submitTransaction(rawtx: "`+rawTx+`") { txid code } }`)
+	assert.Equal(t, map[string]interface{}{"txid": "010203", "code": CodeOK}, data["submitTransaction"])
+
+	// Submitting needs the mempool scope
+	data = execMutation(t, rb, auth.NewScopes(auth.ScopeChain), `mutation { submitTransaction(rawtx: "`+rawTx+`") { code } }`)
+	assert.Equal(t, map[string]interface{}{"code": CodeUnauthorized}, data["submitTransaction"])
+
+	// An invalid tx is not submitted
+	data = execMutation(t, rb, mempoolScope, `mutation { submitTransaction(rawtx: "zz") { code } }`)
+	assert.Equal(t, map[string]interface{}{"code": CodeInvalidArgument}, data["submitTransaction"])
+
+	// Errors of the mempool come with their own code
+	rb = rpcbus.New()
+	serveRPC(t, rb, topics.SendMempoolTx, nil, mempool.ErrAlreadyExists)
+
+	data = execMutation(t, rb, mempoolScope, `mutation { submitTransaction(rawtx: "`+rawTx+`") { code message } }`)
+	assert.Equal(t, map[string]interface{}{"code": CodeAlreadyExists, "message": mempool.ErrAlreadyExists.Error()}, data["submitTransaction"])
+}
+
+func TestWalletMutations(t *testing.T) {
+	rb := rpcbus.New()
+	serveRPC(t, rb, topics.SendStakeTx, nil, transactor.ErrWalletNotLoaded)
+
+	mutation := `mutation { stake(amount: "1000", locktime: 250000) { code } }`
+
+	// Wallet mutations need the wallet scope
+	data := execMutation(t, rb, mempoolScope, mutation)
+	assert.Equal(t, map[string]interface{}{"code": CodeUnauthorized}, data["stake"])
+
+	data = execMutation(t, rb, auth.AllScopes(), mutation)
+	assert.Equal(t, map[string]interface{}{"code": CodeWalletNotLoaded}, data["stake"])
+
+	data = execMutation(t, rb, auth.AllScopes(), `mutation { stake(amount: "-1", locktime: 250000) { code } }`)
+	assert.Equal(t, map[string]interface{}{"code": CodeInvalidArgument}, data["stake"])
+
+	// The transactor not running
+	data = execMutation(t, rb, auth.AllScopes(), `mutation { transfer(amount: "1000", address: "addr") { code } }`)
+	assert.Equal(t, map[string]interface{}{"code": CodeUnavailable}, data["transfer"])
+}
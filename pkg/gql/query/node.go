@@ -0,0 +1,269 @@
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-protobuf/autogen/go/node"
+	"github.com/dusk-network/dusk-wallet/v2/block"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	nodeRoundArg  = "round"
+	nodeHeightArg = "height"
+)
+
+// File purpose is to define all arguments and resolvers relevant to the
+// status of the node, and to the consensus state it keeps track of
+
+type (
+	queryNode struct {
+		Version      string
+		Network      string
+		Peers        int
+		SyncProgress float32
+	}
+
+	// queryProvisioner is a provisioner along with its stake. The stake is
+	// the one active at the queried round, if any, or the total one
+	queryProvisioner struct {
+		PubKeyBLS []byte
+		PubKeyEd  []byte
+		Stake     uint64
+		Stakes    []user.Stake
+	}
+
+	queryBid struct {
+		X         []byte
+		M         []byte
+		EndHeight uint64
+	}
+
+	// queryRoundResults is the winning block of a round, along with the
+	// certificate the consensus reached on it
+	queryRoundResults struct {
+		Block       *block.Block
+		Certificate *block.Certificate
+	}
+
+	nodeStatus struct {
+		rpcBus *rpcbus.RPCBus
+	}
+)
+
+// getQuery returns the version, the network and the synchronization status of
+// the node
+func (n nodeStatus) getQuery() *graphql.Field {
+	return &graphql.Field{
+		Type:    Node,
+		Resolve: n.resolve,
+	}
+}
+
+// getProvisionersQuery returns the provisioners, optionally restricted to the
+// ones with a stake active at a round
+func (n nodeStatus) getProvisionersQuery() *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewList(Provisioner),
+		Args: graphql.FieldConfigArgument{
+			nodeRoundArg: &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+		},
+		Resolve: n.resolveProvisioners,
+	}
+}
+
+// getBidListQuery returns the bids of the current round
+func (n nodeStatus) getBidListQuery() *graphql.Field {
+	return &graphql.Field{
+		Type:    graphql.NewList(Bid),
+		Resolve: n.resolveBidList,
+	}
+}
+
+// getCertificateQuery returns the certificate of the block at a height, or
+// the last certificate reached by the consensus if no height is given
+func (n nodeStatus) getCertificateQuery() *graphql.Field {
+	return &graphql.Field{
+		Type: Certificate,
+		Args: graphql.FieldConfigArgument{
+			nodeHeightArg: &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+		},
+		Resolve: n.resolveCertificate,
+	}
+}
+
+// getRoundResultsQuery returns the winning block and the certificate of a
+// round which is still to be finalized
+func (n nodeStatus) getRoundResultsQuery() *graphql.Field {
+	return &graphql.Field{
+		Type: RoundResults,
+		Args: graphql.FieldConfigArgument{
+			nodeRoundArg: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+		Resolve: n.resolveRoundResults,
+	}
+}
+
+func (n nodeStatus) resolve(p graphql.ResolveParams) (interface{}, error) {
+	status := queryNode{
+		Version: protocol.NodeVer.String(),
+		Network: config.Get().General.Network,
+	}
+
+	resp, err := n.rpcBus.Call(topics.GetSyncProgress, rpcbus.NewRequest(&node.EmptyRequest{}), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	status.SyncProgress = resp.(*node.SyncProgressResponse).Progress
+
+	resp, err = n.rpcBus.Call(topics.GetPeerCount, rpcbus.EmptyRequest(), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	status.Peers = resp.(int)
+
+	return status, nil
+}
+
+func (n nodeStatus) resolveProvisioners(p graphql.ResolveParams) (interface{}, error) {
+	round, hasRound := p.Args[nodeRoundArg].(int)
+	if hasRound && round < 0 {
+		return nil, errors.New("invalid round")
+	}
+
+	resp, err := n.rpcBus.Call(topics.GetProvisioners, rpcbus.EmptyRequest(), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	provisioners := resp.(user.Provisioners)
+	members := make([]queryProvisioner, 0, len(provisioners.Set))
+	for i := range provisioners.Set {
+		m, err := provisioners.MemberAt(i)
+		if err != nil {
+			return nil, err
+		}
+
+		qp := queryProvisioner{PubKeyBLS: m.PublicKeyBLS, PubKeyEd: m.PublicKeyEd, Stakes: m.Stakes}
+		for _, stake := range m.Stakes {
+			if !hasRound || (stake.StartHeight <= uint64(round) && uint64(round) <= stake.EndHeight) {
+				qp.Stake += stake.Amount
+			}
+		}
+
+		// only the provisioners active at the round
+		if hasRound && qp.Stake == 0 {
+			continue
+		}
+
+		members = append(members, qp)
+	}
+
+	return members, nil
+}
+
+func (n nodeStatus) resolveBidList(p graphql.ResolveParams) (interface{}, error) {
+	resp, err := n.rpcBus.Call(topics.GetBidList, rpcbus.EmptyRequest(), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	bidList := resp.(user.BidList)
+	bids := make([]queryBid, len(bidList))
+	for i, bid := range bidList {
+		x, m := bid.X, bid.M
+		bids[i] = queryBid{X: x[:], M: m[:], EndHeight: bid.EndHeight}
+	}
+
+	return bids, nil
+}
+
+func (n nodeStatus) resolveCertificate(p graphql.ResolveParams) (interface{}, error) {
+	height, ok := p.Args[nodeHeightArg].(int)
+	if !ok {
+		resp, err := n.rpcBus.Call(topics.GetLastCertificate, rpcbus.EmptyRequest(), 5*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		buf := resp.(bytes.Buffer)
+		cert := block.EmptyCertificate()
+		if err := message.UnmarshalCertificate(&buf, cert); err != nil {
+			return nil, err
+		}
+
+		return cert, nil
+	}
+
+	if height < 0 {
+		return nil, errors.New("invalid height")
+	}
+
+	// Retrieve DB conn from context
+	db, ok := p.Context.Value("database").(database.DB)
+	if !ok {
+		return nil, errors.New("context does not store database conn")
+	}
+
+	var header *block.Header
+	err := db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(uint64(height))
+		if err != nil {
+			return err
+		}
+
+		header, err = t.FetchBlockHeader(hash)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return header.Certificate, nil
+}
+
+func (n nodeStatus) resolveRoundResults(p graphql.ResolveParams) (interface{}, error) {
+	round, ok := p.Args[nodeRoundArg].(int)
+	if !ok || round < 0 {
+		return nil, errors.New("invalid round")
+	}
+
+	params := new(bytes.Buffer)
+	if err := binary.Write(params, binary.LittleEndian, uint64(round)); err != nil {
+		return nil, err
+	}
+
+	resp, err := n.rpcBus.Call(topics.GetRoundResults, rpcbus.NewRequest(*params), 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := resp.(bytes.Buffer)
+	results := queryRoundResults{Block: block.NewBlock(), Certificate: block.EmptyCertificate()}
+	if err := message.UnmarshalBlock(&buf, results.Block); err != nil {
+		return nil, err
+	}
+
+	if err := message.UnmarshalCertificate(&buf, results.Certificate); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
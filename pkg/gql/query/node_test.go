@@ -0,0 +1,126 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-protobuf/autogen/go/node"
+	"github.com/dusk-network/dusk-wallet/v2/block"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func execNodeQuery(t *testing.T, rb *rpcbus.RPCBus, query string) map[string]interface{} {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: NewRoot(rb).Query})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: query,
+		Context:       context.WithValue(context.Background(), "database", db),
+	})
+
+	if !assert.Empty(t, result.Errors) {
+		t.FailNow()
+	}
+
+	out, err := json.Marshal(result.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(out, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestNode(t *testing.T) {
+	rb := rpcbus.New()
+	serveRPC(t, rb, topics.GetSyncProgress, &node.SyncProgressResponse{Progress: 50}, nil)
+	serveRPC(t, rb, topics.GetPeerCount, 3, nil)
+
+	data := execNodeQuery(t, rb, `{ node { version peers syncProgress } }`)
+	assert.Equal(t, map[string]interface{}{
+		"version":      protocol.NodeVer.String(),
+		"peers":        float64(3),
+		"syncProgress": float64(50),
+	}, data["node"])
+}
+
+func TestProvisioners(t *testing.T) {
+	rb := rpcbus.New()
+	p, _ := consensus.MockProvisioners(2)
+	serveRPC(t, rb, topics.GetProvisioners, *p, nil)
+
+	data := execNodeQuery(t, rb, `{
+		active: provisioners(round: 100) { stake stakes { amount endheight } }
+		expired: provisioners(round: 20000) { stake }
+	}`)
+
+	stake := map[string]interface{}{
+		"stake":  "500",
+		"stakes": []interface{}{map[string]interface{}{"amount": "500", "endheight": float64(10000)}},
+	}
+	assert.Equal(t, []interface{}{stake, stake}, data["active"])
+	assert.Empty(t, data["expired"])
+}
+
+func TestBidList(t *testing.T) {
+	rb := rpcbus.New()
+	bidList := consensus.MockBidList(2)
+	serveRPC(t, rb, topics.GetBidList, bidList, nil)
+
+	data := execNodeQuery(t, rb, `{ bidList { x endheight } }`)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"x": hex.EncodeToString(bidList[0].X[:]), "endheight": float64(10)},
+		map[string]interface{}{"x": hex.EncodeToString(bidList[1].X[:]), "endheight": float64(10)},
+	}, data["bidList"])
+}
+
+func TestCertificate(t *testing.T) {
+	cert := block.EmptyCertificate()
+	cert.Step = 3
+	buf := new(bytes.Buffer)
+	if err := message.MarshalCertificate(buf, cert); err != nil {
+		t.Fatal(err)
+	}
+
+	rb := rpcbus.New()
+	serveRPC(t, rb, topics.GetLastCertificate, *buf, nil)
+
+	// The last certificate, not yet stored with its block
+	data := execNodeQuery(t, rb, `{ certificate { step } }`)
+	assert.Equal(t, map[string]interface{}{"step": float64(3)}, data["certificate"])
+
+	// The certificate of a stored block
+	var header *block.Header
+	_ = db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(1)
+		if err != nil {
+			return err
+		}
+
+		header, err = t.FetchBlockHeader(hash)
+		return err
+	})
+
+	data = execNodeQuery(t, rb, `{ certificate(height: 1) { steponebatchedsig step } }`)
+	assert.Equal(t, map[string]interface{}{
+		"steponebatchedsig": hex.EncodeToString(header.Certificate.StepOneBatchedSig),
+		"step":              float64(header.Certificate.Step),
+	}, data["certificate"])
+}
@@ -1,29 +1,59 @@
 package query
 
 import (
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/graphql-go/graphql"
 )
 
 type Root struct {
-	Query *graphql.Object
+	Query        *graphql.Object
+	Mutation     *graphql.Object
+	Subscription *graphql.Object
 }
 
 func NewRoot(rpcBus *rpcbus.RPCBus) *Root {
 
 	m := mempool{rpcBus: rpcBus}
+	rt := roundTraces{rpcBus: rpcBus}
+	c := committee{rpcBus: rpcBus}
+	mu := mutations{rpcBus: rpcBus}
+	n := nodeStatus{rpcBus: rpcBus}
 
 	root := Root{
 		Query: graphql.NewObject(
 			graphql.ObjectConfig{
 				Name: "Query",
 				Fields: graphql.Fields{
-					"blocks":       blocks{}.getQuery(),
-					"transactions": transactions{}.getQuery(),
-					"mempool":      m.getQuery(),
+					"blocks":                 blocks{}.getQuery(),
+					"blocksConnection":       blocks{}.getConnectionQuery(),
+					"transactions":           transactions{}.getQuery(),
+					"transactionsConnection": transactions{}.getConnectionQuery(),
+					"mempool":                m.getQuery(),
+					"roundtraces":            rt.getQuery(),
+					"roundtimeline":          rt.getTimelineQuery(),
+					"committee":              c.getQuery(),
+					"nextselection":          c.getNextSelectionQuery(),
+					"node":                   n.getQuery(),
+					"provisioners":           n.getProvisionersQuery(),
+					"bidList":                n.getBidListQuery(),
+					"certificate":            n.getCertificateQuery(),
+					"roundResults":           n.getRoundResultsQuery(),
 				},
 			},
 		),
+		Mutation: graphql.NewObject(
+			graphql.ObjectConfig{
+				Name: "Mutation",
+				Fields: graphql.Fields{
+					"submitTransaction": mu.getSubmitTransaction(),
+					"transfer":          mu.getTransfer(),
+					"bid":               mu.getConsensusTx(topics.SendBidTx),
+					"stake":             mu.getConsensusTx(topics.SendStakeTx),
+				},
+			},
+		),
+		Subscription: NewSubscription(),
 	}
 	return &root
 }
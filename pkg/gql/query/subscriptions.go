@@ -0,0 +1,225 @@
+package query
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-wallet/v2/block"
+	core "github.com/dusk-network/dusk-wallet/v2/transactions"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	minHeightArg = "minheight"
+	minTxsArg    = "mintxs"
+	pubKeyArg    = "pubkey"
+	minRoundArg  = "minround"
+	syncedArg    = "synced"
+)
+
+// File purpose is to define the fields of the Subscription root. Each field is
+// resolved against the payload of the eventbus message triggering it, and
+// resolves to null when the message does not match the field arguments, so
+// that no notification is sent.
+
+type (
+	// Trigger describes when a subscription field is resolved
+	Trigger struct {
+		// Topic is the eventbus topic whose messages trigger the field
+		Topic topics.Topic
+		// Once is set for the fields which are notified at most once, after
+		// which the subscription is complete
+		Once bool
+	}
+
+	// queryRoundUpdate wraps the consensus.RoundUpdate fields relevant to
+	// graphql clients
+	queryRoundUpdate struct {
+		Round        uint64
+		Hash         []byte
+		Seed         []byte
+		Provisioners int
+		Bidders      int
+		TotalStake   string
+	}
+)
+
+// Triggers maps the fields of the Subscription root to what triggers them
+var Triggers = map[string]Trigger{
+	"newBlock":     {Topic: topics.AcceptedBlock},
+	"newMempoolTx": {Topic: topics.VerifiedTx},
+	"txConfirmed":  {Topic: topics.AcceptedBlock, Once: true},
+	"roundUpdate":  {Topic: topics.RoundUpdate},
+	"syncProgress": {Topic: topics.SyncProgress},
+}
+
+// NewSubscription creates the Subscription root of the schema
+func NewSubscription() *graphql.Object {
+	return graphql.NewObject(
+		graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"newBlock": &graphql.Field{
+					Type: Block,
+					Args: graphql.FieldConfigArgument{
+						minHeightArg: &graphql.ArgumentConfig{
+							Type: graphql.Int,
+						},
+						minTxsArg: &graphql.ArgumentConfig{
+							Type: graphql.Int,
+						},
+					},
+					Resolve: resolveNewBlock,
+				},
+				"newMempoolTx": &graphql.Field{
+					Type: Transaction,
+					Args: graphql.FieldConfigArgument{
+						pubKeyArg: &graphql.ArgumentConfig{
+							Type: graphql.String,
+						},
+					},
+					Resolve: resolveNewMempoolTx,
+				},
+				"txConfirmed": &graphql.Field{
+					Type: Transaction,
+					Args: graphql.FieldConfigArgument{
+						txidArg: &graphql.ArgumentConfig{
+							Type: graphql.NewNonNull(graphql.String),
+						},
+					},
+					Resolve: resolveTxConfirmed,
+				},
+				"roundUpdate": &graphql.Field{
+					Type: RoundUpdate,
+					Args: graphql.FieldConfigArgument{
+						minRoundArg: &graphql.ArgumentConfig{
+							Type: graphql.Int,
+						},
+					},
+					Resolve: resolveRoundUpdate,
+				},
+				"syncProgress": &graphql.Field{
+					Type: graphql.Float,
+					Args: graphql.FieldConfigArgument{
+						syncedArg: &graphql.ArgumentConfig{
+							Type:         graphql.Boolean,
+							DefaultValue: false,
+						},
+					},
+					Resolve: resolveSyncProgress,
+				},
+			},
+		},
+	)
+}
+
+func resolveNewBlock(p graphql.ResolveParams) (interface{}, error) {
+	blk, ok := p.Source.(block.Block)
+	if !ok {
+		return nil, errors.New("invalid source block")
+	}
+
+	if minHeight, ok := p.Args[minHeightArg].(int); ok && blk.Header.Height < uint64(minHeight) {
+		return nil, nil
+	}
+
+	if minTxs, ok := p.Args[minTxsArg].(int); ok && len(blk.Txs) < minTxs {
+		return nil, nil
+	}
+
+	return &blk, nil
+}
+
+func resolveNewMempoolTx(p graphql.ResolveParams) (interface{}, error) {
+	tx, ok := p.Source.(core.Transaction)
+	if !ok {
+		return nil, errors.New("invalid source tx")
+	}
+
+	if encoded, ok := p.Args[pubKeyArg].(string); ok {
+		pubKey, err := hex.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.New("invalid pubkey")
+		}
+
+		if !hasOutput(tx, pubKey) {
+			return nil, nil
+		}
+	}
+
+	return newQueryTx(tx, nil)
+}
+
+func resolveTxConfirmed(p graphql.ResolveParams) (interface{}, error) {
+	blk, ok := p.Source.(block.Block)
+	if !ok {
+		return nil, errors.New("invalid source block")
+	}
+
+	txid, err := hex.DecodeString(p.Args[txidArg].(string))
+	if err != nil {
+		return nil, errors.New("invalid txid")
+	}
+
+	for _, tx := range blk.Txs {
+		id, err := tx.CalculateHash()
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Equal(id, txid) {
+			return newQueryTx(tx, blk.Header.Hash)
+		}
+	}
+
+	return nil, nil
+}
+
+func resolveRoundUpdate(p graphql.ResolveParams) (interface{}, error) {
+	ru, ok := p.Source.(consensus.RoundUpdate)
+	if !ok {
+		return nil, errors.New("invalid source round update")
+	}
+
+	if minRound, ok := p.Args[minRoundArg].(int); ok && ru.Round < uint64(minRound) {
+		return nil, nil
+	}
+
+	return queryRoundUpdate{
+		Round:        ru.Round,
+		Hash:         ru.Hash,
+		Seed:         ru.Seed,
+		Provisioners: ru.P.SubsetSizeAt(ru.Round),
+		Bidders:      len(ru.BidList),
+		TotalStake:   strconv.FormatUint(ru.P.ActiveWeight(ru.Round), 10),
+	}, nil
+}
+
+func resolveSyncProgress(p graphql.ResolveParams) (interface{}, error) {
+	progress, ok := p.Source.(float32)
+	if !ok {
+		return nil, errors.New("invalid source progress")
+	}
+
+	// the synchronization is complete once the progress reaches 100%
+	if synced, _ := p.Args[syncedArg].(bool); synced && progress < 100 {
+		return nil, nil
+	}
+
+	return float64(progress), nil
+}
+
+// hasOutput checks if any output of the tx is sent to the given public key
+func hasOutput(tx core.Transaction, pubKey []byte) bool {
+	for _, output := range tx.StandardTx().Outputs {
+		if bytes.Equal(output.PubKey.P.Bytes(), pubKey) {
+			return true
+		}
+	}
+
+	return false
+}
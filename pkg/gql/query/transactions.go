@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"strconv"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
@@ -20,6 +21,8 @@ const (
 	txidArg   = "txid"
 	txidsArg  = "txids"
 	txlastArg = "last"
+	txTypeArg = "txtype"
+	minFeeArg = "minfee"
 )
 
 // queryTx is a data-wrapper for all core.transaction relevant fields that
@@ -51,6 +54,14 @@ type (
 type transactions struct {
 }
 
+// txFilter holds the filters of the transactions connection
+type txFilter struct {
+	hasType bool
+	txType  core.TxType
+	minFee  uint64
+	pubKey  []byte
+}
+
 // newQueryTx constructs query tx data from core tx and block hash
 func newQueryTx(tx core.Transaction, blockHash []byte) (queryTx, error) {
 
@@ -114,6 +125,32 @@ func (t transactions) getQuery() *graphql.Field {
 	}
 }
 
+// getConnectionQuery returns the txs of the chain, in the order of the blocks
+// and then of the txs within a block, as a Relay-style connection
+func (t transactions) getConnectionQuery() *graphql.Field {
+	return &graphql.Field{
+		Type: TransactionConnection,
+		Args: connectionArgs(graphql.FieldConfigArgument{
+			fromHeightArg: &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			toHeightArg: &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			txTypeArg: &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			minFeeArg: &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+			pubKeyArg: &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+		}),
+		Resolve: t.resolveConnection,
+	}
+}
+
 func (t transactions) resolve(p graphql.ResolveParams) (interface{}, error) {
 
 	// Retrieve DB conn from context
@@ -241,3 +278,227 @@ func (b transactions) fetchLastTxs(db database.DB, count int) ([]queryTx, error)
 
 	return txs, err
 }
+
+func (t transactions) resolveConnection(p graphql.ResolveParams) (interface{}, error) {
+
+	// Retrieve DB conn from context
+	db, ok := p.Context.Value("database").(database.DB)
+	if !ok {
+		return nil, errors.New("context does not store database conn")
+	}
+
+	pg, err := newPage(p, "tx", 2)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := newTxFilter(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var c queryConnection
+	err = db.View(func(tr database.Transaction) error {
+		tip, err := tr.FetchCurrentHeight()
+		if err != nil {
+			return err
+		}
+
+		from, to, err := heightRange(p, tip)
+		if err != nil {
+			return err
+		}
+
+		c.count = func() (int, error) {
+			return t.countTxs(db, from, to, filter)
+		}
+
+		var hasNext, hasPrevious bool
+		if pg.backward {
+			c.Edges, hasPrevious, hasNext, err = t.fetchTxsBackward(tr, from, to, pg, filter)
+		} else {
+			c.Edges, hasNext, hasPrevious, err = t.fetchTxsForward(tr, from, to, pg, filter)
+		}
+
+		c.PageInfo = newPageInfo(c.Edges, hasNext, hasPrevious)
+		return err
+	})
+
+	return c, err
+}
+
+// fetchTxsForward fetches the page of txs following the cursor. It also
+// returns whether more txs follow, and whether the page follows a cursor
+func (t transactions) fetchTxsForward(tr database.Transaction, from, to uint64, pg page, filter txFilter) ([]queryEdge, bool, bool, error) {
+	edges := make([]queryEdge, 0, pg.size+1)
+	startHeight, startIdx, hasPrevious := from, 0, false
+	if pg.cursor != nil && pg.cursor[0] >= from {
+		startHeight, startIdx, hasPrevious = pg.cursor[0], int(pg.cursor[1])+1, true
+	}
+
+	for height := startHeight; height <= to && len(edges) <= pg.size; height++ {
+		hash, blockTxs, err := fetchTxsAt(tr, height)
+		if err != nil {
+			return nil, false, false, err
+		}
+
+		i := 0
+		if height == startHeight {
+			i = startIdx
+		}
+
+		for ; i < len(blockTxs) && len(edges) <= pg.size; i++ {
+			edge, ok, err := newTxEdge(blockTxs[i], hash, height, i, filter)
+			if err != nil {
+				return nil, false, false, err
+			}
+			if ok {
+				edges = append(edges, edge)
+			}
+		}
+	}
+
+	if len(edges) > pg.size {
+		return edges[:pg.size], true, hasPrevious, nil
+	}
+
+	return edges, false, hasPrevious, nil
+}
+
+// fetchTxsBackward fetches the page of txs preceding the cursor. It also
+// returns whether more txs precede, and whether the page precedes a cursor
+func (t transactions) fetchTxsBackward(tr database.Transaction, from, to uint64, pg page, filter txFilter) ([]queryEdge, bool, bool, error) {
+	edges := make([]queryEdge, 0, pg.size+1)
+	endHeight, endIdx, hasNext := to, -1, false
+	if pg.cursor != nil && pg.cursor[0] <= to {
+		endHeight, endIdx, hasNext = pg.cursor[0], int(pg.cursor[1]), true
+	}
+
+	for height := endHeight; height >= from && len(edges) <= pg.size; height-- {
+		hash, blockTxs, err := fetchTxsAt(tr, height)
+		if err != nil {
+			return nil, false, false, err
+		}
+
+		i := len(blockTxs) - 1
+		if height == endHeight && endIdx >= 0 && endIdx <= len(blockTxs) {
+			i = endIdx - 1
+		}
+
+		for ; i >= 0 && len(edges) <= pg.size; i-- {
+			edge, ok, err := newTxEdge(blockTxs[i], hash, height, i, filter)
+			if err != nil {
+				return nil, false, false, err
+			}
+			if ok {
+				edges = append(edges, edge)
+			}
+		}
+
+		if height == 0 {
+			break
+		}
+	}
+
+	hasPrevious := len(edges) > pg.size
+	if hasPrevious {
+		edges = edges[:pg.size]
+	}
+
+	// edges were collected in reverse order
+	for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+		edges[i], edges[j] = edges[j], edges[i]
+	}
+
+	return edges, hasPrevious, hasNext, nil
+}
+
+// countTxs counts the txs matching the filter within a range of heights
+func (t transactions) countTxs(db database.DB, from, to uint64, filter txFilter) (int, error) {
+	count := 0
+	err := db.View(func(tr database.Transaction) error {
+		for height := from; height <= to; height++ {
+			_, blockTxs, err := fetchTxsAt(tr, height)
+			if err != nil {
+				return err
+			}
+
+			for _, tx := range blockTxs {
+				if filter.match(tx) {
+					count++
+				}
+			}
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+func fetchTxsAt(tr database.Transaction, height uint64) ([]byte, []core.Transaction, error) {
+	hash, err := tr.FetchBlockHashByHeight(height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blockTxs, err := tr.FetchBlockTxs(hash)
+	return hash, blockTxs, err
+}
+
+// newTxEdge creates the edge of a tx, if it matches the filter
+func newTxEdge(tx core.Transaction, blockHash []byte, height uint64, idx int, filter txFilter) (queryEdge, bool, error) {
+	if !filter.match(tx) {
+		return queryEdge{}, false, nil
+	}
+
+	d, err := newQueryTx(tx, blockHash)
+	if err != nil {
+		return queryEdge{}, false, err
+	}
+
+	return queryEdge{Cursor: encodeCursor("tx", height, uint64(idx)), Node: d}, true, nil
+}
+
+func newTxFilter(p graphql.ResolveParams) (txFilter, error) {
+	var f txFilter
+	if txType, ok := p.Args[txTypeArg].(int); ok {
+		if txType < 0 || txType > 255 {
+			return f, errors.New("invalid txtype")
+		}
+		f.hasType, f.txType = true, core.TxType(txType)
+	}
+
+	if minFee, ok := p.Args[minFeeArg].(string); ok {
+		fee, err := strconv.ParseUint(minFee, 10, 64)
+		if err != nil {
+			return f, errors.New("invalid minfee")
+		}
+		f.minFee = fee
+	}
+
+	if encoded, ok := p.Args[pubKeyArg].(string); ok {
+		pubKey, err := hex.DecodeString(encoded)
+		if err != nil {
+			return f, errors.New("invalid pubkey")
+		}
+		f.pubKey = pubKey
+	}
+
+	return f, nil
+}
+
+func (f txFilter) match(tx core.Transaction) bool {
+	if f.hasType && tx.Type() != f.txType {
+		return false
+	}
+
+	if f.minFee > 0 && tx.StandardTx().Fee.BigInt().Uint64() < f.minFee {
+		return false
+	}
+
+	if f.pubKey != nil && !hasOutput(tx, f.pubKey) {
+		return false
+	}
+
+	return true
+}
@@ -183,3 +183,76 @@ func TestTxSize(t *testing.T) {
 	`
 	assertQuery(t, query, response)
 }
+
+func TestTransactionsConnection(t *testing.T) {
+	query := `
+		{
+		  range: transactionsConnection(fromheight: 1, first: 1) {
+			edges {
+			  cursor
+			  node {
+				txtype
+			  }
+			}
+			pageInfo {
+			  hasNextPage
+			  hasPreviousPage
+			}
+			totalCount
+		  },
+		  last: transactionsConnection(last: 2, before: "dHg6Mjow") {
+			edges {
+			  cursor
+			}
+			pageInfo {
+			  hasNextPage
+			  hasPreviousPage
+			}
+		  },
+		  standard: transactionsConnection(txtype: 3) {
+			totalCount
+		  },
+		  other: transactionsConnection(txtype: 1) {
+			edges {
+			  cursor
+			}
+			totalCount
+		  }
+		}
+	`
+
+	response := `
+		{
+		  "data": {
+			"range": {
+			  "edges": [
+				{"cursor": "dHg6MTow", "node": {"txtype": "3"}}
+			  ],
+			  "pageInfo": {
+				"hasNextPage": true,
+				"hasPreviousPage": false
+			  },
+			  "totalCount": 2
+			},
+			"last": {
+			  "edges": [
+				{"cursor": "dHg6MDow"},
+				{"cursor": "dHg6MTow"}
+			  ],
+			  "pageInfo": {
+				"hasNextPage": true,
+				"hasPreviousPage": false
+			  }
+			},
+			"standard": {
+			  "totalCount": 3
+			},
+			"other": {
+			  "edges": [],
+			  "totalCount": 0
+			}
+		  }
+		}
+	`
+	assertQuery(t, query, response)
+}
@@ -81,6 +81,70 @@ var Transaction = graphql.NewObject(
 	},
 )
 
+var BlockConnection = newConnection(Block)
+
+var TransactionConnection = newConnection(Transaction)
+
+var RoundUpdate = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "RoundUpdate",
+		Fields: graphql.Fields{
+			"round": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"hash": &graphql.Field{
+				Type: Hex,
+			},
+			"seed": &graphql.Field{
+				Type: Hex,
+			},
+			"provisioners": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"bidders": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"totalstake": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
+var MutationCode = graphql.NewEnum(
+	graphql.EnumConfig{
+		Name: "MutationCode",
+		Values: graphql.EnumValueConfigMap{
+			CodeOK:                 &graphql.EnumValueConfig{Value: CodeOK},
+			CodeInvalidArgument:    &graphql.EnumValueConfig{Value: CodeInvalidArgument},
+			CodeUnauthorized:       &graphql.EnumValueConfig{Value: CodeUnauthorized},
+			CodeUnavailable:        &graphql.EnumValueConfig{Value: CodeUnavailable},
+			CodeAlreadyExists:      &graphql.EnumValueConfig{Value: CodeAlreadyExists},
+			CodeFeeTooLow:          &graphql.EnumValueConfig{Value: CodeFeeTooLow},
+			CodeCoinbaseNotAllowed: &graphql.EnumValueConfig{Value: CodeCoinbaseNotAllowed},
+			CodeWalletNotLoaded:    &graphql.EnumValueConfig{Value: CodeWalletNotLoaded},
+			CodeRejected:           &graphql.EnumValueConfig{Value: CodeRejected},
+		},
+	},
+)
+
+var MutationResult = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "MutationResult",
+		Fields: graphql.Fields{
+			"txid": &graphql.Field{
+				Type: Hex,
+			},
+			"code": &graphql.Field{
+				Type: MutationCode,
+			},
+			"message": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
 var Output = graphql.NewObject(
 	graphql.ObjectConfig{
 		Name: "Output",
@@ -103,6 +167,213 @@ var Input = graphql.NewObject(
 	},
 )
 
+var RoundTrace = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "RoundTrace",
+		Fields: graphql.Fields{
+			"round": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"events": &graphql.Field{
+				Type: graphql.NewList(TraceEvent),
+			},
+		},
+	},
+)
+
+var TraceEvent = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "TraceEvent",
+		Fields: graphql.Fields{
+			"kind": &graphql.Field{
+				Type:    graphql.String,
+				Resolve: resolveTraceKind,
+			},
+			"step": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"time": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"hash": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
+var Committee = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Committee",
+		Fields: graphql.Fields{
+			"round": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"step": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"size": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"totalweight": &graphql.Field{
+				Type: graphql.String,
+			},
+			"members": &graphql.Field{
+				Type: graphql.NewList(CommitteeMember),
+			},
+			"ismember": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+			"votes": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
+var CommitteeMember = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "CommitteeMember",
+		Fields: graphql.Fields{
+			"pubkey": &graphql.Field{
+				Type: Hex,
+			},
+			"votes": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
+var Selection = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Selection",
+		Fields: graphql.Fields{
+			"round": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"step": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"votes": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
+var Node = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Node",
+		Fields: graphql.Fields{
+			"version": &graphql.Field{
+				Type: graphql.String,
+			},
+			"network": &graphql.Field{
+				Type: graphql.String,
+			},
+			"peers": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"syncProgress": &graphql.Field{
+				Type: graphql.Float,
+			},
+		},
+	},
+)
+
+var Provisioner = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Provisioner",
+		Fields: graphql.Fields{
+			"pubkeybls": &graphql.Field{
+				Type: Hex,
+			},
+			"pubkeyed": &graphql.Field{
+				Type: Hex,
+			},
+			"stake": &graphql.Field{
+				Type: graphql.String,
+			},
+			"stakes": &graphql.Field{
+				Type: graphql.NewList(Stake),
+			},
+		},
+	},
+)
+
+var Stake = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Stake",
+		Fields: graphql.Fields{
+			"amount": &graphql.Field{
+				Type: graphql.String,
+			},
+			"startheight": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"endheight": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
+var Bid = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Bid",
+		Fields: graphql.Fields{
+			"x": &graphql.Field{
+				Type: Hex,
+			},
+			"m": &graphql.Field{
+				Type: Hex,
+			},
+			"endheight": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
+var Certificate = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Certificate",
+		Fields: graphql.Fields{
+			"steponebatchedsig": &graphql.Field{
+				Type: Hex,
+			},
+			"steptwobatchedsig": &graphql.Field{
+				Type: Hex,
+			},
+			"step": &graphql.Field{
+				Type: graphql.Int,
+			},
+			// the committees are bitsets of the provisioners which voted
+			"steponecommittee": &graphql.Field{
+				Type: graphql.String,
+			},
+			"steptwocommittee": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
+var RoundResults = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "RoundResults",
+		Fields: graphql.Fields{
+			"block": &graphql.Field{
+				Type: Block,
+			},
+			"certificate": &graphql.Field{
+				Type: Certificate,
+			},
+		},
+	},
+)
+
 var Hex = graphql.NewScalar(graphql.ScalarConfig{
 	Name:        "Hex",
 	Description: "Hex scalar type represents a byte array",
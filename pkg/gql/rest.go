@@ -0,0 +1,445 @@
+package gql
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/auth"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/query"
+	"github.com/go-chi/render"
+	"github.com/graphql-go/graphql"
+)
+
+// File purpose is to define the REST API of the node. Each endpoint is served
+// by a fixed GraphQL operation, so that both APIs return the same data. The
+// OpenAPI document of the API is generated from the routes
+
+const (
+	endpointREST    = "/v1/"
+	endpointOpenAPI = "/v1/openapi.json"
+
+	blockFields = `header { height hash version prevblockhash seed txroot timestamp } transactions { txid txtype size }`
+	txFields    = `txid txtype blockhash size output { pubkey } input { keyimage }`
+)
+
+var errNotFound = errors.New("not found")
+
+type (
+	// restParam is a path parameter of a route
+	restParam struct {
+		name        string
+		description string
+	}
+
+	// restRoute maps an endpoint of the REST API onto a GraphQL operation
+	restRoute struct {
+		method  string
+		path    string
+		summary string
+		param   *restParam
+		// scope is the scope the API key needs
+		scope auth.Scope
+		// body describes the JSON fields of the request body, if any
+		body map[string]string
+		// responses maps the status codes to their description
+		responses map[int]string
+
+		// operation builds the GraphQL request of an API request from the
+		// path parameter and the body. A returned error is a bad request
+		operation func(param string, body map[string]string) (data, error)
+		// result picks the response out of the data of the operation
+		result func(d map[string]interface{}) (interface{}, int, error)
+	}
+
+	// restError is the body of the failed responses
+	restError struct {
+		Error string `json:"error"`
+	}
+)
+
+// restRoutes are the routes of the REST API
+var restRoutes = []restRoute{
+	{
+		method:  http.MethodGet,
+		path:    "/v1/blocks/{id}",
+		summary: "Returns a block and the ids of its transactions",
+		scope:   auth.ScopeChain,
+		param:   &restParam{name: "id", description: "height or hex-encoded hash of the block"},
+		responses: map[int]string{
+			http.StatusOK:         "the block",
+			http.StatusBadRequest: "invalid height or hash",
+			http.StatusNotFound:   "unknown block",
+		},
+		operation: blockOperation,
+		result:    firstItem("blocks"),
+	},
+	{
+		method:  http.MethodGet,
+		path:    "/v1/tx/{id}",
+		summary: "Returns an accepted transaction",
+		scope:   auth.ScopeChain,
+		param:   &restParam{name: "id", description: "hex-encoded id of the transaction"},
+		responses: map[int]string{
+			http.StatusOK:         "the transaction",
+			http.StatusBadRequest: "invalid id",
+			http.StatusNotFound:   "unknown transaction",
+		},
+		operation: func(id string, _ map[string]string) (data, error) {
+			if _, err := hex.DecodeString(id); err != nil {
+				return data{}, errors.New("invalid id")
+			}
+
+			return data{
+				Query:     `query ($txid: String!) { transactions(txid: $txid) { ` + txFields + ` } }`,
+				Variables: map[string]interface{}{"txid": id},
+			}, nil
+		},
+		result: firstItem("transactions"),
+	},
+	{
+		method:  http.MethodGet,
+		path:    "/v1/mempool",
+		summary: "Returns the transactions of the mempool",
+		scope:   auth.ScopeChain,
+		responses: map[int]string{
+			http.StatusOK: "the transactions",
+		},
+		operation: func(string, map[string]string) (data, error) {
+			return data{Query: `{ mempool(txid: "") { txid txtype size } }`}, nil
+		},
+		result: field("mempool"),
+	},
+	{
+		method:  http.MethodGet,
+		path:    "/v1/status",
+		summary: "Returns the status of the node and the tip of its chain",
+		scope:   auth.ScopeChain,
+		responses: map[int]string{
+			http.StatusOK: "the status",
+		},
+		operation: func(string, map[string]string) (data, error) {
+			return data{Query: `{
+				node { version network peers syncProgress }
+				tip: blocks(height: -1) { header { height hash timestamp } }
+			}`}, nil
+		},
+		result: func(d map[string]interface{}) (interface{}, int, error) {
+			status := map[string]interface{}{"node": d["node"]}
+			if tip, ok := d["tip"].([]interface{}); ok && len(tip) > 0 {
+				status["tip"] = tip[0].(map[string]interface{})["header"]
+			}
+
+			return status, http.StatusOK, nil
+		},
+	},
+	{
+		method:  http.MethodPost,
+		path:    "/v1/tx",
+		summary: "Submits a transaction to the mempool",
+		scope:   auth.ScopeMempool,
+		body: map[string]string{
+			"rawtx": "hex-encoded marshaled transaction",
+		},
+		responses: map[int]string{
+			http.StatusOK:                  "the transaction is accepted by the mempool",
+			http.StatusBadRequest:          "invalid transaction",
+			http.StatusConflict:            "the transaction is already in the mempool",
+			http.StatusUnprocessableEntity: "the transaction is rejected by the mempool",
+			http.StatusServiceUnavailable:  "the mempool is unavailable",
+		},
+		operation: func(_ string, body map[string]string) (data, error) {
+			rawTx, ok := body["rawtx"]
+			if !ok {
+				return data{}, errors.New("missing rawtx")
+			}
+
+			return data{
+				Query:     `mutation ($rawtx: String!) { submitTransaction(rawtx: $rawtx) { txid code message } }`,
+				Variables: map[string]interface{}{"rawtx": rawTx},
+			}, nil
+		},
+		result: submitResult,
+	},
+}
+
+// blockOperation looks a block up either by height or by hash. The heights
+// are looked up through the connection, as it ignores the heights above the
+// tip instead of failing
+func blockOperation(id string, _ map[string]string) (data, error) {
+	if height, err := strconv.ParseUint(id, 10, 64); err == nil {
+		if height > math.MaxInt32 {
+			return data{}, errors.New("invalid height")
+		}
+
+		return data{
+			Query: `query ($height: Int!) {
+				blocks: blocksConnection(fromheight: $height, toheight: $height, first: 1) {
+					edges { node { ` + blockFields + ` } }
+				}
+			}`,
+			Variables: map[string]interface{}{"height": int(height)},
+		}, nil
+	}
+
+	if _, err := hex.DecodeString(id); err != nil {
+		return data{}, errors.New("invalid height or hash")
+	}
+
+	return data{
+		Query:     `query ($hash: String!) { blocks(hash: $hash) { ` + blockFields + ` } }`,
+		Variables: map[string]interface{}{"hash": id},
+	}, nil
+}
+
+// field returns the result picking a field of the data
+func field(name string) func(map[string]interface{}) (interface{}, int, error) {
+	return func(d map[string]interface{}) (interface{}, int, error) {
+		return d[name], http.StatusOK, nil
+	}
+}
+
+// firstItem returns the result picking the first item of a list field, or
+// the first node of a connection
+func firstItem(name string) func(map[string]interface{}) (interface{}, int, error) {
+	return func(d map[string]interface{}) (interface{}, int, error) {
+		items, _ := d[name].([]interface{})
+		if c, ok := d[name].(map[string]interface{}); ok {
+			edges, _ := c["edges"].([]interface{})
+			for _, edge := range edges {
+				items = append(items, edge.(map[string]interface{})["node"])
+			}
+		}
+
+		if len(items) == 0 {
+			return nil, http.StatusNotFound, errNotFound
+		}
+
+		return items[0], http.StatusOK, nil
+	}
+}
+
+// submitResult maps the code of the mutation onto the status of the response
+func submitResult(d map[string]interface{}) (interface{}, int, error) {
+	result, _ := d["submitTransaction"].(map[string]interface{})
+	code, _ := result["code"].(string)
+	message, _ := result["message"].(string)
+
+	switch code {
+	case query.CodeOK:
+		return map[string]interface{}{"txid": result["txid"]}, http.StatusOK, nil
+	case query.CodeInvalidArgument:
+		return nil, http.StatusBadRequest, errors.New(message)
+	case query.CodeAlreadyExists:
+		return nil, http.StatusConflict, errors.New(message)
+	case query.CodeUnavailable:
+		return nil, http.StatusServiceUnavailable, errors.New(message)
+	default:
+		return nil, http.StatusUnprocessableEntity, errors.New(message)
+	}
+}
+
+// match checks whether a route serves a path, and returns its parameter
+func (rt restRoute) match(path string) (string, bool) {
+	if rt.param == nil {
+		return "", path == rt.path
+	}
+
+	prefix := strings.TrimSuffix(rt.path, "{"+rt.param.name+"}")
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+
+	param := strings.TrimPrefix(path, prefix)
+	return param, len(param) > 0 && !strings.Contains(param, "/")
+}
+
+// handleREST serves the requests of the REST API
+func handleREST(schema *graphql.Schema, limits queryLimits, w http.ResponseWriter, r *http.Request, db database.DB, cache *query.BlockCache, id auth.Identity) {
+
+	if r.URL.Path == endpointOpenAPI && r.Method == http.MethodGet {
+		render.JSON(w, r, openAPI(restRoutes))
+		return
+	}
+
+	// Find the route
+	var route *restRoute
+	var param string
+	pathFound := false
+	for i, rt := range restRoutes {
+		p, ok := rt.match(r.URL.Path)
+		if !ok {
+			continue
+		}
+
+		pathFound = true
+		if rt.method == r.Method {
+			route, param = &restRoutes[i], p
+			break
+		}
+	}
+
+	if route == nil {
+		if pathFound {
+			restFailure(w, r, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+
+		restFailure(w, r, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	if !id.Scopes.Has(route.scope) {
+		restFailure(w, r, http.StatusForbidden, fmt.Errorf("the API key needs the %s scope", route.scope))
+		return
+	}
+
+	body := make(map[string]string)
+	if route.body != nil {
+		content, err := ioutil.ReadAll(r.Body)
+		defer r.Body.Close()
+		if err != nil {
+			restFailure(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := json.Unmarshal(content, &body); err != nil {
+			restFailure(w, r, http.StatusBadRequest, errors.New("invalid body"))
+			return
+		}
+	}
+
+	req, err := route.operation(param, body)
+	if err != nil {
+		restFailure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := queryContext(limits, db, cache, id)
+	defer cancel()
+
+	result := executeQuery(ctx, schema, limits, req)
+	if len(result.Errors) > 0 {
+		log.Warnf("Execute REST query error(s): %v", result.Errors)
+		restFailure(w, r, errorStatus(result.Errors[0].Message), errors.New(result.Errors[0].Message))
+		return
+	}
+
+	d, _ := result.Data.(map[string]interface{})
+	response, status, err := route.result(d)
+	if err != nil {
+		restFailure(w, r, status, err)
+		return
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, response)
+}
+
+// errorStatus returns the status of a response to an operation which failed
+func errorStatus(message string) int {
+	switch message {
+	case database.ErrBlockNotFound.Error(), database.ErrTxNotFound.Error():
+		return http.StatusNotFound
+	case errQueryTimeout.Error():
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func restFailure(w http.ResponseWriter, r *http.Request, status int, err error) {
+	render.Status(r, status)
+	render.JSON(w, r, restError{Error: err.Error()})
+}
+
+// openAPI generates the OpenAPI document of the routes
+func openAPI(routes []restRoute) map[string]interface{} {
+	paths := make(map[string]interface{})
+	for _, rt := range routes {
+		responses := make(map[string]interface{})
+		for status, description := range rt.responses {
+			responses[strconv.Itoa(status)] = map[string]interface{}{"description": description}
+		}
+
+		// The failures of the authentication and of the rate limiter
+		responses[strconv.Itoa(http.StatusUnauthorized)] = map[string]interface{}{"description": "missing or invalid API key"}
+		responses[strconv.Itoa(http.StatusForbidden)] = map[string]interface{}{"description": "the API key lacks the " + string(rt.scope) + " scope"}
+		responses[strconv.Itoa(http.StatusTooManyRequests)] = map[string]interface{}{"description": "rate limit reached"}
+
+		operation := map[string]interface{}{
+			"summary":     rt.summary,
+			"description": "Requires the `" + string(rt.scope) + "` scope.",
+			"responses":   responses,
+		}
+
+		if rt.param != nil {
+			operation["parameters"] = []interface{}{
+				map[string]interface{}{
+					"name":        rt.param.name,
+					"in":          "path",
+					"required":    true,
+					"description": rt.param.description,
+					"schema":      map[string]interface{}{"type": "string"},
+				},
+			}
+		}
+
+		if rt.body != nil {
+			properties := make(map[string]interface{})
+			required := make([]string, 0, len(rt.body))
+			for name, description := range rt.body {
+				properties[name] = map[string]interface{}{"type": "string", "description": description}
+				required = append(required, name)
+			}
+			sort.Strings(required)
+
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"type":       "object",
+							"properties": properties,
+							"required":   required,
+						},
+					},
+				},
+			}
+		}
+
+		methods, ok := paths[rt.path].(map[string]interface{})
+		if !ok {
+			methods = make(map[string]interface{})
+			paths[rt.path] = methods
+		}
+		methods[strings.ToLower(rt.method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "Dusk node REST API",
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"bearer": map[string]interface{}{"type": "http", "scheme": "bearer"},
+				"apiKey": map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+		"security": []interface{}{
+			map[string]interface{}{"bearer": []string{}},
+			map[string]interface{}{"apiKey": []string{}},
+		},
+	}
+}
@@ -0,0 +1,179 @@
+package gql
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/auth"
+	"github.com/dusk-network/dusk-blockchain/pkg/gql/query"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/dusk-network/dusk-wallet/v2/block"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+type restFixture struct {
+	schema *graphql.Schema
+	db     database.DB
+	chain  []*block.Block
+}
+
+func newRESTFixture(t *testing.T, rb *rpcbus.RPCBus) restFixture {
+	_, db := lite.CreateDBConnection()
+
+	chain := make([]*block.Block, 0)
+	for height := uint64(0); height < 2; height++ {
+		blk := helper.RandomBlock(t, height, 1)
+		hash, err := blk.CalculateHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		blk.Header.Hash = hash
+		chain = append(chain, blk)
+	}
+
+	err := db.Update(func(t database.Transaction) error {
+		for _, blk := range chain {
+			if err := t.StoreBlock(blk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := query.NewRoot(rb)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: root.Query, Mutation: root.Mutation})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return restFixture{schema: &schema, db: db, chain: chain}
+}
+
+func (f restFixture) do(method, path string, body []byte) (int, map[string]interface{}) {
+	return f.doAs(auth.Identity{Scopes: auth.AllScopes()}, method, path, body)
+}
+
+func (f restFixture) doAs(id auth.Identity, method, path string, body []byte) (int, map[string]interface{}) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, path, bytes.NewReader(body))
+	handleREST(f.schema, queryLimits{}, w, r, f.db, nil, id)
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w.Code, response
+}
+
+func TestRESTBlocks(t *testing.T) {
+	f := newRESTFixture(t, rpcbus.New())
+	defer f.db.Close()
+
+	hash := hex.EncodeToString(f.chain[1].Header.Hash)
+
+	status, blk := f.do(http.MethodGet, "/v1/blocks/1", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, hash, blk["header"].(map[string]interface{})["hash"])
+	assert.Len(t, blk["transactions"], 1)
+
+	status, blk = f.do(http.MethodGet, "/v1/blocks/"+hash, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(1), blk["header"].(map[string]interface{})["height"])
+
+	status, _ = f.do(http.MethodGet, "/v1/blocks/5", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = f.do(http.MethodGet, "/v1/blocks/"+hex.EncodeToString(make([]byte, 32)), nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, response := f.do(http.MethodGet, "/v1/blocks/xyz", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "invalid height or hash", response["error"])
+}
+
+func TestRESTRouting(t *testing.T) {
+	f := newRESTFixture(t, rpcbus.New())
+	defer f.db.Close()
+
+	status, _ := f.do(http.MethodPost, "/v1/blocks/1", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+
+	status, _ = f.do(http.MethodGet, "/v1/blocks/1/txs", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = f.do(http.MethodGet, "/v1/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// The routes need their scope
+	readOnly := auth.Identity{Name: "explorer", Scopes: auth.NewScopes(auth.ScopeChain)}
+	status, _ = f.doAs(readOnly, http.MethodGet, "/v1/blocks/1", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, response := f.doAs(readOnly, http.MethodPost, "/v1/tx", []byte(`{"rawtx": "00"}`))
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, "the API key needs the mempool scope", response["error"])
+}
+
+func TestRESTSubmitTx(t *testing.T) {
+	rb := rpcbus.New()
+	reqChan := make(chan rpcbus.Request, 1)
+	if err := rb.Register(topics.SendMempoolTx, reqChan); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first submission is accepted, the second one already exists
+	go func() {
+		r := <-reqChan
+		r.RespChan <- rpcbus.Response{Resp: []byte{1, 2}}
+		r = <-reqChan
+		r.RespChan <- rpcbus.Response{Err: mempool.ErrAlreadyExists}
+	}()
+
+	f := newRESTFixture(t, rb)
+	defer f.db.Close()
+
+	buf := new(bytes.Buffer)
+	if err := message.MarshalTx(buf, helper.RandomStandardTx(t, false)); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(map[string]string{"rawtx": hex.EncodeToString(buf.Bytes())})
+
+	status, response := f.do(http.MethodPost, "/v1/tx", body)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "0102", response["txid"])
+
+	status, _ = f.do(http.MethodPost, "/v1/tx", body)
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, _ = f.do(http.MethodPost, "/v1/tx", []byte(`{"rawtx": "zz"}`))
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = f.do(http.MethodPost, "/v1/tx", []byte(`{}`))
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestOpenAPI(t *testing.T) {
+	doc := openAPI(restRoutes)
+	paths := doc["paths"].(map[string]interface{})
+	assert.Len(t, paths, len(restRoutes))
+
+	for _, rt := range restRoutes {
+		methods := paths[rt.path].(map[string]interface{})
+		operation, ok := methods[map[string]string{http.MethodGet: "get", http.MethodPost: "post"}[rt.method]]
+		if assert.True(t, ok, rt.path) {
+			assert.Equal(t, rt.summary, operation.(map[string]interface{})["summary"])
+		}
+	}
+}
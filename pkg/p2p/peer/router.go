@@ -47,6 +47,7 @@ func (m *messageRouter) CanRoute(topic topics.Topic) bool {
 		topics.Score,
 		topics.Reduction,
 		topics.Agreement,
+		topics.Evidence,
 		topics.RoundResults:
 		return true
 	}
@@ -0,0 +1,160 @@
+package message
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/header"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/msg"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util"
+)
+
+// ErrNotEquivocation is returned when verifying an Evidence whose headers do
+// not prove a provisioner voted twice in the same round and step
+var ErrNotEquivocation = errors.New("headers do not prove an equivocation")
+
+// Evidence proves that a provisioner signed two different block hashes for
+// the same round and step. It is self-contained: anyone knowing the
+// provisioner BLS public key can verify it, regardless of the committee
+// the votes were cast in.
+type Evidence struct {
+	// Category is the topic of the conflicting votes (i.e. Reduction or
+	// Agreement). The vote signatures do not cover it, so it is only
+	// informative: a provisioner never signs two block hashes for the same
+	// round and step, whatever the kind of the votes
+	Category  topics.Topic
+	First     header.Header
+	FirstSig  []byte
+	Second    header.Header
+	SecondSig []byte
+}
+
+// NewEvidence creates an Evidence out of two conflicting votes
+func NewEvidence(category topics.Topic, first header.Header, firstSig []byte, second header.Header, secondSig []byte) Evidence {
+	return Evidence{
+		Category:  category,
+		First:     first,
+		FirstSig:  firstSig,
+		Second:    second,
+		SecondSig: secondSig,
+	}
+}
+
+func (e Evidence) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Category.String())
+	sb.WriteString(" first: ")
+	sb.WriteString(e.First.String())
+	sb.WriteString(" second hash='")
+	sb.WriteString(util.StringifyBytes(e.Second.BlockHash))
+	sb.WriteString("'")
+	return sb.String()
+}
+
+// State returns the header of the first vote. It is used to comply to
+// consensus.Message
+func (e Evidence) State() header.Header {
+	return e.First
+}
+
+// Sender returns the BLS public key of the equivocating provisioner
+func (e Evidence) Sender() []byte {
+	return e.First.Sender()
+}
+
+// Verify checks that the category is the one of a vote, that both headers
+// belong to the same provisioner, round and step, that they vote for different
+// block hashes and that both signatures are valid
+func (e Evidence) Verify() error {
+	if e.Category != topics.Reduction && e.Category != topics.Agreement {
+		return ErrNotEquivocation
+	}
+
+	if !bytes.Equal(e.First.PubKeyBLS, e.Second.PubKeyBLS) ||
+		e.First.Round != e.Second.Round ||
+		e.First.Step != e.Second.Step ||
+		bytes.Equal(e.First.BlockHash, e.Second.BlockHash) {
+		return ErrNotEquivocation
+	}
+
+	if err := VerifyVote(e.First, e.FirstSig); err != nil {
+		return err
+	}
+
+	return VerifyVote(e.Second, e.SecondSig)
+}
+
+// VerifyVote verifies the BLS signature of a consensus vote
+func VerifyVote(hdr header.Header, signature []byte) error {
+	r := new(bytes.Buffer)
+	if err := header.MarshalSignableVote(r, hdr); err != nil {
+		return err
+	}
+
+	// we make a copy of the signature because the crypto package apparently mutates the byte array when
+	// Compressing/Decompressing a point
+	// see https://github.com/dusk-network/dusk-crypto/issues/16
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	return msg.VerifyBLSSignature(hdr.PubKeyBLS, r.Bytes(), sig)
+}
+
+// UnmarshalEvidenceMessage unmarshals a network inbound Evidence
+func UnmarshalEvidenceMessage(r *bytes.Buffer, m SerializableMessage) error {
+	e := Evidence{}
+	if err := UnmarshalEvidence(r, &e); err != nil {
+		return err
+	}
+
+	m.SetPayload(e)
+	return nil
+}
+
+// UnmarshalEvidence unmarshals the buffer into an Evidence
+func UnmarshalEvidence(r *bytes.Buffer, e *Evidence) error {
+	var category uint8
+	if err := encoding.ReadUint8(r, &category); err != nil {
+		return err
+	}
+	e.Category = topics.Topic(category)
+
+	if err := header.Unmarshal(r, &e.First); err != nil {
+		return err
+	}
+
+	e.FirstSig = make([]byte, 33)
+	if err := encoding.ReadBLS(r, e.FirstSig); err != nil {
+		return err
+	}
+
+	if err := header.Unmarshal(r, &e.Second); err != nil {
+		return err
+	}
+
+	e.SecondSig = make([]byte, 33)
+	return encoding.ReadBLS(r, e.SecondSig)
+}
+
+// MarshalEvidence marshals an Evidence into a buffer
+func MarshalEvidence(r *bytes.Buffer, e Evidence) error {
+	if err := encoding.WriteUint8(r, uint8(e.Category)); err != nil {
+		return err
+	}
+
+	if err := header.Marshal(r, e.First); err != nil {
+		return err
+	}
+
+	if err := encoding.WriteBLS(r, e.FirstSig); err != nil {
+		return err
+	}
+
+	if err := header.Marshal(r, e.Second); err != nil {
+		return err
+	}
+
+	return encoding.WriteBLS(r, e.SecondSig)
+}
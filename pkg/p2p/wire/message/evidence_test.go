@@ -0,0 +1,58 @@
+package message_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	crypto "github.com/dusk-network/dusk-crypto/hash"
+	"github.com/dusk-network/dusk-wallet/v2/key"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvidenceUnMarshal(t *testing.T) {
+	e := newEvidence(t)
+	assert.NoError(t, e.Verify())
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, message.MarshalEvidence(buf, e))
+
+	e2 := message.Evidence{}
+	assert.NoError(t, message.UnmarshalEvidence(buf, &e2))
+	assert.Equal(t, e, e2)
+	assert.NoError(t, e2.Verify())
+}
+
+func TestEvidenceVerify(t *testing.T) {
+	// votes for the same hash are no equivocation
+	e := newEvidence(t)
+	e.Second, e.SecondSig = e.First, e.FirstSig
+	assert.Equal(t, message.ErrNotEquivocation, e.Verify())
+
+	// votes in different steps are no equivocation
+	e = newEvidence(t)
+	e.Second.Step++
+	assert.Equal(t, message.ErrNotEquivocation, e.Verify())
+
+	// only the votes can equivocate
+	e = newEvidence(t)
+	e.Category = topics.Score
+	assert.Equal(t, message.ErrNotEquivocation, e.Verify())
+
+	// swapped signatures
+	e = newEvidence(t)
+	e.FirstSig, e.SecondSig = e.SecondSig, e.FirstSig
+	assert.Error(t, e.Verify())
+}
+
+func newEvidence(t *testing.T) message.Evidence {
+	k, _ := key.NewRandConsensusKeys()
+	keys := []key.ConsensusKeys{k}
+	hash1, _ := crypto.RandEntropy(32)
+	hash2, _ := crypto.RandEntropy(32)
+
+	r1 := message.MockReduction(hash1, 1, 2, keys)
+	r2 := message.MockReduction(hash2, 1, 2, keys)
+	return message.NewEvidence(topics.Reduction, r1.State(), r1.SignedHash, r2.State(), r2.SignedHash)
+}
@@ -136,6 +136,8 @@ func Unmarshal(b *bytes.Buffer) (Message, error) {
 		err = UnmarshalReductionMessage(b, msg)
 	case topics.Agreement:
 		err = UnmarshalAgreementMessage(b, msg)
+	case topics.Evidence:
+		err = UnmarshalEvidenceMessage(b, msg)
 	}
 
 	if err != nil {
@@ -222,6 +224,9 @@ func marshalMessage(topic topics.Topic, payload interface{}, buf *bytes.Buffer)
 	case topics.Agreement:
 		agreement := payload.(Agreement)
 		err = MarshalAgreement(buf, agreement)
+	case topics.Evidence:
+		evidence := payload.(Evidence)
+		err = MarshalEvidence(buf, evidence)
 	default:
 		return fmt.Errorf("unsupported marshalling of message type: %v", topic.String())
 	}
@@ -95,6 +95,26 @@ const (
 	// Cross-network RPCBus topics
 	GetRoundResults
 	GetCandidate
+
+	// Topics appended after the cross-network ones. The byte of a topic is
+	// part of the wire format, so new topics are only ever appended here
+	DisconnectedBlock
+	GetRebroadcastTxs
+	ReplacedTx
+	GetConsensusTimeouts
+	SetConsensusFaults
+	GetRoundTraces
+	RoundProgress
+	GetCommittee
+	GetNextSelection
+	VerifiedTx
+	SyncProgress
+	GetProvisioners
+	GetBidList
+	GetPeerCount
+	ConfigReloaded
+	ReloadConfig
+	Evidence
 )
 
 type topicBuf struct {
@@ -171,6 +191,23 @@ var Topics = [...]topicBuf{
 	topicBuf{StopProfile, *(bytes.NewBuffer([]byte{byte(StopProfile)})), "stopprofile"},
 	topicBuf{GetRoundResults, *(bytes.NewBuffer([]byte{byte(GetRoundResults)})), "getroundresults"},
 	topicBuf{GetCandidate, *(bytes.NewBuffer([]byte{byte(GetCandidate)})), "getcandidate"},
+	topicBuf{DisconnectedBlock, *(bytes.NewBuffer([]byte{byte(DisconnectedBlock)})), "disconnectedblock"},
+	topicBuf{GetRebroadcastTxs, *(bytes.NewBuffer([]byte{byte(GetRebroadcastTxs)})), "getrebroadcasttxs"},
+	topicBuf{ReplacedTx, *(bytes.NewBuffer([]byte{byte(ReplacedTx)})), "replacedtx"},
+	topicBuf{GetConsensusTimeouts, *(bytes.NewBuffer([]byte{byte(GetConsensusTimeouts)})), "getconsensustimeouts"},
+	topicBuf{SetConsensusFaults, *(bytes.NewBuffer([]byte{byte(SetConsensusFaults)})), "setconsensusfaults"},
+	topicBuf{GetRoundTraces, *(bytes.NewBuffer([]byte{byte(GetRoundTraces)})), "getroundtraces"},
+	topicBuf{RoundProgress, *(bytes.NewBuffer([]byte{byte(RoundProgress)})), "roundprogress"},
+	topicBuf{GetCommittee, *(bytes.NewBuffer([]byte{byte(GetCommittee)})), "getcommittee"},
+	topicBuf{GetNextSelection, *(bytes.NewBuffer([]byte{byte(GetNextSelection)})), "getnextselection"},
+	topicBuf{VerifiedTx, *(bytes.NewBuffer([]byte{byte(VerifiedTx)})), "verifiedtx"},
+	topicBuf{SyncProgress, *(bytes.NewBuffer([]byte{byte(SyncProgress)})), "syncprogress"},
+	topicBuf{GetProvisioners, *(bytes.NewBuffer([]byte{byte(GetProvisioners)})), "getprovisioners"},
+	topicBuf{GetBidList, *(bytes.NewBuffer([]byte{byte(GetBidList)})), "getbidlist"},
+	topicBuf{GetPeerCount, *(bytes.NewBuffer([]byte{byte(GetPeerCount)})), "getpeercount"},
+	topicBuf{ConfigReloaded, *(bytes.NewBuffer([]byte{byte(ConfigReloaded)})), "configreloaded"},
+	topicBuf{ReloadConfig, *(bytes.NewBuffer([]byte{byte(ReloadConfig)})), "reloadconfig"},
+	topicBuf{Evidence, *(bytes.NewBuffer([]byte{byte(Evidence)})), "evidence"},
 }
 
 func checkConsistency(topics []topicBuf) {
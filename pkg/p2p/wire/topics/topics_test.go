@@ -53,3 +53,81 @@ func TestCheckConsistency(t *testing.T) {
 	tpcs = append(tpcs, Topics[3])
 	assert.Panics(t, func() { checkConsistency(tpcs) })
 }
+
+// wireBytes are the bytes of the topics exchanged with the peers and the
+// other processes. They must never change, or the node could not talk to the
+// nodes running a previous version
+var wireBytes = map[Topic]byte{
+	Version:               0,
+	VerAck:                1,
+	Ping:                  2,
+	Pong:                  3,
+	Addr:                  4,
+	GetAddr:               5,
+	GetData:               6,
+	GetBlocks:             7,
+	GetHeaders:            8,
+	Tx:                    9,
+	Block:                 10,
+	AcceptedBlock:         11,
+	Headers:               12,
+	MemPool:               13,
+	Inv:                   14,
+	Certificate:           15,
+	RoundResults:          16,
+	Candidate:             17,
+	Score:                 18,
+	Reduction:             19,
+	Agreement:             20,
+	Gossip:                21,
+	NotFound:              22,
+	Unknown:               23,
+	Reject:                24,
+	Initialization:        25,
+	RoundUpdate:           26,
+	BestScore:             27,
+	Quit:                  28,
+	Log:                   29,
+	Monitor:               30,
+	Test:                  31,
+	StepVotes:             32,
+	ScoreEvent:            33,
+	Generation:            34,
+	Restart:               35,
+	StopConsensus:         36,
+	IntermediateBlock:     37,
+	HighestSeen:           38,
+	ValidCandidateHash:    39,
+	GetLastBlock:          40,
+	GetMempoolTxs:         41,
+	GetMempoolTxsBySize:   42,
+	VerifyCandidateBlock:  43,
+	GetLastCertificate:    44,
+	SendMempoolTx:         45,
+	GetMempoolView:        46,
+	CreateWallet:          47,
+	CreateFromSeed:        48,
+	LoadWallet:            49,
+	SendBidTx:             50,
+	SendStakeTx:           51,
+	SendStandardTx:        52,
+	GetBalance:            53,
+	GetUnconfirmedBalance: 54,
+	GetAddress:            55,
+	GetTxHistory:          56,
+	AutomateConsensusTxs:  57,
+	GetSyncProgress:       58,
+	IsWalletLoaded:        59,
+	RebuildChain:          60,
+	ClearWalletDatabase:   61,
+	StartProfile:          62,
+	StopProfile:           63,
+	GetRoundResults:       64,
+	GetCandidate:          65,
+}
+
+func TestWireBytes(t *testing.T) {
+	for topic, b := range wireBytes {
+		assert.Equal(t, b, byte(topic), topic.String())
+	}
+}
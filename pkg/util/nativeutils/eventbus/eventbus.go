@@ -1,6 +1,7 @@
 package eventbus
 
 import (
+	"sync/atomic"
 	"time"
 
 	lg "github.com/sirupsen/logrus"
@@ -24,6 +25,9 @@ type (
 	EventBus struct {
 		listeners       *listenerMap
 		defaultListener *multiListener
+		// forwarding is the number of messages being forwarded to the
+		// default listeners
+		forwarding int32
 	}
 )
 
@@ -34,3 +38,9 @@ func New() *EventBus {
 		defaultListener: newMultiListener(),
 	}
 }
+
+// Idle returns true if no message is being forwarded to the default
+// listeners
+func (bus *EventBus) Idle() bool {
+	return atomic.LoadInt32(&bus.forwarding) == 0
+}
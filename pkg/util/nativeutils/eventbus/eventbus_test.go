@@ -109,6 +109,30 @@ func TestDefaultListener(t *testing.T) {
 	}
 }
 
+// Test that the bus is not idle while a message is being forwarded to the
+// default listeners
+func TestIdle(t *testing.T) {
+	eb := New()
+	release := make(chan struct{})
+	eb.AddDefaultTopic(topics.Reject)
+	eb.SubscribeDefault(NewCallbackListener(func(message.Message) error {
+		<-release
+		return nil
+	}))
+
+	assert.True(t, eb.Idle())
+	eb.Publish(topics.Reject, message.New(topics.Reject, *(bytes.NewBufferString("pluto"))))
+	assert.False(t, eb.Idle())
+
+	close(release)
+	for deadline := time.Now().Add(time.Second); !eb.Idle(); {
+		if time.Now().After(deadline) {
+			t.Fatal("the bus did not become idle")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
 //****************
 // SETUP FUNCTIONS
 //****************
@@ -1,6 +1,8 @@
 package eventbus
 
 import (
+	"sync/atomic"
+
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/sirupsen/logrus"
@@ -21,7 +23,11 @@ func (bus *EventBus) Publish(topic topics.Topic, m message.Message) {
 	}).Traceln("publishing on the eventbus")
 
 	// first serve the default topic listeners as they are most likely to need more time to process topics
-	go bus.defaultListener.Forward(topic, m)
+	atomic.AddInt32(&bus.forwarding, 1)
+	go func() {
+		bus.defaultListener.Forward(topic, m)
+		atomic.AddInt32(&bus.forwarding, -1)
+	}()
 
 	if listeners := bus.listeners.Load(topic); listeners != nil {
 		for _, listener := range listeners {
@@ -29,6 +29,22 @@ func (c Cluster) Equal(other Cluster) bool {
 	return true
 }
 
+// Copy returns a Cluster with the same elements and occurrences, which can be
+// modified independently
+func (c Cluster) Copy() Cluster {
+	set := make(Set, len(c.Set))
+	copy(set, c.Set)
+	elems := make(map[string]int, len(c.elements))
+	for k, v := range c.elements {
+		elems[k] = v
+	}
+
+	return Cluster{
+		Set:      set,
+		elements: elems,
+	}
+}
+
 // Size returns the amount of elements in the cluster
 func (c Cluster) TotalOccurrences() int {
 	size := 0